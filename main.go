@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"os"
 	"sync"
 	"time"
 
@@ -15,34 +19,90 @@ import (
 	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
 	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/signals"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	rbacinformers "k8s.io/client-go/informers/rbac/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog"
 )
 
 var (
-	masterURL               string
-	kubeconfig              string
-	grafanaURL              string
-	prometheusListenAddress string
-	prometheusPath          string
-	resyncDeletePeriod      time.Duration
-	resyncPeriod            time.Duration
+	masterURL                    string
+	kubeconfig                   string
+	grafanaURL                   string
+	grafanaCredentialsSecret     string
+	grafanaCredentialsDir        string
+	grafanaTLSSecret             string
+	grafanaTLSDir                string
+	grafanaTLSServerName         string
+	grafanaTLSInsecureSkipVerify bool
+	grafanaConnectTimeout        time.Duration
+	grafanaReadTimeout           time.Duration
+	grafanaTimeout               time.Duration
+	grafanaMaxRetries            int
+	grafanaRetryBackoff          time.Duration
+	grafanaRetryMaxBackoff       time.Duration
+	grafanaQPS                   float64
+	grafanaBurst                 int
+	grafanaMaxInFlight           int
+	prometheusListenAddress      string
+	prometheusPath               string
+	resyncDeletePeriod           time.Duration
+	resyncPeriod                 time.Duration
+	folderRBACPermissions        string
+	rolloutAnnotations           bool
+	eventAnnotations             bool
+	eventAnnotationsNamespaces   string
+	eventAnnotationsReasons      string
+	eventAnnotationsSelector     string
 )
 
 func init() {
 	flag.StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
 	flag.StringVar(&masterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
 	flag.StringVar(&grafanaURL, "grafana", "http://grafana", "The address of the Grafana server.")
+	flag.StringVar(&grafanaCredentialsSecret, "grafana-credentials-secret", "", "<namespace>/<name> of a Secret containing Grafana credentials.  The Secret must have either a token key or username and password keys.")
+	flag.StringVar(&grafanaCredentialsDir, "grafana-credentials-dir", "", "Path to a directory containing Grafana credentials files, such as a mounted Secret.  The directory must have either a token file or username and password files.")
+	flag.StringVar(&grafanaTLSSecret, "grafana-tls-secret", "", "<namespace>/<name> of a Secret containing TLS material for connecting to Grafana.  Reads ca.crt, tls.crt and tls.key.")
+	flag.StringVar(&grafanaTLSDir, "grafana-tls-dir", "", "Path to a directory containing TLS material for connecting to Grafana, such as a mounted Secret.  Reads ca.crt, tls.crt and tls.key.")
+	flag.StringVar(&grafanaTLSServerName, "grafana-tls-server-name", "", "Overrides the server name used for SNI and to validate the Grafana certificate.")
+	flag.BoolVar(&grafanaTLSInsecureSkipVerify, "grafana-tls-insecure-skip-verify", false, "Skip validation of the Grafana server certificate.  Insecure.")
+	flag.DurationVar(&grafanaConnectTimeout, "grafana-connect-timeout", grafana.DefaultConnectTimeout, "Timeout for establishing a connection to Grafana, including the TLS handshake.")
+	flag.DurationVar(&grafanaReadTimeout, "grafana-read-timeout", time.Second*30, "Timeout for Grafana to respond once a request has been sent.  Pass 0s to disable.")
+	flag.DurationVar(&grafanaTimeout, "grafana-timeout", time.Minute, "Overall timeout for a single Grafana request.  Pass 0s to disable.")
+	flag.IntVar(&grafanaMaxRetries, "grafana-max-retries", 3, "Number of times a failed Grafana request is retried before giving up.  Only requests that are safe to repeat are retried.  Pass 0 to disable.")
+	flag.DurationVar(&grafanaRetryBackoff, "grafana-retry-backoff", time.Millisecond*500, "Initial backoff between Grafana retries.  Doubles with each retry and is randomly jittered.")
+	flag.DurationVar(&grafanaRetryMaxBackoff, "grafana-retry-max-backoff", time.Second*10, "Maximum backoff between Grafana retries.  Requests asking to Retry-After longer than this are not retried.")
+	flag.Float64Var(&grafanaQPS, "grafana-qps", 0, "Maximum sustained requests per second sent to Grafana, shared by all controllers.  0 is unlimited.")
+	flag.IntVar(&grafanaBurst, "grafana-burst", 0, "Number of requests that may be sent to Grafana at once before -grafana-qps applies.  Only used with -grafana-qps.  0 is a burst of 1.")
+	flag.IntVar(&grafanaMaxInFlight, "grafana-max-in-flight", 0, "Maximum concurrent requests to Grafana, shared by all controllers.  0 is unlimited.")
 	flag.StringVar(&prometheusListenAddress, "prometheus-listen-address", ":8080", "The address to listen on for Prometheus scrapes.")
 	flag.StringVar(&prometheusPath, "prometheus-path", "/metrics", "The path to publish Prometheus metrics to.")
 	flag.DurationVar(&resyncDeletePeriod, "resync-delete", time.Second*30, "Periodic interval in which to force resync deleted objects.  Pass 0s to disable.")
 	flag.DurationVar(&resyncPeriod, "resync", time.Second*30, "Periodic interval in which to force resync objects.")
+	flag.StringVar(&folderRBACPermissions, "folder-rbac-permissions", "", "Path to a YAML or JSON file mapping RoleBindings and ClusterRoleBindings to folder permissions.  When set the acl of every folder is replaced with the permissions granted in its namespace.")
+	flag.BoolVar(&rolloutAnnotations, "rollout-annotations", false, "Annotate the rollouts of Deployments, StatefulSets and DaemonSets labeled "+controllers.RolloutAnnotationsLabel+"=true in the default organization.")
+	flag.BoolVar(&eventAnnotations, "event-annotations", false, "Annotate Kubernetes Warning events in the default organization.")
+	flag.StringVar(&eventAnnotationsNamespaces, "event-annotations-namespaces", "", "Comma separated namespaces of the events to annotate.  Only these namespaces are watched.  Defaults to all namespaces.")
+	flag.StringVar(&eventAnnotationsReasons, "event-annotations-reasons", controllers.DefaultEventAnnotationReasons, "Comma separated reasons of the events to annotate.")
+	flag.StringVar(&eventAnnotationsSelector, "event-annotations-selector", "", "Label selector the objects events are about must match to be annotated.  Supports pods, nodes, deployments, replicasets, statefulsets and daemonsets.")
 
 	klog.InitFlags(nil)
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == migrateAlertNotificationsCommand {
+		if err := runMigrateAlertNotifications(os.Args[2:]); err != nil {
+			klog.Fatalf("Error migrating alert notifications: %s", err.Error())
+		}
+
+		return
+	}
+
 	klog.Info("Application Starting")
 
 	flag.Parse()
@@ -63,35 +123,213 @@ func main() {
 		klog.Fatalf("Error building kubernetes clientset: %s", err.Error())
 	}
 
-	grafanaClient := grafana.NewClient(grafanaURL)
+	stopCh := signals.SetupSignalHandler()
+
+	grafanaClientOptions, err := buildGrafanaClientOptions(kubeClient, stopCh)
+	if err != nil {
+		klog.Fatalf("Error building grafana client options: %s", err.Error())
+	}
+
+	grafanaClient, err := grafana.NewClient(grafanaURL, grafanaClientOptions)
+	if err != nil {
+		klog.Fatalf("Error building grafana client: %s", err.Error())
+	}
+
+	// detection is retried on first use if grafana is not reachable yet
+	if capabilities, err := grafanaClient.Capabilities(context.Background()); err != nil {
+		klog.Warningf("Unable to detect grafana version: %s", err.Error())
+	} else {
+		klog.Infof("Detected grafana version %q", capabilities.Version)
+	}
+
+	var rbacPermissions *controllers.RBACPermissions
+	var rbacInformerFactory kubeinformers.SharedInformerFactory
+	var rbacInformers rbacinformers.Interface
+
+	if folderRBACPermissions != "" {
+		rbacPermissions, err = controllers.LoadRBACPermissions(folderRBACPermissions)
+		if err != nil {
+			klog.Fatalf("Error loading folder rbac permissions: %s", err.Error())
+		}
+
+		rbacInformerFactory = kubeinformers.NewSharedInformerFactory(kubeClient, resyncPeriod)
+		rbacInformers = rbacInformerFactory.Rbac().V1()
+	}
+
 	informerFactory := informers.NewSharedInformerFactory(client, resyncPeriod)
 
+	// contact points read their secure settings from secrets
+	secretInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, resyncPeriod)
+
 	var allControllers []*controllers.Controller
 
 	allControllers = append(allControllers, controllers.NewDashboardController(client,
 		kubeClient,
 		grafanaClient,
 		informerFactory.Grafana().V1alpha1().Dashboards(),
-		informerFactory.Grafana().V1alpha1().Folders()))
+		informerFactory.Grafana().V1alpha1().Folders(),
+		informerFactory.Grafana().V1alpha1().Organizations(),
+		informerFactory.Grafana().V1alpha1().Teams(),
+		informerFactory.Grafana().V1alpha1().LibraryPanels()))
 
 	allControllers = append(allControllers, controllers.NewAlertNotificationController(client,
 		kubeClient,
 		grafanaClient,
-		informerFactory.Grafana().V1alpha1().AlertNotifications()))
+		informerFactory.Grafana().V1alpha1().AlertNotifications(),
+		informerFactory.Grafana().V1alpha1().Organizations()))
 
 	allControllers = append(allControllers, controllers.NewDataSourceController(client,
 		kubeClient,
 		grafanaClient,
-		informerFactory.Grafana().V1alpha1().DataSources()))
+		informerFactory.Grafana().V1alpha1().DataSources(),
+		informerFactory.Grafana().V1alpha1().Organizations()))
 
 	allControllers = append(allControllers, controllers.NewFolderController(client,
 		kubeClient,
 		grafanaClient,
-		informerFactory.Grafana().V1alpha1().Folders()))
+		informerFactory.Grafana().V1alpha1().Folders(),
+		informerFactory.Grafana().V1alpha1().Organizations(),
+		informerFactory.Grafana().V1alpha1().Teams(),
+		rbacPermissions,
+		rbacInformers))
 
-	stopCh := signals.SetupSignalHandler()
+	allControllers = append(allControllers, controllers.NewOrganizationController(client,
+		kubeClient,
+		grafanaClient,
+		informerFactory.Grafana().V1alpha1().Organizations()))
+
+	allControllers = append(allControllers, controllers.NewTeamController(client,
+		kubeClient,
+		grafanaClient,
+		informerFactory.Grafana().V1alpha1().Teams(),
+		informerFactory.Grafana().V1alpha1().Organizations()))
+
+	allControllers = append(allControllers, controllers.NewAlertRuleGroupController(client,
+		kubeClient,
+		grafanaClient,
+		informerFactory.Grafana().V1alpha1().AlertRuleGroups(),
+		informerFactory.Grafana().V1alpha1().Folders(),
+		informerFactory.Grafana().V1alpha1().DataSources(),
+		informerFactory.Grafana().V1alpha1().Organizations()))
+
+	allControllers = append(allControllers, controllers.NewContactPointController(client,
+		kubeClient,
+		grafanaClient,
+		informerFactory.Grafana().V1alpha1().ContactPoints(),
+		informerFactory.Grafana().V1alpha1().Organizations(),
+		secretInformerFactory.Core().V1().Secrets()))
+
+	allControllers = append(allControllers, controllers.NewMuteTimingController(client,
+		kubeClient,
+		grafanaClient,
+		informerFactory.Grafana().V1alpha1().MuteTimings(),
+		informerFactory.Grafana().V1alpha1().Organizations()))
+
+	allControllers = append(allControllers, controllers.NewNotificationPolicyController(client,
+		kubeClient,
+		grafanaClient,
+		informerFactory.Grafana().V1alpha1().NotificationPolicies(),
+		informerFactory.Grafana().V1alpha1().NotificationRoutes(),
+		informerFactory.Grafana().V1alpha1().ContactPoints(),
+		informerFactory.Grafana().V1alpha1().MuteTimings(),
+		informerFactory.Grafana().V1alpha1().Organizations()))
+
+	allControllers = append(allControllers, controllers.NewLibraryPanelController(client,
+		kubeClient,
+		grafanaClient,
+		informerFactory.Grafana().V1alpha1().LibraryPanels(),
+		informerFactory.Grafana().V1alpha1().Folders(),
+		informerFactory.Grafana().V1alpha1().Organizations()))
+
+	allControllers = append(allControllers, controllers.NewPlaylistController(client,
+		kubeClient,
+		grafanaClient,
+		informerFactory.Grafana().V1alpha1().Playlists(),
+		informerFactory.Grafana().V1alpha1().Dashboards(),
+		informerFactory.Grafana().V1alpha1().Organizations()))
+
+	allControllers = append(allControllers, controllers.NewAnnotationController(client,
+		kubeClient,
+		grafanaClient,
+		informerFactory.Grafana().V1alpha1().Annotations(),
+		informerFactory.Grafana().V1alpha1().Dashboards(),
+		informerFactory.Grafana().V1alpha1().Organizations()))
+
+	allControllers = append(allControllers, controllers.NewServiceAccountController(client,
+		kubeClient,
+		grafanaClient,
+		informerFactory.Grafana().V1alpha1().ServiceAccounts(),
+		informerFactory.Grafana().V1alpha1().Organizations()))
 
 	informerFactory.Start(stopCh)
+	secretInformerFactory.Start(stopCh)
+
+	if rbacInformerFactory != nil {
+		rbacInformerFactory.Start(stopCh)
+	}
+
+	if rolloutAnnotations {
+		kubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, resyncPeriod,
+			kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+				options.LabelSelector = controllers.RolloutAnnotationsLabel + "=true"
+			}))
+
+		allControllers = append(allControllers, controllers.NewDeploymentRolloutController(kubeClient,
+			grafanaClient,
+			kubeInformerFactory.Apps().V1().Deployments()))
+
+		allControllers = append(allControllers, controllers.NewStatefulSetRolloutController(kubeClient,
+			grafanaClient,
+			kubeInformerFactory.Apps().V1().StatefulSets()))
+
+		allControllers = append(allControllers, controllers.NewDaemonSetRolloutController(kubeClient,
+			grafanaClient,
+			kubeInformerFactory.Apps().V1().DaemonSets()))
+
+		kubeInformerFactory.Start(stopCh)
+	}
+
+	if eventAnnotations {
+		filter, err := controllers.NewEventAnnotationFilter(eventAnnotationsNamespaces, eventAnnotationsReasons, eventAnnotationsSelector)
+		if err != nil {
+			klog.Fatalf("Error parsing event annotation filter: %s", err.Error())
+		}
+
+		// one set of informers per namespace so only the selected namespaces are watched
+		namespaces := filter.Namespaces
+		if len(namespaces) == 0 {
+			namespaces = []string{metav1.NamespaceAll}
+		}
+
+		var eventInformerFactories []kubeinformers.SharedInformerFactory
+		var eventInformers []coreinformers.EventInformer
+		var objectInformerFactories []kubeinformers.SharedInformerFactory
+
+		for _, namespace := range namespaces {
+			eventInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, resyncPeriod,
+				kubeinformers.WithNamespace(namespace),
+				kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+					options.FieldSelector = "type=" + corev1.EventTypeWarning
+				}))
+
+			eventInformerFactories = append(eventInformerFactories, eventInformerFactory)
+			eventInformers = append(eventInformers, eventInformerFactory.Core().V1().Events())
+
+			objectInformerFactories = append(objectInformerFactories, kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, resyncPeriod,
+				kubeinformers.WithNamespace(namespace)))
+		}
+
+		allControllers = append(allControllers, controllers.NewEventAnnotationController(kubeClient,
+			grafanaClient,
+			eventInformers,
+			objectInformerFactories,
+			filter))
+
+		// factories only start the informers the controller asked for
+		for _, factory := range append(eventInformerFactories, objectInformerFactories...) {
+			factory.Start(stopCh)
+		}
+	}
 
 	var wg sync.WaitGroup
 
@@ -118,3 +356,72 @@ func main() {
 
 	wg.Wait()
 }
+
+func buildGrafanaClientOptions(kubeClient kubernetes.Interface, stopCh <-chan struct{}) (grafana.ClientOptions, error) {
+	options := grafana.ClientOptions{
+		ConnectTimeout: grafanaConnectTimeout,
+		ReadTimeout:    grafanaReadTimeout,
+		Timeout:        grafanaTimeout,
+		Retry: &grafana.RetryOptions{
+			MaxRetries:     grafanaMaxRetries,
+			InitialBackoff: grafanaRetryBackoff,
+			MaxBackoff:     grafanaRetryMaxBackoff,
+		},
+		QPS:         grafanaQPS,
+		Burst:       grafanaBurst,
+		MaxInFlight: grafanaMaxInFlight,
+	}
+
+	credentialsSource, err := buildSecretSource("grafana-credentials", grafanaCredentialsSecret, grafanaCredentialsDir, kubeClient, stopCh)
+	if err != nil {
+		return options, err
+	}
+
+	if credentialsSource != nil {
+		options.Credentials = grafana.NewCredentials(credentialsSource)
+	} else if u, err := url.Parse(grafanaURL); err == nil && u.User != nil {
+		klog.Warning("-grafana contains credentials.  Use -grafana-credentials-secret or -grafana-credentials-dir instead.")
+	}
+
+	tlsSource, err := buildSecretSource("grafana-tls", grafanaTLSSecret, grafanaTLSDir, kubeClient, stopCh)
+	if err != nil {
+		return options, err
+	}
+
+	if tlsSource != nil || grafanaTLSServerName != "" || grafanaTLSInsecureSkipVerify {
+		options.TLS = &grafana.TLSOptions{
+			Source:             tlsSource,
+			ServerName:         grafanaTLSServerName,
+			InsecureSkipVerify: grafanaTLSInsecureSkipVerify,
+		}
+	}
+
+	return options, nil
+}
+
+// buildSecretSource returns a secret source for a pair of -<prefix>-secret and -<prefix>-dir flags
+// or nil if neither is set.
+func buildSecretSource(prefix string, secret string, dir string, kubeClient kubernetes.Interface, stopCh <-chan struct{}) (grafana.SecretSource, error) {
+	if secret != "" && dir != "" {
+		return nil, fmt.Errorf("only one of -%s-secret and -%s-dir may be set", prefix, prefix)
+	}
+
+	if dir != "" {
+		return grafana.NewDirectorySecretSource(dir), nil
+	}
+
+	if secret != "" {
+		namespace, name, err := cache.SplitMetaNamespaceKey(secret)
+		if err != nil {
+			return nil, err
+		}
+
+		if namespace == "" {
+			return nil, fmt.Errorf("-%s-secret must be in the form <namespace>/<name>: %s", prefix, secret)
+		}
+
+		return grafana.NewKubernetesSecretSource(kubeClient, namespace, name, stopCh), nil
+	}
+
+	return nil, nil
+}
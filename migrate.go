@@ -0,0 +1,159 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	clientset "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/migrate"
+)
+
+const migrateAlertNotificationsCommand = "migrate-alert-notifications"
+
+// runMigrateAlertNotifications converts AlertNotification objects to ContactPoints and Secrets for
+// their secure settings.  Manifests are written to stdout or -output-dir unless -apply is set.
+// Settings that could not be mapped are reported on stderr.
+func runMigrateAlertNotifications(args []string) error {
+	flags := flag.NewFlagSet(migrateAlertNotificationsCommand, flag.ExitOnError)
+
+	var (
+		kubeconfig string
+		masterURL  string
+		namespace  string
+		outputDir  string
+		apply      bool
+	)
+
+	flags.StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
+	flags.StringVar(&masterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	flags.StringVar(&namespace, "namespace", metav1.NamespaceAll, "Only migrate AlertNotifications in this namespace.  Defaults to all namespaces.")
+	flags.StringVar(&outputDir, "output-dir", "", "Directory to write one <namespace>-<name>.yaml manifest per contact point and its secret to.  Manifests are written to stdout if empty.")
+	flags.BoolVar(&apply, "apply", false, "Create the ContactPoints and Secrets instead of writing manifests.  Existing ContactPoints and Secrets are left alone.")
+
+	flags.Parse(args)
+
+	if apply && outputDir != "" {
+		return fmt.Errorf("only one of -apply and -output-dir may be set")
+	}
+
+	config, err := clientcmd.BuildConfigFromFlags(masterURL, kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	client, err := clientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	alertNotifications, err := client.GrafanaV1alpha1().AlertNotifications(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	items := alertNotifications.Items
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Namespace != items[j].Namespace {
+			return items[i].Namespace < items[j].Namespace
+		}
+
+		return items[i].Name < items[j].Name
+	})
+
+	failed := 0
+
+	for i := range items {
+		alertNotification := &items[i]
+		key := alertNotification.Namespace + "/" + alertNotification.Name
+
+		contactPoint, secret, unmapped, err := migrate.ContactPoint(alertNotification)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: not migrated: %v\n", key, err)
+			failed++
+			continue
+		}
+
+		for _, message := range unmapped {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", key, message)
+		}
+
+		objects := []runtime.Object{contactPoint}
+
+		if apply {
+			// the secret is created first so the contact point never references a missing one
+			if secret != nil {
+				_, err = kubeClient.CoreV1().Secrets(secret.Namespace).Create(secret)
+				if k8serrors.IsAlreadyExists(err) {
+					fmt.Fprintf(os.Stderr, "%s: secret %s already exists, left alone\n", key, secret.Name)
+					err = nil
+				}
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "%s: not migrated: %v\n", key, err)
+					failed++
+					continue
+				}
+			}
+
+			_, err = client.GrafanaV1alpha1().ContactPoints(contactPoint.Namespace).Create(contactPoint)
+			if k8serrors.IsAlreadyExists(err) {
+				fmt.Fprintf(os.Stderr, "%s: contact point already exists, skipped\n", key)
+				continue
+			}
+		} else {
+			if secret != nil {
+				objects = append(objects, secret)
+			}
+
+			err = writeManifest(contactPoint.Namespace+"-"+contactPoint.Name, outputDir, objects...)
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: not migrated: %v\n", key, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d alert notifications were not migrated", failed, len(items))
+	}
+
+	return nil
+}
+
+// writeManifest writes the manifests of objects to dir/<name>.yaml or to stdout if dir is empty
+func writeManifest(name string, dir string, objects ...runtime.Object) error {
+	var manifests string
+
+	for _, object := range objects {
+		manifest, err := migrate.Manifest(object)
+		if err != nil {
+			return err
+		}
+
+		manifests += "---\n" + string(manifest)
+	}
+
+	if dir != "" {
+		return ioutil.WriteFile(filepath.Join(dir, name+".yaml"), []byte(manifests), 0644)
+	}
+
+	_, err := io.WriteString(os.Stdout, manifests)
+
+	return err
+}
@@ -0,0 +1,76 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AlertRuleGroup is a specification for a AlertRuleGroup resource
+type AlertRuleGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AlertRuleGroupSpec   `json:"spec"`
+	Status AlertRuleGroupStatus `json:"status"`
+}
+
+// AlertRuleGroupSpec is the spec for a AlertRuleGroup resource
+type AlertRuleGroupSpec struct {
+	// Title is the name of the group in grafana.  Defaults to the name of the object.
+	Title string `json:"title"`
+	// FolderName is the name of the Folder object the group is stored in
+	FolderName       string `json:"folderName"`
+	OrganizationName string `json:"organizationName"`
+	// Interval is how often the rules are evaluated, e.g. 1m.  Defaults to 1m.
+	Interval string      `json:"interval"`
+	Rules    []AlertRule `json:"rules"`
+}
+
+// AlertRule is a single unified alerting rule.  Rules are identified by title within their group.
+type AlertRule struct {
+	Title string `json:"title"`
+	// Condition is the refId of the query or expression that decides whether the rule fires
+	Condition    string       `json:"condition"`
+	Queries      []AlertQuery `json:"queries"`
+	For          string       `json:"for"`
+	NoDataState  string       `json:"noDataState"`
+	ExecErrState string       `json:"execErrState"`
+
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// AlertQuery is a query or expression evaluated by an AlertRule
+type AlertQuery struct {
+	RefID string `json:"refId"`
+	// DataSourceName is the name of a DataSource object to query.  It takes precedence over
+	// DataSourceUID.
+	DataSourceName string `json:"dataSourceName"`
+	// DataSourceUID is the uid of the grafana data source to query.  Expressions use __expr__.
+	DataSourceUID string `json:"dataSourceUid"`
+	QueryType     string `json:"queryType"`
+	// From and To are the time range queried in seconds before now
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+	// Model is the query or expression json as a string
+	Model string `json:"model"`
+}
+
+// AlertRuleGroupStatus is the status for a AlertRuleGroup resource
+type AlertRuleGroupStatus struct {
+	// GrafanaID is the uid of the group's folder and the group's title joined by /
+	GrafanaID    string `json:"grafanaID"`
+	GrafanaOrgID string `json:"grafanaOrgID"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AlertRuleGroupList is a list of AlertRuleGroup resources
+type AlertRuleGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []AlertRuleGroup `json:"items"`
+}
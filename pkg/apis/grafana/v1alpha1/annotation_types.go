@@ -0,0 +1,53 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Annotation is a specification for an Annotation resource
+type Annotation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AnnotationSpec   `json:"spec"`
+	Status AnnotationStatus `json:"status"`
+}
+
+// AnnotationSpec is the spec for an Annotation resource
+type AnnotationSpec struct {
+	Text string   `json:"text"`
+	Tags []string `json:"tags"`
+	// Time is when the annotation starts.  It defaults to the creation time of the object.
+	Time metav1.Time `json:"time"`
+	// TimeEnd makes the annotation a region ending at this time
+	TimeEnd *metav1.Time `json:"timeEnd"`
+	// DashboardName is the name of a dashboard object to place the annotation on.  Without one
+	// the annotation is shown on every dashboard that queries annotations of its organization.
+	DashboardName string `json:"dashboardName"`
+	// PanelID is the id of a panel in the dashboard's json to restrict the annotation to
+	PanelID          int64  `json:"panelId"`
+	OrganizationName string `json:"organizationName"`
+}
+
+// AnnotationStatus is the status for an Annotation resource
+type AnnotationStatus struct {
+	GrafanaID    string `json:"grafanaID"`
+	GrafanaOrgID string `json:"grafanaOrgID"`
+	// DashboardUID and PanelID record where the annotation was placed.  grafana cannot move an
+	// annotation so it is recreated when they change.
+	DashboardUID string `json:"dashboardUID"`
+	PanelID      int64  `json:"panelId"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AnnotationList is a list of Annotation resources
+type AnnotationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Annotation `json:"items"`
+}
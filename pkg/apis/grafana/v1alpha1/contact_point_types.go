@@ -0,0 +1,51 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ContactPoint is a specification for a ContactPoint resource
+type ContactPoint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ContactPointSpec   `json:"spec"`
+	Status ContactPointStatus `json:"status"`
+}
+
+// ContactPointSpec is the spec for a ContactPoint resource
+type ContactPointSpec struct {
+	// JSON is the contact point's name, type and settings.  The name defaults to the name of the
+	// object.
+	JSON             string `json:"json"`
+	OrganizationName string `json:"organizationName"`
+	// SecureSettings are added to the settings in JSON from Secrets in the object's namespace
+	SecureSettings []SecureSetting `json:"secureSettings"`
+}
+
+// SecureSetting is a contact point setting read from a Secret
+type SecureSetting struct {
+	// Name is the setting's key, e.g. url or integrationKey
+	Name         string                   `json:"name"`
+	SecretKeyRef corev1.SecretKeySelector `json:"secretKeyRef"`
+}
+
+// ContactPointStatus is the status for a ContactPoint resource
+type ContactPointStatus struct {
+	GrafanaID    string `json:"grafanaID"`
+	GrafanaOrgID string `json:"grafanaOrgID"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ContactPointList is a list of ContactPoint resources
+type ContactPointList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ContactPoint `json:"items"`
+}
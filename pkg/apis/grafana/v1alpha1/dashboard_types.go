@@ -18,13 +18,23 @@ type Dashboard struct {
 
 // DashboardSpec is the spec for a Dashboard resource
 type DashboardSpec struct {
-	FolderName string `json:"folderName"`
-	JSON       string `json:"json"`
+	FolderName       string `json:"folderName"`
+	JSON             string `json:"json"`
+	OrganizationName string `json:"organizationName"`
+	// Permissions are granted on the dashboard.  Entries the controller granted before that are no
+	// longer listed are revoked.  Other entries of the ACL are kept unless ReplacePermissions is set.
+	Permissions []Permission `json:"permissions"`
+	// ReplacePermissions replaces the whole ACL of the dashboard with Permissions.  Without Permissions
+	// only org admins keep access.
+	ReplacePermissions bool `json:"replacePermissions"`
 }
 
 // DashboardStatus is the status for a Dashboard resource
 type DashboardStatus struct {
-	GrafanaID string `json:"grafanaID"`
+	GrafanaID    string `json:"grafanaID"`
+	GrafanaOrgID string `json:"grafanaOrgID"`
+	// GrantedPermissions are the entries of the dashboard's ACL the controller granted
+	GrantedPermissions []GrantedPermission `json:"grantedPermissions,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
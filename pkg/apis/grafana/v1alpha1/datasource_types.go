@@ -18,12 +18,14 @@ type DataSource struct {
 
 // DataSourceSpec is the spec for a DataSource resource
 type DataSourceSpec struct {
-	JSON string `json:"json"`
+	JSON             string `json:"json"`
+	OrganizationName string `json:"organizationName"`
 }
 
 // DataSourceStatus is the status for a DataSource resource
 type DataSourceStatus struct {
-	GrafanaID string `json:"grafanaID"`
+	GrafanaID    string `json:"grafanaID"`
+	GrafanaOrgID string `json:"grafanaOrgID"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -18,13 +18,23 @@ type Folder struct {
 
 // FolderSpec is the spec for a Folder resource
 type FolderSpec struct {
-	JSON string `json:"json"`
+	JSON             string `json:"json"`
+	OrganizationName string `json:"organizationName"`
+	// Permissions are granted on the folder.  Entries the controller granted before that are no
+	// longer listed are revoked.  Other entries of the ACL are kept unless ReplacePermissions is set.
+	Permissions []Permission `json:"permissions"`
+	// ReplacePermissions replaces the whole ACL of the folder with Permissions.  Without Permissions
+	// only org admins keep access.
+	ReplacePermissions bool `json:"replacePermissions"`
 }
 
 // FolderStatus is the status for a Folder resource
 type FolderStatus struct {
 	GrafanaID              string `json:"grafanaID"`
 	GrafanaIDForDashboards string `json:"grafanaIDForDashboards"`
+	GrafanaOrgID           string `json:"grafanaOrgID"`
+	// GrantedPermissions are the entries of the folder's ACL the controller granted
+	GrantedPermissions []GrantedPermission `json:"grantedPermissions,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -0,0 +1,43 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LibraryPanel is a specification for a LibraryPanel resource
+type LibraryPanel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LibraryPanelSpec   `json:"spec"`
+	Status LibraryPanelStatus `json:"status"`
+}
+
+// LibraryPanelSpec is the spec for a LibraryPanel resource
+type LibraryPanelSpec struct {
+	// Name is the name of the panel in grafana.  It defaults to the name of the object.
+	Name string `json:"name"`
+	// JSON is the panel model
+	JSON             string `json:"json"`
+	FolderName       string `json:"folderName"`
+	OrganizationName string `json:"organizationName"`
+}
+
+// LibraryPanelStatus is the status for a LibraryPanel resource
+type LibraryPanelStatus struct {
+	GrafanaID    string `json:"grafanaID"`
+	GrafanaOrgID string `json:"grafanaOrgID"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LibraryPanelList is a list of LibraryPanel resources
+type LibraryPanelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []LibraryPanel `json:"items"`
+}
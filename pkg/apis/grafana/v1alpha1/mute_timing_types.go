@@ -0,0 +1,42 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MuteTiming is a specification for a MuteTiming resource
+type MuteTiming struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MuteTimingSpec   `json:"spec"`
+	Status MuteTimingStatus `json:"status"`
+}
+
+// MuteTimingSpec is the spec for a MuteTiming resource
+type MuteTimingSpec struct {
+	// JSON is the mute timing's name and time_intervals.  The name defaults to the name of the
+	// object.
+	JSON             string `json:"json"`
+	OrganizationName string `json:"organizationName"`
+}
+
+// MuteTimingStatus is the status for a MuteTiming resource
+type MuteTimingStatus struct {
+	// GrafanaID is the name of the mute timing in grafana
+	GrafanaID    string `json:"grafanaID"`
+	GrafanaOrgID string `json:"grafanaOrgID"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MuteTimingList is a list of MuteTiming resources
+type MuteTimingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []MuteTiming `json:"items"`
+}
@@ -18,12 +18,14 @@ type AlertNotification struct {
 
 // AlertNotificationSpec is the spec for a AlertNotification resource
 type AlertNotificationSpec struct {
-	JSON string `json:"json"`
+	JSON             string `json:"json"`
+	OrganizationName string `json:"organizationName"`
 }
 
 // AlertNotificationStatus is the status for a AlertNotification resource
 type AlertNotificationStatus struct {
-	GrafanaID string `json:"grafanaID"`
+	GrafanaID    string `json:"grafanaID"`
+	GrafanaOrgID string `json:"grafanaOrgID"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
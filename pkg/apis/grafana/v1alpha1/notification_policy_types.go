@@ -0,0 +1,71 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NotificationPolicy is a specification for a NotificationPolicy resource.  It owns the unified
+// alerting policy tree of an organization.  An organization is managed by one NotificationPolicy.
+type NotificationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NotificationPolicySpec   `json:"spec"`
+	Status NotificationPolicyStatus `json:"status"`
+}
+
+// NotificationPolicySpec is the spec for a NotificationPolicy resource
+type NotificationPolicySpec struct {
+	// OrganizationNamespace and OrganizationName name the Organization object whose policy tree
+	// is managed.  The organization of the controller's credentials is managed if they are empty.
+	OrganizationNamespace string `json:"organizationNamespace"`
+	OrganizationName      string `json:"organizationName"`
+	// NamespaceLabel restricts the routes of NotificationRoutes to alerts with this label set to
+	// the route's namespace.  Routes are not restricted if it is empty.
+	NamespaceLabel string `json:"namespaceLabel"`
+	// Route is the root of the tree.  It must have a receiver.
+	Route PolicyRoute `json:"route"`
+}
+
+// PolicyRoute is a node of the policy tree
+type PolicyRoute struct {
+	// Receiver is the name of a grafana contact point
+	Receiver string `json:"receiver"`
+	// ContactPointName is the name of a ContactPoint object in the route's namespace.  It takes
+	// precedence over Receiver and can only be used in NotificationRoutes.
+	ContactPointName string `json:"contactPointName"`
+	// Matchers select alerts by label, e.g. severity=critical.  Supported operators are =, !=, =~
+	// and !~.
+	Matchers       []string `json:"matchers"`
+	GroupBy        []string `json:"groupBy"`
+	Continue       bool     `json:"continue"`
+	GroupWait      string   `json:"groupWait"`
+	GroupInterval  string   `json:"groupInterval"`
+	RepeatInterval string   `json:"repeatInterval"`
+	// MuteTimeIntervals are the names of grafana mute timings
+	MuteTimeIntervals []string `json:"muteTimeIntervals"`
+	// MuteTimingNames are the names of MuteTiming objects in the route's namespace.  They can only
+	// be used in NotificationRoutes.
+	MuteTimingNames []string      `json:"muteTimingNames"`
+	Routes          []PolicyRoute `json:"routes"`
+}
+
+// NotificationPolicyStatus is the status for a NotificationPolicy resource
+type NotificationPolicyStatus struct {
+	GrafanaID    string `json:"grafanaID"`
+	GrafanaOrgID string `json:"grafanaOrgID"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NotificationPolicyList is a list of NotificationPolicy resources
+type NotificationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NotificationPolicy `json:"items"`
+}
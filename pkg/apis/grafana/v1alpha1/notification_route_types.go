@@ -0,0 +1,40 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NotificationRoute is a specification for a NotificationRoute resource.  It contributes a route
+// to the policy tree of the NotificationPolicy of its organization.
+type NotificationRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NotificationRouteSpec   `json:"spec"`
+	Status NotificationRouteStatus `json:"status"`
+}
+
+// NotificationRouteSpec is the spec for a NotificationRoute resource
+type NotificationRouteSpec struct {
+	OrganizationName string      `json:"organizationName"`
+	Route            PolicyRoute `json:"route"`
+}
+
+// NotificationRouteStatus is the status for a NotificationRoute resource
+type NotificationRouteStatus struct {
+	// PolicyName is the name of the NotificationPolicy the route was last merged into
+	PolicyName string `json:"policyName"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NotificationRouteList is a list of NotificationRoute resources
+type NotificationRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NotificationRoute `json:"items"`
+}
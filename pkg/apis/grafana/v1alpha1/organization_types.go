@@ -0,0 +1,37 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Organization is a specification for a Organization resource
+type Organization struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OrganizationSpec   `json:"spec"`
+	Status OrganizationStatus `json:"status"`
+}
+
+// OrganizationSpec is the spec for a Organization resource
+type OrganizationSpec struct {
+	JSON string `json:"json"`
+}
+
+// OrganizationStatus is the status for a Organization resource
+type OrganizationStatus struct {
+	GrafanaID string `json:"grafanaID"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OrganizationList is a list of Organization resources
+type OrganizationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Organization `json:"items"`
+}
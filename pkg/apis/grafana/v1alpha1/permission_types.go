@@ -0,0 +1,21 @@
+package v1alpha1
+
+// Permission grants View, Edit or Admin on the object it is part of.  Exactly one of Role,
+// TeamName and User is set.
+type Permission struct {
+	// Role is Viewer or Editor
+	Role string `json:"role,omitempty"`
+	// TeamName is the name of a Team in the same namespace
+	TeamName string `json:"teamName,omitempty"`
+	// User is the login or email of a grafana user
+	User       string `json:"user,omitempty"`
+	Permission string `json:"permission"`
+}
+
+// GrantedPermission is an entry the controller granted in the ACL of the object it is part of.
+// Exactly one of Role, TeamID and UserID is set.
+type GrantedPermission struct {
+	Role   string `json:"role,omitempty"`
+	TeamID string `json:"teamID,omitempty"`
+	UserID string `json:"userID,omitempty"`
+}
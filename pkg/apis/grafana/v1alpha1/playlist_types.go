@@ -0,0 +1,52 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Playlist is a specification for a Playlist resource
+type Playlist struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PlaylistSpec   `json:"spec"`
+	Status PlaylistStatus `json:"status"`
+}
+
+// PlaylistSpec is the spec for a Playlist resource
+type PlaylistSpec struct {
+	// Name is the name of the playlist in grafana.  It defaults to the name of the object.
+	Name string `json:"name"`
+	// Interval is how long each dashboard is shown, e.g. 5m.  It defaults to 5m.
+	Interval         string         `json:"interval"`
+	Items            []PlaylistItem `json:"items"`
+	OrganizationName string         `json:"organizationName"`
+}
+
+// PlaylistItem references dashboard objects in the playlist's namespace.  Exactly one of
+// DashboardName and Tag is set.
+type PlaylistItem struct {
+	// DashboardName is the name of a dashboard object
+	DashboardName string `json:"dashboardName,omitempty"`
+	// Tag selects every dashboard object whose json has this tag, in order of name
+	Tag string `json:"tag,omitempty"`
+}
+
+// PlaylistStatus is the status for a Playlist resource
+type PlaylistStatus struct {
+	GrafanaID    string `json:"grafanaID"`
+	GrafanaOrgID string `json:"grafanaOrgID"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PlaylistList is a list of Playlist resources
+type PlaylistList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Playlist `json:"items"`
+}
@@ -37,6 +37,28 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&DataSourceList{},
 		&Folder{},
 		&FolderList{},
+		&Organization{},
+		&OrganizationList{},
+		&Team{},
+		&TeamList{},
+		&AlertRuleGroup{},
+		&AlertRuleGroupList{},
+		&ContactPoint{},
+		&ContactPointList{},
+		&NotificationPolicy{},
+		&NotificationPolicyList{},
+		&NotificationRoute{},
+		&NotificationRouteList{},
+		&MuteTiming{},
+		&MuteTimingList{},
+		&LibraryPanel{},
+		&LibraryPanelList{},
+		&Playlist{},
+		&PlaylistList{},
+		&Annotation{},
+		&AnnotationList{},
+		&ServiceAccount{},
+		&ServiceAccountList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
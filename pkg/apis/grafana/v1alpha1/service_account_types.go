@@ -0,0 +1,54 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceAccount is a specification for a ServiceAccount resource
+type ServiceAccount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServiceAccountSpec   `json:"spec"`
+	Status ServiceAccountStatus `json:"status"`
+}
+
+// ServiceAccountSpec is the spec for a ServiceAccount resource
+type ServiceAccountSpec struct {
+	// Name is the name of the service account in grafana.  It defaults to the name of the object.
+	Name string `json:"name"`
+	// Role is the organization role of the service account: Viewer, Editor, Admin or None.  It
+	// defaults to Viewer.
+	Role string `json:"role"`
+	// TokenTTL is how long a token lives, e.g. 720h.  Tokens are rotated before they expire.
+	// Tokens never expire if it is empty.
+	TokenTTL string `json:"tokenTTL"`
+	// SecretName is the name of the Secret the token is written to.  It defaults to the name of
+	// the object.
+	SecretName       string `json:"secretName"`
+	OrganizationName string `json:"organizationName"`
+}
+
+// ServiceAccountStatus is the status for a ServiceAccount resource
+type ServiceAccountStatus struct {
+	GrafanaID    string `json:"grafanaID"`
+	GrafanaOrgID string `json:"grafanaOrgID"`
+	// TokenID is the id of the token in the Secret SecretName
+	TokenID    string `json:"tokenID"`
+	SecretName string `json:"secretName"`
+	// TokenExpiration is when the token expires.  It is not set for tokens that never expire.
+	TokenExpiration *metav1.Time `json:"tokenExpiration,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceAccountList is a list of ServiceAccount resources
+type ServiceAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ServiceAccount `json:"items"`
+}
@@ -0,0 +1,41 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Team is a specification for a Team resource
+type Team struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TeamSpec   `json:"spec"`
+	Status TeamStatus `json:"status"`
+}
+
+// TeamSpec is the spec for a Team resource
+type TeamSpec struct {
+	JSON             string `json:"json"`
+	OrganizationName string `json:"organizationName"`
+	// Members are the logins or emails of the grafana users in the team
+	Members []string `json:"members"`
+}
+
+// TeamStatus is the status for a Team resource
+type TeamStatus struct {
+	GrafanaID    string `json:"grafanaID"`
+	GrafanaOrgID string `json:"grafanaOrgID"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TeamList is a list of Team resources
+type TeamList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Team `json:"items"`
+}
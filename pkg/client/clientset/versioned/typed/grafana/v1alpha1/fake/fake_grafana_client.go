@@ -33,6 +33,18 @@ func (c *FakeGrafanaV1alpha1) AlertNotifications(namespace string) v1alpha1.Aler
 	return &FakeAlertNotifications{c, namespace}
 }
 
+func (c *FakeGrafanaV1alpha1) AlertRuleGroups(namespace string) v1alpha1.AlertRuleGroupInterface {
+	return &FakeAlertRuleGroups{c, namespace}
+}
+
+func (c *FakeGrafanaV1alpha1) Annotations(namespace string) v1alpha1.AnnotationInterface {
+	return &FakeAnnotations{c, namespace}
+}
+
+func (c *FakeGrafanaV1alpha1) ContactPoints(namespace string) v1alpha1.ContactPointInterface {
+	return &FakeContactPoints{c, namespace}
+}
+
 func (c *FakeGrafanaV1alpha1) Dashboards(namespace string) v1alpha1.DashboardInterface {
 	return &FakeDashboards{c, namespace}
 }
@@ -45,6 +57,38 @@ func (c *FakeGrafanaV1alpha1) Folders(namespace string) v1alpha1.FolderInterface
 	return &FakeFolders{c, namespace}
 }
 
+func (c *FakeGrafanaV1alpha1) LibraryPanels(namespace string) v1alpha1.LibraryPanelInterface {
+	return &FakeLibraryPanels{c, namespace}
+}
+
+func (c *FakeGrafanaV1alpha1) MuteTimings(namespace string) v1alpha1.MuteTimingInterface {
+	return &FakeMuteTimings{c, namespace}
+}
+
+func (c *FakeGrafanaV1alpha1) NotificationPolicies() v1alpha1.NotificationPolicyInterface {
+	return &FakeNotificationPolicies{c}
+}
+
+func (c *FakeGrafanaV1alpha1) NotificationRoutes(namespace string) v1alpha1.NotificationRouteInterface {
+	return &FakeNotificationRoutes{c, namespace}
+}
+
+func (c *FakeGrafanaV1alpha1) Organizations(namespace string) v1alpha1.OrganizationInterface {
+	return &FakeOrganizations{c, namespace}
+}
+
+func (c *FakeGrafanaV1alpha1) Playlists(namespace string) v1alpha1.PlaylistInterface {
+	return &FakePlaylists{c, namespace}
+}
+
+func (c *FakeGrafanaV1alpha1) ServiceAccounts(namespace string) v1alpha1.ServiceAccountInterface {
+	return &FakeServiceAccounts{c, namespace}
+}
+
+func (c *FakeGrafanaV1alpha1) Teams(namespace string) v1alpha1.TeamInterface {
+	return &FakeTeams{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeGrafanaV1alpha1) RESTClient() rest.Interface {
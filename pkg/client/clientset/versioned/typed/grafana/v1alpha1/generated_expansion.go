@@ -20,8 +20,30 @@ package v1alpha1
 
 type AlertNotificationExpansion interface{}
 
+type AlertRuleGroupExpansion interface{}
+
+type AnnotationExpansion interface{}
+
+type ContactPointExpansion interface{}
+
 type DashboardExpansion interface{}
 
 type DataSourceExpansion interface{}
 
 type FolderExpansion interface{}
+
+type LibraryPanelExpansion interface{}
+
+type MuteTimingExpansion interface{}
+
+type NotificationPolicyExpansion interface{}
+
+type NotificationRouteExpansion interface{}
+
+type OrganizationExpansion interface{}
+
+type PlaylistExpansion interface{}
+
+type ServiceAccountExpansion interface{}
+
+type TeamExpansion interface{}
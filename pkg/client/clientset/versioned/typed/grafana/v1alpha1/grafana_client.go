@@ -29,9 +29,20 @@ import (
 type GrafanaV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	AlertNotificationsGetter
+	AlertRuleGroupsGetter
+	AnnotationsGetter
+	ContactPointsGetter
 	DashboardsGetter
 	DataSourcesGetter
 	FoldersGetter
+	LibraryPanelsGetter
+	MuteTimingsGetter
+	NotificationPoliciesGetter
+	NotificationRoutesGetter
+	OrganizationsGetter
+	PlaylistsGetter
+	ServiceAccountsGetter
+	TeamsGetter
 }
 
 // GrafanaV1alpha1Client is used to interact with features provided by the grafana.com group.
@@ -43,6 +54,18 @@ func (c *GrafanaV1alpha1Client) AlertNotifications(namespace string) AlertNotifi
 	return newAlertNotifications(c, namespace)
 }
 
+func (c *GrafanaV1alpha1Client) AlertRuleGroups(namespace string) AlertRuleGroupInterface {
+	return newAlertRuleGroups(c, namespace)
+}
+
+func (c *GrafanaV1alpha1Client) Annotations(namespace string) AnnotationInterface {
+	return newAnnotations(c, namespace)
+}
+
+func (c *GrafanaV1alpha1Client) ContactPoints(namespace string) ContactPointInterface {
+	return newContactPoints(c, namespace)
+}
+
 func (c *GrafanaV1alpha1Client) Dashboards(namespace string) DashboardInterface {
 	return newDashboards(c, namespace)
 }
@@ -55,6 +78,38 @@ func (c *GrafanaV1alpha1Client) Folders(namespace string) FolderInterface {
 	return newFolders(c, namespace)
 }
 
+func (c *GrafanaV1alpha1Client) LibraryPanels(namespace string) LibraryPanelInterface {
+	return newLibraryPanels(c, namespace)
+}
+
+func (c *GrafanaV1alpha1Client) MuteTimings(namespace string) MuteTimingInterface {
+	return newMuteTimings(c, namespace)
+}
+
+func (c *GrafanaV1alpha1Client) NotificationPolicies() NotificationPolicyInterface {
+	return newNotificationPolicies(c)
+}
+
+func (c *GrafanaV1alpha1Client) NotificationRoutes(namespace string) NotificationRouteInterface {
+	return newNotificationRoutes(c, namespace)
+}
+
+func (c *GrafanaV1alpha1Client) Organizations(namespace string) OrganizationInterface {
+	return newOrganizations(c, namespace)
+}
+
+func (c *GrafanaV1alpha1Client) Playlists(namespace string) PlaylistInterface {
+	return newPlaylists(c, namespace)
+}
+
+func (c *GrafanaV1alpha1Client) ServiceAccounts(namespace string) ServiceAccountInterface {
+	return newServiceAccounts(c, namespace)
+}
+
+func (c *GrafanaV1alpha1Client) Teams(namespace string) TeamInterface {
+	return newTeams(c, namespace)
+}
+
 // NewForConfig creates a new GrafanaV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*GrafanaV1alpha1Client, error) {
 	config := *c
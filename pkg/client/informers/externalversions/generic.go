@@ -55,12 +55,34 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=grafana.com, Version=v1alpha1
 	case v1alpha1.SchemeGroupVersion.WithResource("alertnotifications"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Grafana().V1alpha1().AlertNotifications().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("alertrulegroups"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Grafana().V1alpha1().AlertRuleGroups().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("annotations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Grafana().V1alpha1().Annotations().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("contactpoints"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Grafana().V1alpha1().ContactPoints().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("dashboards"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Grafana().V1alpha1().Dashboards().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("datasources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Grafana().V1alpha1().DataSources().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("folders"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Grafana().V1alpha1().Folders().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("librarypanels"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Grafana().V1alpha1().LibraryPanels().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("mutetimings"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Grafana().V1alpha1().MuteTimings().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("notificationpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Grafana().V1alpha1().NotificationPolicies().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("notificationroutes"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Grafana().V1alpha1().NotificationRoutes().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("organizations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Grafana().V1alpha1().Organizations().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("playlists"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Grafana().V1alpha1().Playlists().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("serviceaccounts"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Grafana().V1alpha1().ServiceAccounts().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("teams"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Grafana().V1alpha1().Teams().Informer()}, nil
 
 	}
 
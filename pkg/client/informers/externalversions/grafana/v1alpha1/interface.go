@@ -26,12 +26,34 @@ import (
 type Interface interface {
 	// AlertNotifications returns a AlertNotificationInformer.
 	AlertNotifications() AlertNotificationInformer
+	// AlertRuleGroups returns a AlertRuleGroupInformer.
+	AlertRuleGroups() AlertRuleGroupInformer
+	// Annotations returns a AnnotationInformer.
+	Annotations() AnnotationInformer
+	// ContactPoints returns a ContactPointInformer.
+	ContactPoints() ContactPointInformer
 	// Dashboards returns a DashboardInformer.
 	Dashboards() DashboardInformer
 	// DataSources returns a DataSourceInformer.
 	DataSources() DataSourceInformer
 	// Folders returns a FolderInformer.
 	Folders() FolderInformer
+	// LibraryPanels returns a LibraryPanelInformer.
+	LibraryPanels() LibraryPanelInformer
+	// MuteTimings returns a MuteTimingInformer.
+	MuteTimings() MuteTimingInformer
+	// NotificationPolicies returns a NotificationPolicyInformer.
+	NotificationPolicies() NotificationPolicyInformer
+	// NotificationRoutes returns a NotificationRouteInformer.
+	NotificationRoutes() NotificationRouteInformer
+	// Organizations returns a OrganizationInformer.
+	Organizations() OrganizationInformer
+	// Playlists returns a PlaylistInformer.
+	Playlists() PlaylistInformer
+	// ServiceAccounts returns a ServiceAccountInformer.
+	ServiceAccounts() ServiceAccountInformer
+	// Teams returns a TeamInformer.
+	Teams() TeamInformer
 }
 
 type version struct {
@@ -50,6 +72,21 @@ func (v *version) AlertNotifications() AlertNotificationInformer {
 	return &alertNotificationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// AlertRuleGroups returns a AlertRuleGroupInformer.
+func (v *version) AlertRuleGroups() AlertRuleGroupInformer {
+	return &alertRuleGroupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// Annotations returns a AnnotationInformer.
+func (v *version) Annotations() AnnotationInformer {
+	return &annotationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// ContactPoints returns a ContactPointInformer.
+func (v *version) ContactPoints() ContactPointInformer {
+	return &contactPointInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Dashboards returns a DashboardInformer.
 func (v *version) Dashboards() DashboardInformer {
 	return &dashboardInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -64,3 +101,43 @@ func (v *version) DataSources() DataSourceInformer {
 func (v *version) Folders() FolderInformer {
 	return &folderInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// LibraryPanels returns a LibraryPanelInformer.
+func (v *version) LibraryPanels() LibraryPanelInformer {
+	return &libraryPanelInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// MuteTimings returns a MuteTimingInformer.
+func (v *version) MuteTimings() MuteTimingInformer {
+	return &muteTimingInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// NotificationPolicies returns a NotificationPolicyInformer.
+func (v *version) NotificationPolicies() NotificationPolicyInformer {
+	return &notificationPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// NotificationRoutes returns a NotificationRouteInformer.
+func (v *version) NotificationRoutes() NotificationRouteInformer {
+	return &notificationRouteInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// Organizations returns a OrganizationInformer.
+func (v *version) Organizations() OrganizationInformer {
+	return &organizationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// Playlists returns a PlaylistInformer.
+func (v *version) Playlists() PlaylistInformer {
+	return &playlistInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// ServiceAccounts returns a ServiceAccountInformer.
+func (v *version) ServiceAccounts() ServiceAccountInformer {
+	return &serviceAccountInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// Teams returns a TeamInformer.
+func (v *version) Teams() TeamInformer {
+	return &teamInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
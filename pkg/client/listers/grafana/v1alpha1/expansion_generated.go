@@ -26,6 +26,30 @@ type AlertNotificationListerExpansion interface{}
 // AlertNotificationNamespaceLister.
 type AlertNotificationNamespaceListerExpansion interface{}
 
+// AlertRuleGroupListerExpansion allows custom methods to be added to
+// AlertRuleGroupLister.
+type AlertRuleGroupListerExpansion interface{}
+
+// AlertRuleGroupNamespaceListerExpansion allows custom methods to be added to
+// AlertRuleGroupNamespaceLister.
+type AlertRuleGroupNamespaceListerExpansion interface{}
+
+// AnnotationListerExpansion allows custom methods to be added to
+// AnnotationLister.
+type AnnotationListerExpansion interface{}
+
+// AnnotationNamespaceListerExpansion allows custom methods to be added to
+// AnnotationNamespaceLister.
+type AnnotationNamespaceListerExpansion interface{}
+
+// ContactPointListerExpansion allows custom methods to be added to
+// ContactPointLister.
+type ContactPointListerExpansion interface{}
+
+// ContactPointNamespaceListerExpansion allows custom methods to be added to
+// ContactPointNamespaceLister.
+type ContactPointNamespaceListerExpansion interface{}
+
 // DashboardListerExpansion allows custom methods to be added to
 // DashboardLister.
 type DashboardListerExpansion interface{}
@@ -49,3 +73,63 @@ type FolderListerExpansion interface{}
 // FolderNamespaceListerExpansion allows custom methods to be added to
 // FolderNamespaceLister.
 type FolderNamespaceListerExpansion interface{}
+
+// LibraryPanelListerExpansion allows custom methods to be added to
+// LibraryPanelLister.
+type LibraryPanelListerExpansion interface{}
+
+// LibraryPanelNamespaceListerExpansion allows custom methods to be added to
+// LibraryPanelNamespaceLister.
+type LibraryPanelNamespaceListerExpansion interface{}
+
+// MuteTimingListerExpansion allows custom methods to be added to
+// MuteTimingLister.
+type MuteTimingListerExpansion interface{}
+
+// MuteTimingNamespaceListerExpansion allows custom methods to be added to
+// MuteTimingNamespaceLister.
+type MuteTimingNamespaceListerExpansion interface{}
+
+// NotificationPolicyListerExpansion allows custom methods to be added to
+// NotificationPolicyLister.
+type NotificationPolicyListerExpansion interface{}
+
+// NotificationRouteListerExpansion allows custom methods to be added to
+// NotificationRouteLister.
+type NotificationRouteListerExpansion interface{}
+
+// NotificationRouteNamespaceListerExpansion allows custom methods to be added to
+// NotificationRouteNamespaceLister.
+type NotificationRouteNamespaceListerExpansion interface{}
+
+// OrganizationListerExpansion allows custom methods to be added to
+// OrganizationLister.
+type OrganizationListerExpansion interface{}
+
+// OrganizationNamespaceListerExpansion allows custom methods to be added to
+// OrganizationNamespaceLister.
+type OrganizationNamespaceListerExpansion interface{}
+
+// PlaylistListerExpansion allows custom methods to be added to
+// PlaylistLister.
+type PlaylistListerExpansion interface{}
+
+// PlaylistNamespaceListerExpansion allows custom methods to be added to
+// PlaylistNamespaceLister.
+type PlaylistNamespaceListerExpansion interface{}
+
+// ServiceAccountListerExpansion allows custom methods to be added to
+// ServiceAccountLister.
+type ServiceAccountListerExpansion interface{}
+
+// ServiceAccountNamespaceListerExpansion allows custom methods to be added to
+// ServiceAccountNamespaceLister.
+type ServiceAccountNamespaceListerExpansion interface{}
+
+// TeamListerExpansion allows custom methods to be added to
+// TeamLister.
+type TeamListerExpansion interface{}
+
+// TeamNamespaceListerExpansion allows custom methods to be added to
+// TeamNamespaceLister.
+type TeamNamespaceListerExpansion interface{}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"fmt"
 	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
 
@@ -20,6 +21,7 @@ import (
 // AlertNotificationSyncer is the controller implementation for GrafanaAlertNotification resources
 type AlertNotificationSyncer struct {
 	grafanaAlertNotificationLister listers.AlertNotificationLister
+	grafanaOrganizationsLister     listers.OrganizationLister
 	grafanaClient                  grafana.Interface
 	grafanaclientset               clientset.Interface
 }
@@ -29,10 +31,12 @@ func NewAlertNotificationController(
 	grafanaclientset clientset.Interface,
 	kubeclientset kubernetes.Interface,
 	grafanaClient grafana.Interface,
-	grafanaAlertNotificationInformer informers.AlertNotificationInformer) *Controller {
+	grafanaAlertNotificationInformer informers.AlertNotificationInformer,
+	grafanaOrganizationInformer informers.OrganizationInformer) *Controller {
 
 	syncer := &AlertNotificationSyncer{
 		grafanaAlertNotificationLister: grafanaAlertNotificationInformer.Lister(),
+		grafanaOrganizationsLister:     grafanaOrganizationInformer.Lister(),
 		grafanaClient:                  grafanaClient,
 		grafanaclientset:               grafanaclientset,
 	}
@@ -40,6 +44,7 @@ func NewAlertNotificationController(
 	controller := NewController(grafanaAlertNotificationInformer.Informer(),
 		kubeclientset,
 		syncer)
+	controller.watchOrganizations(grafanaOrganizationInformer)
 
 	return controller
 }
@@ -52,18 +57,29 @@ func (s *AlertNotificationSyncer) getRuntimeObjectByName(name string, namespace
 	return s.grafanaAlertNotificationLister.AlertNotifications(namespace).Get(name)
 }
 
-func (s *AlertNotificationSyncer) deleteObjectById(id string) error {
-	return s.grafanaClient.DeleteAlertNotification(id)
+func (s *AlertNotificationSyncer) deleteObjectById(ctx context.Context, id string) error {
+	return s.grafanaClient.DeleteAlertNotification(ctx, id)
 }
 
-func (s *AlertNotificationSyncer) updateObject(object runtime.Object) error {
+func (s *AlertNotificationSyncer) updateObject(ctx context.Context, object runtime.Object) error {
 
 	grafanaAlertNotification, ok := object.(*v1alpha1.AlertNotification)
 	if !ok {
 		return fmt.Errorf("expected alert notification in but got %#v", object)
 	}
 
-	id, err := s.grafanaClient.PostAlertNotification(grafanaAlertNotification.Spec.JSON, grafanaAlertNotification.Status.GrafanaID)
+	ctx, orgID, grafanaID, err := targetOrganization(ctx,
+		s.grafanaOrganizationsLister,
+		grafanaAlertNotification.Namespace,
+		grafanaAlertNotification.Spec.OrganizationName,
+		grafanaAlertNotification.Status.GrafanaOrgID,
+		grafanaAlertNotification.Status.GrafanaID,
+		s.deleteObjectById)
+	if err != nil {
+		return err
+	}
+
+	id, err := s.grafanaClient.PostAlertNotification(ctx, grafanaAlertNotification.Spec.JSON, grafanaID)
 
 	if err != nil {
 		return err
@@ -71,6 +87,7 @@ func (s *AlertNotificationSyncer) updateObject(object runtime.Object) error {
 
 	grafanaAlertNotificationCopy := grafanaAlertNotification.DeepCopy()
 	grafanaAlertNotificationCopy.Status.GrafanaID = id
+	grafanaAlertNotificationCopy.Status.GrafanaOrgID = orgID
 	_, err = s.grafanaclientset.GrafanaV1alpha1().AlertNotifications(grafanaAlertNotification.Namespace).UpdateStatus(grafanaAlertNotificationCopy)
 
 	if err != nil {
@@ -79,7 +96,7 @@ func (s *AlertNotificationSyncer) updateObject(object runtime.Object) error {
 	return nil
 }
 
-func (s *AlertNotificationSyncer) getAllKubernetesObjectIDs() ([]string, error) {
+func (s *AlertNotificationSyncer) getAllKubernetesObjectIDs(orgID string) ([]string, error) {
 	alertNotifications, err := s.grafanaAlertNotificationLister.List(labels.Everything())
 
 	if err != nil {
@@ -89,14 +106,23 @@ func (s *AlertNotificationSyncer) getAllKubernetesObjectIDs() ([]string, error)
 	ids := make([]string, 0)
 
 	for _, notification := range alertNotifications {
+		// objects without an id may be in any organization
+		if notification.Status.GrafanaOrgID != orgID && notification.Status.GrafanaID != grafana.NO_ID {
+			continue
+		}
+
 		ids = append(ids, notification.Status.GrafanaID)
 	}
 
 	return ids, nil
 }
 
-func (s *AlertNotificationSyncer) getAllGrafanaObjectIDs() ([]string, error) {
-	return s.grafanaClient.GetAllAlertNotificationIds()
+func (s *AlertNotificationSyncer) getAllGrafanaObjectIDs(ctx context.Context) ([]string, error) {
+	return s.grafanaClient.GetAllAlertNotificationIds(ctx)
+}
+
+func (s *AlertNotificationSyncer) getGrafanaObjectNumericIDs(ctx context.Context) (map[string]string, error) {
+	return s.grafanaClient.GetAlertNotificationNumericIds(ctx)
 }
 
 func (s *AlertNotificationSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
@@ -115,7 +141,7 @@ func (s *AlertNotificationSyncer) createWorkQueueItem(obj interface{}) *WorkQueu
 		return nil
 	}
 
-	item := NewWorkQueueItem(key, grafanaAlertNotification.DeepCopyObject(), grafanaAlertNotification.Status.GrafanaID) // todo: confirm this doesnt need null checking
+	item := NewWorkQueueItem(key, grafanaAlertNotification.DeepCopyObject(), grafanaAlertNotification.Status.GrafanaID, grafanaAlertNotification.Status.GrafanaOrgID) // todo: confirm this doesnt need null checking
 
 	return &item
 }
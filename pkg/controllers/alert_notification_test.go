@@ -1,65 +1,67 @@
 package controllers
 
 import (
-	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned/fake"
-	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions"
-	grafana "github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana/fake"
+	"context"
+	"errors"
 	"testing"
 
-	grafanacontroller "github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
-
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	k8sfake "k8s.io/client-go/kubernetes/fake"
-	"k8s.io/client-go/tools/record"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
 )
 
-func newGrafanaAlertNotification(name string, notificationJson string) *grafanacontroller.GrafanaAlertNotification {
-	return &grafanacontroller.GrafanaAlertNotification{
-		TypeMeta: metav1.TypeMeta{APIVersion: grafanacontroller.SchemeGroupVersion.String()},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: metav1.NamespaceDefault,
-		},
-		Spec: grafanacontroller.GrafanaAlertNotificationSpec{
-			AlertNotificationJSON: notificationJson,
+func newAlertNotification(name string, alertNotificationJson string) *v1alpha1.AlertNotification {
+	return &v1alpha1.AlertNotification{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: newObjectMeta(name),
+		Spec: v1alpha1.AlertNotificationSpec{
+			JSON: alertNotificationJson,
 		},
 	}
 }
 
-func newAlertNotificationController(f *fixture) (*Controller, informers.SharedInformerFactory) {
-	f.client = fake.NewSimpleClientset(f.objects...)
-	f.kubeclient = k8sfake.NewSimpleClientset(f.kubeobjects...)
-	f.grafanaClient = grafana.NewGrafanaClientFake("https://example.com", FAKE_UID)
+func newAlertNotificationController(f *fixture) *Controller {
+	return NewAlertNotificationController(f.client, f.kubeclient, f.grafanaClient,
+		f.informers.Grafana().V1alpha1().AlertNotifications(),
+		f.informers.Grafana().V1alpha1().Organizations())
+}
 
-	i := informers.NewSharedInformerFactory(f.client, noResyncPeriodFunc())
+func TestCreatesAlertNotification(t *testing.T) {
+	alertNotification := newAlertNotification("test", `{"name": "test", "type": "email"}`)
 
-	c := NewAlertNotificationController(f.client, f.kubeclient,
-		f.grafanaClient, i.Grafana().V1alpha1().GrafanaAlertNotifications())
+	f := newFixture(t, alertNotification)
+	c := f.newController(newAlertNotificationController)
 
-	c.informerSynced = alwaysReady
-	c.syncer.(*AlertNotificationSyncer).recorder = &record.FakeRecorder{}
+	if err := f.sync(c, newItem(alertNotification, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
 
-	for _, d := range f.grafanaNotificationLister {
-		i.Grafana().V1alpha1().GrafanaAlertNotifications().Informer().GetIndexer().Add(d)
+	updated, err := f.client.GrafanaV1alpha1().AlertNotifications(metav1.NamespaceDefault).Get("test", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	return c, i
+	if _, err := f.grafanaClient.GetAlertNotification(context.Background(), updated.Status.GrafanaID); err != nil {
+		t.Errorf("expected alert notification in grafana: %v", err)
+	}
 }
 
-func TestCreatesGrafanaAlertNotification(t *testing.T) {
+func TestFailedAlertNotificationIsRequeued(t *testing.T) {
+	alertNotification := newAlertNotification("test", `{"name": "test", "type": "email"}`)
 
-	f := newFixture(t)
-	notificationJson := "{ 'test': 'test' }"
+	f := newFixture(t, alertNotification)
+	f.grafanaClient.FailNext("PostAlertNotification", errors.New("connection refused"))
 
-	notification := newGrafanaAlertNotification("test", notificationJson)
-	item := NewWorkQueueItem(getKey(notification, t), nil, "")
+	c := f.newController(newAlertNotificationController)
 
-	f.grafananotificationLister = append(f.grafananotificationLister, notification)
-	f.objects = append(f.objects, notification)
+	item := newItem(alertNotification, AddOrUpdate, "", t)
+	f.process(c, item)
 
-	notification.Status.GrafanaID = FAKE_UID
-	f.expectUpdateGrafanaObject(notification, notification.Namespace, "grafanaalertnotifications")
-	f.expectGrafanaPost(notificationJson)
+	if c.workqueue.NumRequeues(item) != 1 {
+		t.Error("expected a transient failure to be requeued")
+	}
 
-	f.runController(newAlertNotificationController, item, true, false)
+	if ids, _ := f.grafanaClient.GetAllAlertNotificationIds(context.Background()); len(ids) != 0 {
+		t.Errorf("expected no alert notifications in grafana, got %v", ids)
+	}
 }
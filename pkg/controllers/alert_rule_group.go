@@ -0,0 +1,306 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	clientset "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned"
+	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions/grafana/v1alpha1"
+	listers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/listers/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+const defaultAlertRuleGroupInterval = time.Minute
+
+// AlertRuleGroupSyncer is the controller implementation for AlertRuleGroup resources
+type AlertRuleGroupSyncer struct {
+	grafanaAlertRuleGroupsLister listers.AlertRuleGroupLister
+	grafanaFoldersLister         listers.FolderLister
+	grafanaDataSourcesLister     listers.DataSourceLister
+	grafanaOrganizationsLister   listers.OrganizationLister
+	grafanaClient                grafana.Interface
+	grafanaclientset             clientset.Interface
+
+	// other provisioning clients, like terraform, create rule groups with the same provenance.
+	// only recorded ones are garbage collected
+	managedIDs *managedIDs
+}
+
+// NewAlertRuleGroupController returns a new grafana AlertRuleGroup controller
+func NewAlertRuleGroupController(
+	grafanaclientset clientset.Interface,
+	kubeclientset kubernetes.Interface,
+	grafanaClient grafana.Interface,
+	grafanaAlertRuleGroupInformer informers.AlertRuleGroupInformer,
+	grafanaFolderInformer informers.FolderInformer,
+	grafanaDataSourceInformer informers.DataSourceInformer,
+	grafanaOrganizationInformer informers.OrganizationInformer) *Controller {
+
+	syncer := &AlertRuleGroupSyncer{
+		grafanaAlertRuleGroupsLister: grafanaAlertRuleGroupInformer.Lister(),
+		grafanaFoldersLister:         grafanaFolderInformer.Lister(),
+		grafanaDataSourcesLister:     grafanaDataSourceInformer.Lister(),
+		grafanaOrganizationsLister:   grafanaOrganizationInformer.Lister(),
+		grafanaClient:                grafanaClient,
+		grafanaclientset:             grafanaclientset,
+		managedIDs:                   newManagedIDs(),
+	}
+
+	controller := NewController(grafanaAlertRuleGroupInformer.Informer(),
+		kubeclientset,
+		syncer)
+	controller.watchOrganizations(grafanaOrganizationInformer)
+
+	return controller
+}
+
+func (s *AlertRuleGroupSyncer) getType() string {
+	return prometheus.TypeAlertRuleGroup
+}
+
+func (s *AlertRuleGroupSyncer) getRuntimeObjectByName(name string, namespace string) (runtime.Object, error) {
+	return s.grafanaAlertRuleGroupsLister.AlertRuleGroups(namespace).Get(name)
+}
+
+func (s *AlertRuleGroupSyncer) deleteObjectById(ctx context.Context, id string) error {
+	if err := s.grafanaClient.DeleteAlertRuleGroup(ctx, id); err != nil {
+		return err
+	}
+
+	s.managedIDs.remove(grafana.OrgID(ctx), id)
+	return nil
+}
+
+func (s *AlertRuleGroupSyncer) updateObject(ctx context.Context, object runtime.Object) error {
+
+	grafanaAlertRuleGroup, ok := object.(*v1alpha1.AlertRuleGroup)
+	if !ok {
+		return fmt.Errorf("expected alert rule group in but got %#v", object)
+	}
+
+	ctx, orgID, grafanaID, err := targetOrganization(ctx,
+		s.grafanaOrganizationsLister,
+		grafanaAlertRuleGroup.Namespace,
+		grafanaAlertRuleGroup.Spec.OrganizationName,
+		grafanaAlertRuleGroup.Status.GrafanaOrgID,
+		grafanaAlertRuleGroup.Status.GrafanaID,
+		s.deleteObjectById)
+	if err != nil {
+		return err
+	}
+
+	if grafanaAlertRuleGroup.Spec.FolderName == "" {
+		return grafana.Invalidf("alert rule group %s has no folderName.  grafana stores rules in folders", grafanaAlertRuleGroup.Name)
+	}
+
+	folder, err := s.grafanaFoldersLister.Folders(grafanaAlertRuleGroup.Namespace).Get(grafanaAlertRuleGroup.Spec.FolderName)
+	if err != nil {
+		return err
+	}
+
+	if folder.Status.GrafanaID == grafana.NO_ID {
+		return fmt.Errorf("folder %s has not been created in grafana yet", folder.Name)
+	}
+
+	if folder.Status.GrafanaOrgID != orgID {
+		return fmt.Errorf("folder %s is not in the same organization as alert rule group %s", folder.Name, grafanaAlertRuleGroup.Name)
+	}
+
+	groupJson, title, err := s.alertRuleGroupJson(grafanaAlertRuleGroup, orgID)
+	if err != nil {
+		return err
+	}
+
+	// a renamed group or a group moved to another folder is a new group in grafana
+	if grafanaID != grafana.NO_ID && grafanaID != grafana.AlertRuleGroupId(folder.Status.GrafanaID, title) {
+		if err := s.deleteObjectById(ctx, grafanaID); err != nil {
+			return err
+		}
+	}
+
+	id, err := s.grafanaClient.PostAlertRuleGroup(ctx, folder.Status.GrafanaID, groupJson)
+
+	if err != nil {
+		return err
+	}
+
+	s.managedIDs.add(orgID, id)
+
+	grafanaAlertRuleGroupCopy := grafanaAlertRuleGroup.DeepCopy()
+	grafanaAlertRuleGroupCopy.Status.GrafanaID = id
+	grafanaAlertRuleGroupCopy.Status.GrafanaOrgID = orgID
+
+	_, err = s.grafanaclientset.GrafanaV1alpha1().AlertRuleGroups(grafanaAlertRuleGroup.Namespace).UpdateStatus(grafanaAlertRuleGroupCopy)
+
+	return err
+}
+
+// alertRuleGroupJson converts the spec of a group to the json PostAlertRuleGroup expects and
+// returns it with the group's title.  Data sources are looked up in the group's namespace and
+// must be in the organization orgID.
+func (s *AlertRuleGroupSyncer) alertRuleGroupJson(group *v1alpha1.AlertRuleGroup, orgID string) (string, string, error) {
+	title := group.Spec.Title
+	if title == "" {
+		title = group.Name
+	}
+
+	interval := defaultAlertRuleGroupInterval
+	if group.Spec.Interval != "" {
+		var err error
+
+		interval, err = time.ParseDuration(group.Spec.Interval)
+		if err != nil {
+			return "", "", grafana.WrapInvalid(err, "alert rule group %s has an invalid interval", group.Name)
+		}
+
+		if interval <= 0 || interval%time.Second != 0 {
+			return "", "", grafana.Invalidf("interval %s must be a positive number of seconds", group.Spec.Interval)
+		}
+	}
+
+	rules := make([]map[string]interface{}, 0, len(group.Spec.Rules))
+
+	for _, rule := range group.Spec.Rules {
+		if rule.Title == "" {
+			return "", "", grafana.Invalidf("alert rule group %s has a rule without a title", group.Name)
+		}
+
+		data := make([]map[string]interface{}, 0, len(rule.Queries))
+
+		for _, query := range rule.Queries {
+			dataSourceUid := query.DataSourceUID
+
+			if query.DataSourceName != "" {
+				dataSource, err := s.grafanaDataSourcesLister.DataSources(group.Namespace).Get(query.DataSourceName)
+				if err != nil {
+					return "", "", err
+				}
+
+				if dataSource.Status.GrafanaID == grafana.NO_ID {
+					return "", "", fmt.Errorf("data source %s has not been created in grafana yet", dataSource.Name)
+				}
+
+				if dataSource.Status.GrafanaOrgID != orgID {
+					return "", "", fmt.Errorf("data source %s is not in the same organization as alert rule group %s", dataSource.Name, group.Name)
+				}
+
+				dataSourceUid = dataSource.Status.GrafanaID
+			}
+
+			model := map[string]interface{}{}
+			if query.Model != "" {
+				if err := json.Unmarshal([]byte(query.Model), &model); err != nil {
+					return "", "", grafana.WrapInvalid(err, "query %s of rule %s", query.RefID, rule.Title)
+				}
+			}
+
+			data = append(data, map[string]interface{}{
+				"refId":         query.RefID,
+				"queryType":     query.QueryType,
+				"datasourceUid": dataSourceUid,
+				"relativeTimeRange": map[string]interface{}{
+					"from": query.From,
+					"to":   query.To,
+				},
+				"model": model,
+			})
+		}
+
+		rules = append(rules, map[string]interface{}{
+			"title":        rule.Title,
+			"condition":    rule.Condition,
+			"data":         data,
+			"for":          defaultString(rule.For, "0s"),
+			"noDataState":  defaultString(rule.NoDataState, "NoData"),
+			"execErrState": defaultString(rule.ExecErrState, "Alerting"),
+			"labels":       rule.Labels,
+			"annotations":  rule.Annotations,
+		})
+	}
+
+	groupJson, err := json.Marshal(map[string]interface{}{
+		"title":    title,
+		"interval": int64(interval / time.Second),
+		"rules":    rules,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return string(groupJson), title, nil
+}
+
+func defaultString(value string, def string) string {
+	if value == "" {
+		return def
+	}
+
+	return value
+}
+
+func (s *AlertRuleGroupSyncer) getAllKubernetesObjectIDs(orgID string) ([]string, error) {
+	alertRuleGroups, err := s.grafanaAlertRuleGroupsLister.List(labels.Everything())
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+
+	for _, alertRuleGroup := range alertRuleGroups {
+		// objects without an id may be in any organization
+		if alertRuleGroup.Status.GrafanaOrgID != orgID && alertRuleGroup.Status.GrafanaID != grafana.NO_ID {
+			continue
+		}
+
+		if alertRuleGroup.Status.GrafanaID != grafana.NO_ID {
+			s.managedIDs.add(orgID, alertRuleGroup.Status.GrafanaID)
+		}
+
+		ids = append(ids, alertRuleGroup.Status.GrafanaID)
+	}
+
+	return ids, nil
+}
+
+// getAllGrafanaObjectIDs returns the rule groups the syncer created or found in the status of an
+// AlertRuleGroup.  Rule groups created by hand or by other provisioning clients are never deleted.
+func (s *AlertRuleGroupSyncer) getAllGrafanaObjectIDs(ctx context.Context) ([]string, error) {
+	ids, err := s.grafanaClient.GetAllAlertRuleGroupIds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.managedIDs.filter(grafana.OrgID(ctx), ids), nil
+}
+
+func (s *AlertRuleGroupSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
+	var key string
+	var err error
+	var alertRuleGroup *v1alpha1.AlertRuleGroup
+	var ok bool
+
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	if alertRuleGroup, ok = obj.(*v1alpha1.AlertRuleGroup); !ok {
+		utilruntime.HandleError(fmt.Errorf("expected alert rule group in workqueue but got %#v", obj))
+		return nil
+	}
+
+	item := NewWorkQueueItem(key, alertRuleGroup.DeepCopyObject(), alertRuleGroup.Status.GrafanaID, alertRuleGroup.Status.GrafanaOrgID)
+
+	return &item
+}
@@ -0,0 +1,271 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+)
+
+func newAlertRuleGroup(name string, folderName string, rules ...v1alpha1.AlertRule) *v1alpha1.AlertRuleGroup {
+	return &v1alpha1.AlertRuleGroup{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: newObjectMeta(name),
+		Spec: v1alpha1.AlertRuleGroupSpec{
+			FolderName: folderName,
+			Rules:      rules,
+		},
+	}
+}
+
+func newAlertRuleGroupController(f *fixture) *Controller {
+	return NewAlertRuleGroupController(f.client, f.kubeclient, f.grafanaClient,
+		f.informers.Grafana().V1alpha1().AlertRuleGroups(),
+		f.informers.Grafana().V1alpha1().Folders(),
+		f.informers.Grafana().V1alpha1().DataSources(),
+		f.informers.Grafana().V1alpha1().Organizations())
+}
+
+func (f *fixture) getAlertRuleGroup(name string) *v1alpha1.AlertRuleGroup {
+	alertRuleGroup, err := f.client.GrafanaV1alpha1().AlertRuleGroups(metav1.NamespaceDefault).Get(name, metav1.GetOptions{})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return alertRuleGroup
+}
+
+// createFolder creates a folder in the fake grafana and returns a Folder object recording its uid
+func (f *fixture) createFolder(name string) *v1alpha1.Folder {
+	folder := newFolder(name, `{"title": "`+name+`"}`)
+
+	uid, id, err := f.grafanaClient.PostFolder(context.Background(), folder.Spec.JSON, "")
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	folder.Status.GrafanaID = uid
+	folder.Status.GrafanaIDForDashboards = id
+
+	return folder
+}
+
+func highCPURule() v1alpha1.AlertRule {
+	return v1alpha1.AlertRule{
+		Title:     "HighCPU",
+		Condition: "B",
+		Queries: []v1alpha1.AlertQuery{
+			{RefID: "A", DataSourceName: "prometheus", From: 600, Model: `{"expr": "cpu"}`},
+			{RefID: "B", DataSourceUID: "__expr__", Model: `{"type": "threshold", "expression": "A"}`},
+		},
+	}
+}
+
+func TestCreatesAlertRuleGroup(t *testing.T) {
+	f := newFixture(t)
+
+	folder := f.createFolder("alerts")
+
+	dataSourceId, err := f.grafanaClient.PostDataSource(context.Background(), `{"name": "prometheus"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataSource := newDataSource("prometheus", `{"name": "prometheus"}`)
+	dataSource.Status.GrafanaID = dataSourceId
+
+	group := newAlertRuleGroup("cpu", "alerts", highCPURule())
+	group.Spec.Interval = "5m"
+
+	f = f.withObjects(folder, dataSource, group)
+	c := f.newController(newAlertRuleGroupController)
+
+	if err := f.sync(c, newItem(group, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	id := f.getAlertRuleGroup("cpu").Status.GrafanaID
+	if id != grafana.AlertRuleGroupId(folder.Status.GrafanaID, "cpu") {
+		t.Fatalf("expected the group status to record its folder and title, got %q", id)
+	}
+
+	if _, err := f.grafanaClient.GetAlertRuleGroup(context.Background(), id); err != nil {
+		t.Errorf("expected alert rule group in grafana: %v", err)
+	}
+
+	calls := f.grafanaClient.CallsTo("PostAlertRuleGroup")
+	if len(calls) != 1 {
+		t.Fatalf("expected one post, got %v", calls)
+	}
+
+	var posted struct {
+		Interval int64 `json:"interval"`
+		Rules    []struct {
+			NoDataState string `json:"noDataState"`
+			Data        []struct {
+				DatasourceUID string `json:"datasourceUid"`
+			} `json:"data"`
+		} `json:"rules"`
+	}
+
+	if err := json.Unmarshal([]byte(calls[0].Args[1]), &posted); err != nil {
+		t.Fatal(err)
+	}
+
+	if posted.Interval != 300 {
+		t.Errorf("expected an interval of 300 seconds, got %d", posted.Interval)
+	}
+
+	if len(posted.Rules) != 1 || len(posted.Rules[0].Data) != 2 {
+		t.Fatalf("expected one rule with two queries, got %+v", posted.Rules)
+	}
+
+	if posted.Rules[0].Data[0].DatasourceUID != dataSourceId || posted.Rules[0].Data[1].DatasourceUID != "__expr__" {
+		t.Errorf("expected data sources to be resolved, got %+v", posted.Rules[0].Data)
+	}
+
+	if posted.Rules[0].NoDataState != "NoData" {
+		t.Errorf("expected noDataState to default to NoData, got %q", posted.Rules[0].NoDataState)
+	}
+}
+
+func TestAlertRuleGroupWaitsForFolder(t *testing.T) {
+	folder := newFolder("alerts", `{"title": "alerts"}`)
+	group := newAlertRuleGroup("cpu", "alerts")
+
+	f := newFixture(t, folder, group)
+	c := f.newController(newAlertRuleGroupController)
+
+	if err := f.sync(c, newItem(group, AddOrUpdate, "", t)); err == nil {
+		t.Fatal("expected an error for a folder that has not been synced")
+	}
+
+	if calls := f.grafanaClient.CallsTo("PostAlertRuleGroup"); len(calls) != 0 {
+		t.Errorf("expected nothing to be posted, got %v", calls)
+	}
+}
+
+func TestRenamedAlertRuleGroupDeletesOldGroup(t *testing.T) {
+	f := newFixture(t)
+
+	folder := f.createFolder("alerts")
+
+	rule := highCPURule()
+	rule.Queries = rule.Queries[1:]
+
+	oldId, err := f.grafanaClient.PostAlertRuleGroup(context.Background(), folder.Status.GrafanaID, `{"title": "old", "interval": 60, "rules": [{"title": "HighCPU"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	group := newAlertRuleGroup("cpu", "alerts", rule)
+	group.Spec.Title = "new"
+	group.Status.GrafanaID = oldId
+
+	f = f.withObjects(folder, group)
+	c := f.newController(newAlertRuleGroupController)
+
+	if err := f.sync(c, newItem(group, AddOrUpdate, oldId, t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.grafanaClient.GetAlertRuleGroup(context.Background(), oldId); !grafana.IsNotFound(err) {
+		t.Errorf("expected the old group to be deleted, got %v", err)
+	}
+
+	id := f.getAlertRuleGroup("cpu").Status.GrafanaID
+	if id != grafana.AlertRuleGroupId(folder.Status.GrafanaID, "new") {
+		t.Errorf("expected the group to be posted under its new title, got %q", id)
+	}
+}
+
+func TestInvalidAlertRuleGroupIntervalIsRejected(t *testing.T) {
+	f := newFixture(t)
+
+	folder := f.createFolder("alerts")
+
+	group := newAlertRuleGroup("cpu", "alerts")
+	group.Spec.Interval = "1.5s"
+
+	f = f.withObjects(folder, group)
+	c := f.newController(newAlertRuleGroupController)
+
+	err := f.sync(c, newItem(group, AddOrUpdate, "", t))
+	if err == nil {
+		t.Fatal("expected an interval that is not a whole number of seconds to be rejected")
+	}
+
+	if grafana.IsRetryable(err) {
+		t.Errorf("expected an invalid interval to not be retried: %v", err)
+	}
+}
+
+func TestInvalidAlertRuleQueryModelIsNotRetried(t *testing.T) {
+	f := newFixture(t)
+
+	folder := f.createFolder("alerts")
+
+	rule := highCPURule()
+	rule.Queries = []v1alpha1.AlertQuery{{RefID: "B", DataSourceUID: "__expr__", Model: "{ not json"}}
+
+	group := newAlertRuleGroup("cpu", "alerts", rule)
+
+	f = f.withObjects(folder, group)
+	c := f.newController(newAlertRuleGroupController)
+
+	err := f.sync(c, newItem(group, AddOrUpdate, "", t))
+	if err == nil {
+		t.Fatal("expected a query model that is not json to be rejected")
+	}
+
+	if grafana.IsRetryable(err) {
+		t.Errorf("expected a query model that is not json to not be retried: %v", err)
+	}
+}
+
+func TestResyncOnlyDeletesManagedAlertRuleGroups(t *testing.T) {
+	f := newFixture(t)
+
+	folder := f.createFolder("alerts")
+
+	rule := highCPURule()
+	rule.Queries = rule.Queries[1:]
+
+	group := newAlertRuleGroup("cpu", "alerts", rule)
+
+	f = f.withObjects(folder, group)
+	c := f.newController(newAlertRuleGroupController)
+
+	if err := f.sync(c, newItem(group, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	managedId := f.getAlertRuleGroup("cpu").Status.GrafanaID
+
+	// e.g. created by terraform, which uses the same provisioning api
+	unmanagedId, err := f.grafanaClient.PostAlertRuleGroup(context.Background(), folder.Status.GrafanaID, `{"title": "terraform", "interval": 60, "rules": [{"title": "HighCPU"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the group is deleted without its delete being handled
+	if err := f.informers.Grafana().V1alpha1().AlertRuleGroups().Informer().GetIndexer().Delete(group); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.resyncDeletedObjects(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.grafanaClient.GetAlertRuleGroup(context.Background(), managedId); err == nil {
+		t.Errorf("expected managed group %s to be deleted", managedId)
+	}
+
+	if _, err := f.grafanaClient.GetAlertRuleGroup(context.Background(), unmanagedId); err != nil {
+		t.Errorf("expected group %s created by another client to be kept: %v", unmanagedId, err)
+	}
+}
@@ -0,0 +1,225 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	clientset "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned"
+	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions/grafana/v1alpha1"
+	listers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/listers/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+// AnnotationSyncer is the controller implementation for Annotation resources
+type AnnotationSyncer struct {
+	grafanaAnnotationsLister   listers.AnnotationLister
+	grafanaDashboardsLister    listers.DashboardLister
+	grafanaOrganizationsLister listers.OrganizationLister
+	grafanaClient              grafana.Interface
+	grafanaclientset           clientset.Interface
+}
+
+// NewAnnotationController returns a new grafana Annotation controller
+func NewAnnotationController(
+	grafanaclientset clientset.Interface,
+	kubeclientset kubernetes.Interface,
+	grafanaClient grafana.Interface,
+	grafanaAnnotationInformer informers.AnnotationInformer,
+	grafanaDashboardInformer informers.DashboardInformer,
+	grafanaOrganizationInformer informers.OrganizationInformer) *Controller {
+
+	syncer := &AnnotationSyncer{
+		grafanaAnnotationsLister:   grafanaAnnotationInformer.Lister(),
+		grafanaDashboardsLister:    grafanaDashboardInformer.Lister(),
+		grafanaOrganizationsLister: grafanaOrganizationInformer.Lister(),
+		grafanaClient:              grafanaClient,
+		grafanaclientset:           grafanaclientset,
+	}
+
+	controller := NewController(grafanaAnnotationInformer.Informer(),
+		kubeclientset,
+		syncer)
+	controller.watchOrganizations(grafanaOrganizationInformer)
+
+	return controller
+}
+
+func (s *AnnotationSyncer) getType() string {
+	return prometheus.TypeAnnotation
+}
+
+func (s *AnnotationSyncer) getRuntimeObjectByName(name string, namespace string) (runtime.Object, error) {
+	return s.grafanaAnnotationsLister.Annotations(namespace).Get(name)
+}
+
+func (s *AnnotationSyncer) deleteObjectById(ctx context.Context, id string) error {
+	return s.grafanaClient.DeleteAnnotation(ctx, id)
+}
+
+func (s *AnnotationSyncer) updateObject(ctx context.Context, object runtime.Object) error {
+
+	grafanaAnnotation, ok := object.(*v1alpha1.Annotation)
+	if !ok {
+		return fmt.Errorf("expected annotation in but got %#v", object)
+	}
+
+	ctx, orgID, grafanaID, err := targetOrganization(ctx,
+		s.grafanaOrganizationsLister,
+		grafanaAnnotation.Namespace,
+		grafanaAnnotation.Spec.OrganizationName,
+		grafanaAnnotation.Status.GrafanaOrgID,
+		grafanaAnnotation.Status.GrafanaID,
+		s.deleteObjectById)
+	if err != nil {
+		return err
+	}
+
+	var dashboardUid string
+
+	if grafanaAnnotation.Spec.DashboardName != "" {
+		dashboard, err := s.grafanaDashboardsLister.Dashboards(grafanaAnnotation.Namespace).Get(grafanaAnnotation.Spec.DashboardName)
+		if err != nil {
+			return err
+		}
+
+		if dashboard.Status.GrafanaID == grafana.NO_ID {
+			return fmt.Errorf("dashboard %s has not been created in grafana yet", dashboard.Name)
+		}
+
+		if dashboard.Status.GrafanaOrgID != orgID {
+			return fmt.Errorf("dashboard %s is not in the same organization as annotation %s", dashboard.Name, grafanaAnnotation.Name)
+		}
+
+		dashboardUid = dashboard.Status.GrafanaID
+	} else if grafanaAnnotation.Spec.PanelID != 0 {
+		return grafana.Invalidf("annotation %s has a panelId but no dashboardName", grafanaAnnotation.Name)
+	}
+
+	annotationJson, err := annotationJson(grafanaAnnotation)
+	if err != nil {
+		return err
+	}
+
+	// grafana cannot move an annotation to another dashboard or panel
+	if grafanaID != grafana.NO_ID &&
+		(grafanaAnnotation.Status.DashboardUID != dashboardUid || grafanaAnnotation.Status.PanelID != grafanaAnnotation.Spec.PanelID) {
+
+		if err := s.deleteObjectById(ctx, grafanaID); err != nil {
+			return err
+		}
+
+		grafanaID = grafana.NO_ID
+	}
+
+	id, err := s.grafanaClient.PostAnnotation(ctx, annotationJson, dashboardUid, grafanaID)
+
+	if err != nil {
+		return err
+	}
+
+	grafanaAnnotationCopy := grafanaAnnotation.DeepCopy()
+	grafanaAnnotationCopy.Status.GrafanaID = id
+	grafanaAnnotationCopy.Status.GrafanaOrgID = orgID
+	grafanaAnnotationCopy.Status.DashboardUID = dashboardUid
+	grafanaAnnotationCopy.Status.PanelID = grafanaAnnotation.Spec.PanelID
+
+	_, err = s.grafanaclientset.GrafanaV1alpha1().Annotations(grafanaAnnotation.Namespace).UpdateStatus(grafanaAnnotationCopy)
+
+	return err
+}
+
+// annotationJson returns the text, tags, time range and panel PostAnnotation expects.  Times are
+// epoch milliseconds.  An annotation without an end time ends when it starts.
+func annotationJson(annotation *v1alpha1.Annotation) (string, error) {
+	start := annotation.Spec.Time.Time
+	if start.IsZero() {
+		start = annotation.CreationTimestamp.Time
+	}
+
+	end := start
+	if annotation.Spec.TimeEnd != nil {
+		end = annotation.Spec.TimeEnd.Time
+	}
+
+	if end.Before(start) {
+		return "", grafana.Invalidf("annotation %s ends before it starts", annotation.Name)
+	}
+
+	tags := annotation.Spec.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	annotationJson, err := json.Marshal(map[string]interface{}{
+		"text":    annotation.Spec.Text,
+		"tags":    tags,
+		"time":    epochMillis(start),
+		"timeEnd": epochMillis(end),
+		"panelId": annotation.Spec.PanelID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(annotationJson), nil
+}
+
+func epochMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+func (s *AnnotationSyncer) getAllKubernetesObjectIDs(orgID string) ([]string, error) {
+	annotations, err := s.grafanaAnnotationsLister.List(labels.Everything())
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+
+	for _, annotation := range annotations {
+		// objects without an id may be in any organization
+		if annotation.Status.GrafanaOrgID != orgID && annotation.Status.GrafanaID != grafana.NO_ID {
+			continue
+		}
+
+		ids = append(ids, annotation.Status.GrafanaID)
+	}
+
+	return ids, nil
+}
+
+func (s *AnnotationSyncer) getAllGrafanaObjectIDs(ctx context.Context) ([]string, error) {
+	return s.grafanaClient.GetAllAnnotationIds(ctx)
+}
+
+func (s *AnnotationSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
+	var key string
+	var err error
+	var annotation *v1alpha1.Annotation
+	var ok bool
+
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	if annotation, ok = obj.(*v1alpha1.Annotation); !ok {
+		utilruntime.HandleError(fmt.Errorf("expected annotation in workqueue but got %#v", obj))
+		return nil
+	}
+
+	item := NewWorkQueueItem(key, annotation.DeepCopyObject(), annotation.Status.GrafanaID, annotation.Status.GrafanaOrgID)
+
+	return &item
+}
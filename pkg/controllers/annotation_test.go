@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+)
+
+func newAnnotation(name string, text string, dashboardName string) *v1alpha1.Annotation {
+	return &v1alpha1.Annotation{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: newObjectMeta(name),
+		Spec: v1alpha1.AnnotationSpec{
+			Text:          text,
+			Time:          metav1.NewTime(time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC)),
+			DashboardName: dashboardName,
+		},
+	}
+}
+
+func newAnnotationController(f *fixture) *Controller {
+	return NewAnnotationController(f.client, f.kubeclient, f.grafanaClient,
+		f.informers.Grafana().V1alpha1().Annotations(),
+		f.informers.Grafana().V1alpha1().Dashboards(),
+		f.informers.Grafana().V1alpha1().Organizations())
+}
+
+func (f *fixture) getAnnotation(name string) *v1alpha1.Annotation {
+	annotation, err := f.client.GrafanaV1alpha1().Annotations(metav1.NamespaceDefault).Get(name, metav1.GetOptions{})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return annotation
+}
+
+// createDashboard creates a dashboard in the fake grafana and returns a Dashboard object recording
+// its uid
+func (f *fixture) createDashboard(name string) *v1alpha1.Dashboard {
+	dashboard := newDashboard(name, `{"title": "`+name+`"}`, "")
+
+	uid, err := f.grafanaClient.PostDashboard(context.Background(), dashboard.Spec.JSON, "")
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	dashboard.Status.GrafanaID = uid
+
+	return dashboard
+}
+
+func TestCreatesAnnotation(t *testing.T) {
+	f := newFixture(t)
+
+	dashboard := f.createDashboard("api")
+
+	annotation := newAnnotation("maintenance", "database upgrade", "api")
+	annotation.Spec.Tags = []string{"maintenance"}
+	annotation.Spec.PanelID = 2
+	end := metav1.NewTime(annotation.Spec.Time.Add(time.Hour))
+	annotation.Spec.TimeEnd = &end
+
+	f = f.withObjects(dashboard, annotation)
+	c := f.newController(newAnnotationController)
+
+	if err := f.sync(c, newItem(annotation, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	status := f.getAnnotation("maintenance").Status
+	if status.GrafanaID == "" || status.DashboardUID != dashboard.Status.GrafanaID || status.PanelID != 2 {
+		t.Fatalf("expected the status to record the annotation's id and placement, got %+v", status)
+	}
+
+	object, err := f.grafanaClient.GetAnnotation(context.Background(), status.GrafanaID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var posted struct {
+		DashboardUID string   `json:"dashboardUID"`
+		PanelID      int64    `json:"panelId"`
+		Text         string   `json:"text"`
+		Tags         []string `json:"tags"`
+		Time         int64    `json:"time"`
+		TimeEnd      int64    `json:"timeEnd"`
+	}
+
+	if err := json.Unmarshal([]byte(object.JSON), &posted); err != nil {
+		t.Fatal(err)
+	}
+
+	if posted.DashboardUID != dashboard.Status.GrafanaID || posted.PanelID != 2 {
+		t.Errorf("expected the annotation on panel 2 of dashboard %s, got %+v", dashboard.Status.GrafanaID, posted)
+	}
+
+	if posted.Text != "database upgrade" || !reflect.DeepEqual(posted.Tags, []string{"maintenance"}) {
+		t.Errorf("expected the text and tags to be posted, got %+v", posted)
+	}
+
+	if posted.Time != 1709330400000 || posted.TimeEnd != 1709334000000 {
+		t.Errorf("expected epoch milliseconds, got %d to %d", posted.Time, posted.TimeEnd)
+	}
+}
+
+func TestAnnotationTimeDefaultsToCreation(t *testing.T) {
+	annotation := newAnnotation("incident", "outage", "")
+	annotation.Spec.Time = metav1.Time{}
+	annotation.CreationTimestamp = metav1.NewTime(time.Unix(1700000000, 0))
+
+	annotationJson, err := annotationJson(annotation)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var posted struct {
+		Time    int64 `json:"time"`
+		TimeEnd int64 `json:"timeEnd"`
+	}
+
+	if err := json.Unmarshal([]byte(annotationJson), &posted); err != nil {
+		t.Fatal(err)
+	}
+
+	if posted.Time != 1700000000000 || posted.TimeEnd != posted.Time {
+		t.Errorf("expected a point annotation at the creation time, got %+v", posted)
+	}
+}
+
+func TestAnnotationEndingBeforeStartIsRejected(t *testing.T) {
+	annotation := newAnnotation("incident", "outage", "")
+	end := metav1.NewTime(annotation.Spec.Time.Add(-time.Hour))
+	annotation.Spec.TimeEnd = &end
+
+	if _, err := annotationJson(annotation); err == nil {
+		t.Error("expected an error for an annotation that ends before it starts")
+	}
+}
+
+func TestMovedAnnotationIsRecreated(t *testing.T) {
+	f := newFixture(t)
+
+	oldDashboard := f.createDashboard("old")
+	newDashboard := f.createDashboard("new")
+
+	id, err := f.grafanaClient.PostAnnotation(context.Background(), `{"text": "outage"}`, oldDashboard.Status.GrafanaID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	annotation := newAnnotation("incident", "outage", "new")
+	annotation.Status.GrafanaID = id
+	annotation.Status.DashboardUID = oldDashboard.Status.GrafanaID
+
+	f = f.withObjects(oldDashboard, newDashboard, annotation)
+	c := f.newController(newAnnotationController)
+
+	if err := f.sync(c, newItem(annotation, AddOrUpdate, id, t)); err != nil {
+		t.Fatal(err)
+	}
+
+	status := f.getAnnotation("incident").Status
+	if status.GrafanaID == id || status.DashboardUID != newDashboard.Status.GrafanaID {
+		t.Errorf("expected a new annotation on dashboard %s, got %+v", newDashboard.Status.GrafanaID, status)
+	}
+
+	if ids, _ := f.grafanaClient.GetAllAnnotationIds(context.Background()); !reflect.DeepEqual(ids, []string{status.GrafanaID}) {
+		t.Errorf("expected the old annotation to be deleted, got %v", ids)
+	}
+}
+
+func TestUpdatesAnnotationInPlace(t *testing.T) {
+	f := newFixture(t)
+
+	id, err := f.grafanaClient.PostAnnotation(context.Background(), `{"text": "outage"}`, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	annotation := newAnnotation("incident", "outage resolved", "")
+	annotation.Status.GrafanaID = id
+
+	f = f.withObjects(annotation)
+	c := f.newController(newAnnotationController)
+
+	if err := f.sync(c, newItem(annotation, AddOrUpdate, id, t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := f.getAnnotation("incident").Status.GrafanaID; got != id {
+		t.Errorf("expected the annotation to be updated in place, got %q", got)
+	}
+
+	if calls := f.grafanaClient.CallsTo("DeleteAnnotation"); len(calls) != 0 {
+		t.Errorf("expected nothing to be deleted, got %v", calls)
+	}
+}
+
+func TestDeletesAnnotation(t *testing.T) {
+	annotation := newAnnotation("incident", "outage", "")
+
+	f := newFixture(t)
+
+	id, err := f.grafanaClient.PostAnnotation(context.Background(), `{"text": "outage"}`, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := f.newController(newAnnotationController)
+
+	if err := f.sync(c, newItem(annotation, Delete, id, t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if ids, _ := f.grafanaClient.GetAllAnnotationIds(context.Background()); len(ids) != 0 {
+		t.Errorf("expected annotation to be deleted from grafana but found %v", ids)
+	}
+
+	f.expectEvent("Normal " + SuccessDeleted)
+}
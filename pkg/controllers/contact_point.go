@@ -0,0 +1,318 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	clientset "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned"
+	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions/grafana/v1alpha1"
+	listers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/listers/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+// ContactPointSyncer is the controller implementation for ContactPoint resources
+type ContactPointSyncer struct {
+	grafanaContactPointsLister listers.ContactPointLister
+	grafanaOrganizationsLister listers.OrganizationLister
+	grafanaClient              grafana.Interface
+	grafanaclientset           clientset.Interface
+	secretsLister              corelisters.SecretLister
+
+	// other provisioning clients, like terraform, create contact points with the same provenance.
+	// only recorded ones are garbage collected
+	managedIDs *managedIDs
+}
+
+// NewContactPointController returns a new grafana ContactPoint controller.  Contact points are
+// synced again whenever a Secret their secure settings reference changes.
+func NewContactPointController(
+	grafanaclientset clientset.Interface,
+	kubeclientset kubernetes.Interface,
+	grafanaClient grafana.Interface,
+	grafanaContactPointInformer informers.ContactPointInformer,
+	grafanaOrganizationInformer informers.OrganizationInformer,
+	secretInformer coreinformers.SecretInformer) *Controller {
+
+	syncer := &ContactPointSyncer{
+		grafanaContactPointsLister: grafanaContactPointInformer.Lister(),
+		grafanaOrganizationsLister: grafanaOrganizationInformer.Lister(),
+		grafanaClient:              grafanaClient,
+		grafanaclientset:           grafanaclientset,
+		secretsLister:              secretInformer.Lister(),
+		managedIDs:                 newManagedIDs(),
+	}
+
+	controller := NewController(grafanaContactPointInformer.Informer(),
+		kubeclientset,
+		syncer)
+	controller.watchOrganizations(grafanaOrganizationInformer)
+	syncer.watchSecrets(controller, secretInformer)
+
+	return controller
+}
+
+// watchSecrets syncs the contact points in the namespace of a Secret that reference it
+func (s *ContactPointSyncer) watchSecrets(controller *Controller, secretInformer coreinformers.SecretInformer) {
+	enqueueContactPoints := func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+
+		secret, err := meta.Accessor(obj)
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+
+		contactPoints, err := s.grafanaContactPointsLister.ContactPoints(secret.GetNamespace()).List(labels.Everything())
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+
+		for _, contactPoint := range contactPoints {
+			for _, setting := range contactPoint.Spec.SecureSettings {
+				if setting.SecretKeyRef.Name == secret.GetName() {
+					controller.enqueueWorkQueueItem(contactPoint, AddOrUpdate)
+					break
+				}
+			}
+		}
+	}
+
+	controller.watchInformer(secretInformer.Informer(), cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueueContactPoints,
+		UpdateFunc: func(old, new interface{}) {
+			oldSecret, oldOk := old.(*corev1.Secret)
+			newSecret, newOk := new.(*corev1.Secret)
+
+			// periodic resyncs change nothing
+			if oldOk && newOk && oldSecret.ResourceVersion == newSecret.ResourceVersion {
+				return
+			}
+
+			enqueueContactPoints(new)
+		},
+		DeleteFunc: enqueueContactPoints,
+	})
+}
+
+func (s *ContactPointSyncer) getType() string {
+	return prometheus.TypeContactPoint
+}
+
+func (s *ContactPointSyncer) getRuntimeObjectByName(name string, namespace string) (runtime.Object, error) {
+	return s.grafanaContactPointsLister.ContactPoints(namespace).Get(name)
+}
+
+func (s *ContactPointSyncer) deleteObjectById(ctx context.Context, id string) error {
+	if err := s.grafanaClient.DeleteContactPoint(ctx, id); err != nil {
+		return err
+	}
+
+	s.managedIDs.remove(grafana.OrgID(ctx), id)
+	return nil
+}
+
+func (s *ContactPointSyncer) updateObject(ctx context.Context, object runtime.Object) error {
+
+	grafanaContactPoint, ok := object.(*v1alpha1.ContactPoint)
+	if !ok {
+		return fmt.Errorf("expected contact point in but got %#v", object)
+	}
+
+	ctx, orgID, grafanaID, err := targetOrganization(ctx,
+		s.grafanaOrganizationsLister,
+		grafanaContactPoint.Namespace,
+		grafanaContactPoint.Spec.OrganizationName,
+		grafanaContactPoint.Status.GrafanaOrgID,
+		grafanaContactPoint.Status.GrafanaID,
+		s.deleteObjectById)
+	if err != nil {
+		return err
+	}
+
+	contactPointJson, err := s.contactPointJson(grafanaContactPoint)
+	if err != nil {
+		return err
+	}
+
+	id, err := s.grafanaClient.PostContactPoint(ctx, contactPointJson, grafanaID)
+
+	if err != nil {
+		return err
+	}
+
+	s.managedIDs.add(orgID, id)
+
+	grafanaContactPointCopy := grafanaContactPoint.DeepCopy()
+	grafanaContactPointCopy.Status.GrafanaID = id
+	grafanaContactPointCopy.Status.GrafanaOrgID = orgID
+
+	_, err = s.grafanaclientset.GrafanaV1alpha1().ContactPoints(grafanaContactPoint.Namespace).UpdateStatus(grafanaContactPointCopy)
+
+	return err
+}
+
+// contactPointJson adds the name and the secure settings of a contact point to its json.  Secrets
+// are read from the contact point's namespace.  Optional settings whose Secret or key does not
+// exist are left out.
+func (s *ContactPointSyncer) contactPointJson(contactPoint *v1alpha1.ContactPoint) (string, error) {
+	var model map[string]interface{}
+
+	if err := json.Unmarshal([]byte(contactPoint.Spec.JSON), &model); err != nil {
+		return "", err
+	}
+
+	if name, _ := model["name"].(string); name == "" {
+		model["name"] = contactPoint.Name
+	}
+
+	settings, ok := model["settings"].(map[string]interface{})
+	if !ok {
+		if model["settings"] != nil {
+			return "", grafana.Invalidf("settings of contact point %s must be an object", contactPoint.Name)
+		}
+
+		settings = make(map[string]interface{})
+		model["settings"] = settings
+	}
+
+	secrets := make(map[string]*corev1.Secret)
+
+	for _, setting := range contactPoint.Spec.SecureSettings {
+		ref := setting.SecretKeyRef
+
+		secret, ok := secrets[ref.Name]
+		if !ok {
+			var err error
+
+			secret, err = s.secretsLister.Secrets(contactPoint.Namespace).Get(ref.Name)
+			if k8serrors.IsNotFound(err) && ref.Optional != nil && *ref.Optional {
+				continue
+			}
+			if err != nil {
+				return "", err
+			}
+
+			secrets[ref.Name] = secret
+		}
+
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			if ref.Optional != nil && *ref.Optional {
+				continue
+			}
+
+			return "", fmt.Errorf("secret %s has no key %s", ref.Name, ref.Key)
+		}
+
+		settings[setting.Name] = string(value)
+	}
+
+	contactPointJson, err := json.Marshal(model)
+	if err != nil {
+		return "", err
+	}
+
+	return string(contactPointJson), nil
+}
+
+func (s *ContactPointSyncer) getAllKubernetesObjectIDs(orgID string) ([]string, error) {
+	contactPoints, err := s.grafanaContactPointsLister.List(labels.Everything())
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+
+	for _, contactPoint := range contactPoints {
+		// objects without an id may be in any organization
+		if contactPoint.Status.GrafanaOrgID != orgID && contactPoint.Status.GrafanaID != grafana.NO_ID {
+			continue
+		}
+
+		if contactPoint.Status.GrafanaID != grafana.NO_ID {
+			s.managedIDs.add(orgID, contactPoint.Status.GrafanaID)
+		}
+
+		ids = append(ids, contactPoint.Status.GrafanaID)
+	}
+
+	return ids, nil
+}
+
+// getAllGrafanaObjectIDs returns the contact points the syncer created or found in the status of
+// a ContactPoint.  Contact points created by hand or by other provisioning clients are never
+// deleted.
+func (s *ContactPointSyncer) getAllGrafanaObjectIDs(ctx context.Context) ([]string, error) {
+	ids, err := s.grafanaClient.GetAllContactPointIds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.managedIDs.filter(grafana.OrgID(ctx), ids), nil
+}
+
+func (s *ContactPointSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
+	var key string
+	var err error
+	var contactPoint *v1alpha1.ContactPoint
+	var ok bool
+
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	if contactPoint, ok = obj.(*v1alpha1.ContactPoint); !ok {
+		utilruntime.HandleError(fmt.Errorf("expected contact point in workqueue but got %#v", obj))
+		return nil
+	}
+
+	item := NewWorkQueueItem(key, contactPoint.DeepCopyObject(), contactPoint.Status.GrafanaID, contactPoint.Status.GrafanaOrgID)
+
+	return &item
+}
+
+// contactPointReceiver returns the name notification policies route to the contact point name in
+// namespace by.  The contact point must have been created in the organization orgID.
+func contactPointReceiver(lister listers.ContactPointLister, namespace string, name string, orgID string) (string, error) {
+	contactPoint, err := lister.ContactPoints(namespace).Get(name)
+	if err != nil {
+		return "", err
+	}
+
+	if contactPoint.Status.GrafanaID == grafana.NO_ID {
+		return "", fmt.Errorf("contact point %s has not been created in grafana yet", name)
+	}
+
+	if contactPoint.Status.GrafanaOrgID != orgID {
+		return "", fmt.Errorf("contact point %s is not in the same organization as its route", name)
+	}
+
+	var model struct {
+		Name string `json:"name"`
+	}
+
+	if err := json.Unmarshal([]byte(contactPoint.Spec.JSON), &model); err != nil {
+		return "", err
+	}
+
+	return defaultString(model.Name, contactPoint.Name), nil
+}
@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+)
+
+func newContactPoint(name string, contactPointJson string, secureSettings ...v1alpha1.SecureSetting) *v1alpha1.ContactPoint {
+	return &v1alpha1.ContactPoint{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: newObjectMeta(name),
+		Spec: v1alpha1.ContactPointSpec{
+			JSON:           contactPointJson,
+			SecureSettings: secureSettings,
+		},
+	}
+}
+
+func newSecureSetting(name string, secretName string, key string) v1alpha1.SecureSetting {
+	return v1alpha1.SecureSetting{
+		Name: name,
+		SecretKeyRef: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+			Key:                  key,
+		},
+	}
+}
+
+func newContactPointController(f *fixture) *Controller {
+	return NewContactPointController(f.client, f.kubeclient, f.grafanaClient,
+		f.informers.Grafana().V1alpha1().ContactPoints(),
+		f.informers.Grafana().V1alpha1().Organizations(),
+		f.kubeInformers.Core().V1().Secrets())
+}
+
+func (f *fixture) getContactPoint(name string) *v1alpha1.ContactPoint {
+	contactPoint, err := f.client.GrafanaV1alpha1().ContactPoints(metav1.NamespaceDefault).Get(name, metav1.GetOptions{})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return contactPoint
+}
+
+func TestCreatesContactPointWithSecureSettings(t *testing.T) {
+	contactPoint := newContactPoint("slack",
+		`{"type": "slack", "settings": {"recipient": "#alerts"}}`,
+		newSecureSetting("url", "slack", "webhook"))
+
+	f := newFixture(t, contactPoint)
+	c := f.newController(newContactPointController)
+
+	f.index(&corev1.Secret{
+		ObjectMeta: newObjectMeta("slack"),
+		Data:       map[string][]byte{"webhook": []byte("https://hooks.slack.com/secret")},
+	})
+
+	if err := f.sync(c, newItem(contactPoint, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	uid := f.getContactPoint("slack").Status.GrafanaID
+	if uid == "" {
+		t.Fatal("expected the contact point status to be updated with its uid")
+	}
+
+	object, err := f.grafanaClient.GetContactPoint(context.Background(), uid)
+	if err != nil {
+		t.Fatalf("expected contact point in grafana: %v", err)
+	}
+
+	var posted struct {
+		Name     string            `json:"name"`
+		Settings map[string]string `json:"settings"`
+	}
+
+	if err := json.Unmarshal([]byte(object.JSON), &posted); err != nil {
+		t.Fatal(err)
+	}
+
+	if posted.Name != "slack" {
+		t.Errorf("expected the name to default to the object's name, got %q", posted.Name)
+	}
+
+	if posted.Settings["url"] != "https://hooks.slack.com/secret" || posted.Settings["recipient"] != "#alerts" {
+		t.Errorf("expected the secure setting to be merged into the settings, got %v", posted.Settings)
+	}
+}
+
+func TestContactPointWithMissingSecretIsNotPosted(t *testing.T) {
+	contactPoint := newContactPoint("pagerduty",
+		`{"type": "pagerduty"}`,
+		newSecureSetting("integrationKey", "pagerduty", "key"))
+
+	f := newFixture(t, contactPoint)
+	c := f.newController(newContactPointController)
+
+	if err := f.sync(c, newItem(contactPoint, AddOrUpdate, "", t)); err == nil {
+		t.Fatal("expected an error for a missing secret")
+	}
+
+	if calls := f.grafanaClient.CallsTo("PostContactPoint"); len(calls) != 0 {
+		t.Errorf("expected nothing to be posted, got %v", calls)
+	}
+}
+
+func TestOptionalSecureSettingIsSkipped(t *testing.T) {
+	optional := true
+
+	setting := newSecureSetting("url", "webhook", "url")
+	setting.SecretKeyRef.Optional = &optional
+
+	contactPoint := newContactPoint("webhook", `{"type": "webhook", "settings": {"url": "http://example.com"}}`, setting)
+
+	f := newFixture(t, contactPoint)
+	c := f.newController(newContactPointController)
+
+	if err := f.sync(c, newItem(contactPoint, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if f.getContactPoint("webhook").Status.GrafanaID == "" {
+		t.Error("expected the contact point to be created without the optional setting")
+	}
+}
+
+func TestResyncOnlyDeletesManagedContactPoints(t *testing.T) {
+	contactPoint := newContactPoint("email", `{"type": "email", "settings": {"addresses": "oncall@example.com"}}`)
+
+	f := newFixture(t, contactPoint)
+	c := f.newController(newContactPointController)
+
+	if err := f.sync(c, newItem(contactPoint, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	managedUid := f.getContactPoint("email").Status.GrafanaID
+
+	// e.g. created by terraform, which uses the same provisioning api
+	unmanagedUid, err := f.grafanaClient.PostContactPoint(context.Background(), `{"name": "terraform", "type": "email", "settings": {"addresses": "ops@example.com"}}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the contact point is deleted without its delete being handled
+	if err := f.informers.Grafana().V1alpha1().ContactPoints().Informer().GetIndexer().Delete(contactPoint); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.resyncDeletedObjects(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.grafanaClient.GetContactPoint(context.Background(), managedUid); err == nil {
+		t.Errorf("expected managed contact point %s to be deleted", managedUid)
+	}
+
+	if _, err := f.grafanaClient.GetContactPoint(context.Background(), unmanagedUid); err != nil {
+		t.Errorf("expected contact point %s created by another client to be kept: %v", unmanagedUid, err)
+	}
+}
+
+func TestOptionalSecureSettingWithMissingKeyIsSkipped(t *testing.T) {
+	optional := true
+
+	setting := newSecureSetting("url", "webhook", "url")
+	setting.SecretKeyRef.Optional = &optional
+
+	contactPoint := newContactPoint("webhook", `{"type": "webhook", "settings": {"url": "http://example.com"}}`, setting)
+
+	f := newFixture(t, contactPoint)
+	c := f.newController(newContactPointController)
+
+	f.index(&corev1.Secret{
+		ObjectMeta: newObjectMeta("webhook"),
+		Data:       map[string][]byte{"token": []byte("secret")},
+	})
+
+	if err := f.sync(c, newItem(contactPoint, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if f.getContactPoint("webhook").Status.GrafanaID == "" {
+		t.Error("expected the contact point to be created without the optional setting")
+	}
+}
+
+func TestSecretChangeSyncsContactPointsReferencingIt(t *testing.T) {
+	slack := newContactPoint("slack", `{"type": "slack"}`, newSecureSetting("url", "slack", "webhook"))
+	email := newContactPoint("email", `{"type": "email", "settings": {"addresses": "oncall@example.com"}}`)
+
+	f := newFixture(t, slack, email)
+	c := f.newController(newContactPointController)
+
+	secretInformer := f.kubeInformers.Core().V1().Secrets().Informer()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	f.kubeInformers.Start(stopCh)
+
+	if !cache.WaitForCacheSync(stopCh, secretInformer.HasSynced) {
+		t.Fatal("secret informer did not sync")
+	}
+
+	// secrets no contact point references are ignored
+	if _, err := f.kubeclient.CoreV1().Secrets(metav1.NamespaceDefault).Create(&corev1.Secret{ObjectMeta: newObjectMeta("unused")}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := f.kubeclient.CoreV1().Secrets(metav1.NamespaceDefault).Create(&corev1.Secret{
+		ObjectMeta: newObjectMeta("slack"),
+		Data:       map[string][]byte{"webhook": []byte("https://hooks.slack.com/rotated")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return c.workqueue.Len() > 0, nil
+	})
+	if err != nil {
+		t.Fatal("expected the contact point to be enqueued")
+	}
+
+	item, _ := c.workqueue.Get()
+	if key := item.(WorkQueueItem).key; key != "default/slack" || c.workqueue.Len() != 0 {
+		t.Errorf("expected only default/slack to be enqueued, got %v and %d more", key, c.workqueue.Len())
+	}
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned/scheme"
@@ -9,9 +10,13 @@ import (
 	"time"
 
 	grafanascheme "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned/scheme"
+	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions/grafana/v1alpha1"
+	listers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/listers/grafana/v1alpha1"
 
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
@@ -28,12 +33,25 @@ const (
 
 	SuccessDeleted         = "Deleted"
 	MessageResourceDeleted = "Grafana Object deleted successfully"
+
+	ErrSyncFailed = "SyncFailed"
 )
 
 type Controller struct {
 	syncer         Syncer
 	informerSynced cache.InformerSynced
 
+	// organizationsLister is nil for controllers whose objects do not belong to an organization
+	organizationsLister listers.OrganizationLister
+	organizationsSynced cache.InformerSynced
+
+	// watchedSynced are the informers of other objects the controller's objects depend on
+	watchedSynced []cache.InformerSynced
+
+	// quiet controllers do not record events for successful syncs and deletes.  It is for
+	// controllers that watch objects they do not own.
+	quiet bool
+
 	workqueue workqueue.RateLimitingInterface
 	recorder  record.EventRecorder
 }
@@ -79,6 +97,20 @@ func NewController(informer cache.SharedIndexInformer,
 	return controller
 }
 
+// watchOrganizations makes the controller resync deleted objects in every organization
+// created by an Organization object as well as the default organization.
+func (c *Controller) watchOrganizations(informer informers.OrganizationInformer) {
+	c.organizationsLister = informer.Lister()
+	c.organizationsSynced = informer.Informer().HasSynced
+}
+
+// watchInformer calls handler when the objects of another informer change and waits for that
+// informer to sync before starting workers.
+func (c *Controller) watchInformer(informer cache.SharedIndexInformer, handler cache.ResourceEventHandler) {
+	informer.AddEventHandler(handler)
+	c.watchedSynced = append(c.watchedSynced, informer.HasSynced)
+}
+
 // Run will set up the event handlers for types we are interested in, as well
 // as syncing informer caches and starting workers. It will block until stopCh
 // is closed, at which point it will shutdown the workqueue and wait for
@@ -92,14 +124,29 @@ func (c *Controller) Run(threadiness int, resyncDeletePeriod time.Duration, stop
 
 	// Wait for the caches to be synced before starting workers
 	klog.Info("Waiting for informer caches to sync")
-	if ok := cache.WaitForCacheSync(stopCh, c.informerSynced); !ok {
+	informersSynced := []cache.InformerSynced{c.informerSynced}
+	if c.organizationsSynced != nil {
+		informersSynced = append(informersSynced, c.organizationsSynced)
+	}
+	informersSynced = append(informersSynced, c.watchedSynced...)
+
+	if ok := cache.WaitForCacheSync(stopCh, informersSynced...); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
+	// cancel in flight grafana requests when stopCh is closed
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
 	klog.Info("Starting workers")
 	// Launch two workers to process GrafanaDashboard resources
 	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		go wait.Until(func() { c.runWorker(ctx) }, time.Second, stopCh)
 	}
 
 	// launch resync all thing
@@ -118,14 +165,14 @@ func (c *Controller) Run(threadiness int, resyncDeletePeriod time.Duration, stop
 // runWorker is a long-running function that will continually call the
 // processNextWorkItem function in order to read and process a message on the
 // workqueue.
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
 	}
 }
 
 // processNextWorkItem will read a single work item off the workqueue and
 // attempt to process it, by calling the syncHandler.
-func (c *Controller) processNextWorkItem() bool {
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	obj, shutdown := c.workqueue.Get()
 
 	if shutdown {
@@ -157,17 +204,34 @@ func (c *Controller) processNextWorkItem() bool {
 		if item.isResyncDeletedObjects() {
 			prometheus.ResyncDeletedTotal.WithLabelValues(c.syncer.getType()).Inc()
 
-			if err := c.resyncDeletedObjects(); err != nil {
+			if err := c.resyncDeletedObjects(ctx); err != nil {
 				prometheus.ErrorTotal.Inc()
 
+				if !grafana.IsRetryable(err) {
+					c.workqueue.Forget(obj)
+					return fmt.Errorf("error resyncing all %s, not retrying", err.Error())
+				}
+
 				c.workqueue.AddRateLimited(item)
 				return fmt.Errorf("error resyncing all %s, requeuing", err.Error())
 			}
 		} else {
 
-			if err := c.syncHandler(item); err != nil {
+			if err := c.syncHandler(ctx, item); err != nil {
 				prometheus.ErrorTotal.Inc()
 
+				// permanent failures, like grafana rejecting the object, will fail the same way
+				// every time.  report them on the object instead of requeuing forever
+				if !grafana.IsRetryable(err) {
+					c.workqueue.Forget(obj)
+
+					if item.originalObject != nil {
+						c.recorder.Event(item.originalObject, corev1.EventTypeWarning, ErrSyncFailed, err.Error())
+					}
+
+					return fmt.Errorf("error syncing '%s': %s, not retrying", item.key, err.Error())
+				}
+
 				c.workqueue.AddRateLimited(item)
 				return fmt.Errorf("error syncing '%s': %s, requeuing", item.key, err.Error())
 			}
@@ -187,7 +251,7 @@ func (c *Controller) processNextWorkItem() bool {
 	return true
 }
 
-func (c *Controller) syncHandler(item WorkQueueItem) error {
+func (c *Controller) syncHandler(ctx context.Context, item WorkQueueItem) error {
 	// Convert the namespace/name string into a distinct namespace and name
 	namespace, name, err := cache.SplitMetaNamespaceKey(item.key)
 	if err != nil {
@@ -195,13 +259,16 @@ func (c *Controller) syncHandler(item WorkQueueItem) error {
 		return nil
 	}
 
+	// deletes act on the organization the object was last synced to
+	deleteCtx := grafana.WithOrgID(ctx, item.orgID)
+
 	if item.itemType == Delete {
 		// object was deleted, so delete from grafana
-		err = c.syncer.deleteObjectById(item.id)
+		err = c.syncer.deleteObjectById(deleteCtx, item.id)
 
 		if err == nil {
 			prometheus.DeletedObjectTotal.WithLabelValues(c.syncer.getType()).Inc()
-			c.recorder.Event(item.originalObject, corev1.EventTypeNormal, SuccessDeleted, MessageResourceDeleted)
+			c.recordEvent(item.originalObject, SuccessDeleted, MessageResourceDeleted)
 		}
 
 		return err
@@ -216,61 +283,124 @@ func (c *Controller) syncHandler(item WorkQueueItem) error {
 			prometheus.ErrorTotal.Inc()
 
 			// object was deleted, so delete from grafana
-			err = c.syncer.deleteObjectById(item.id)
+			err = c.syncer.deleteObjectById(deleteCtx, item.id)
 
 			if err == nil {
 				prometheus.DeletedObjectTotal.WithLabelValues(c.syncer.getType()).Inc()
-				c.recorder.Event(item.originalObject, corev1.EventTypeNormal, SuccessDeleted, MessageResourceDeleted)
+				c.recordEvent(item.originalObject, SuccessDeleted, MessageResourceDeleted)
 			}
 		}
 
 		return err
 	}
 
-	err = c.syncer.updateObject(runtimeObject)
+	err = c.syncer.updateObject(ctx, runtimeObject)
 
 	if err != nil {
 		return err
 	}
 
 	prometheus.UpdatedObjectTotal.WithLabelValues(c.syncer.getType()).Inc()
-	c.recorder.Event(runtimeObject, corev1.EventTypeNormal, SuccessSynced, MessageResourceSynced)
+	c.recordEvent(runtimeObject, SuccessSynced, MessageResourceSynced)
 	return nil
 }
 
-func (c *Controller) resyncDeletedObjects() error {
+// recordEvent records a normal event on object unless the controller is quiet
+func (c *Controller) recordEvent(object runtime.Object, reason string, message string) {
+	if c.quiet {
+		return
+	}
+
+	c.recorder.Event(object, corev1.EventTypeNormal, reason, message)
+}
+
+// recordWarning records a warning event on object.  Warnings are recorded by quiet controllers too.
+func (c *Controller) recordWarning(object runtime.Object, reason string, message string) {
+	c.recorder.Event(object, corev1.EventTypeWarning, reason, message)
+}
+
+func (c *Controller) resyncDeletedObjects(ctx context.Context) error {
+
+	orgIDs, err := c.organizationIDs()
+
+	if err != nil {
+		return err
+	}
+
+	for _, orgID := range orgIDs {
+		if err := c.resyncDeletedObjectsInOrganization(grafana.WithOrgID(ctx, orgID), orgID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// organizationIDs returns the default organization, "", and every organization created by an
+// Organization object.
+func (c *Controller) organizationIDs() ([]string, error) {
+	orgIDs := []string{""}
+
+	if c.organizationsLister == nil {
+		return orgIDs, nil
+	}
+
+	organizations, err := c.organizationsLister.List(labels.Everything())
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, organization := range organizations {
+		if organization.Status.GrafanaID != grafana.NO_ID {
+			orgIDs = append(orgIDs, organization.Status.GrafanaID)
+		}
+	}
+
+	return orgIDs, nil
+}
+
+func (c *Controller) resyncDeletedObjectsInOrganization(ctx context.Context, orgID string) error {
 
 	// get all dashboards in grafana.  anything in grafana that's not in k8s gets nuked
-	kubernetesIDs, err := c.syncer.getAllKubernetesObjectIDs()
+	kubernetesIDs, err := c.syncer.getAllKubernetesObjectIDs(orgID)
 
 	if err != nil {
 		return err
 	}
 
-	grafanaIDs, err := c.syncer.getAllGrafanaObjectIDs()
+	grafanaIDs, err := c.syncer.getAllGrafanaObjectIDs(ctx)
 
 	if err != nil {
 		return err
 	}
 
+	// fetched the first time an object is not found
+	var numericIDs map[string]string
+
 	for _, grafanaID := range grafanaIDs {
-		var found = false
+		found, err := containsKubernetesID(kubernetesIDs, grafanaID)
+		if err != nil {
+			return err
+		}
 
-		for _, kubernetesID := range kubernetesIDs {
+		if numericIDSyncer, ok := c.syncer.(numericIDSyncer); ok && !found {
+			if numericIDs == nil {
+				numericIDs, err = numericIDSyncer.getGrafanaObjectNumericIDs(ctx)
 
-			if kubernetesID == grafana.NO_ID {
-				return errors.New("found kubernetes object with unitialized id, bailing")
+				if err != nil {
+					return err
+				}
 			}
 
-			if kubernetesID == grafanaID {
-				found = true
-				break
+			if numericID, ok := numericIDs[grafanaID]; ok && numericID != grafanaID {
+				found, _ = containsKubernetesID(kubernetesIDs, numericID)
 			}
 		}
 
 		if !found {
 			klog.Infof("Object found in grafana but not k8s.  Deleting")
-			err = c.syncer.deleteObjectById(grafanaID)
+			err = c.syncer.deleteObjectById(ctx, grafanaID)
 
 			// if one fails just go ahead and bail out.  controlling logic will requeue
 			if err != nil {
@@ -282,6 +412,23 @@ func (c *Controller) resyncDeletedObjects() error {
 	return nil
 }
 
+// containsKubernetesID returns true if id is one of kubernetesIDs.  An error is returned if a
+// kubernetes object has not been synced yet because its grafana object can not be told apart.
+func containsKubernetesID(kubernetesIDs []string, id string) (bool, error) {
+	for _, kubernetesID := range kubernetesIDs {
+
+		if kubernetesID == grafana.NO_ID {
+			return false, errors.New("found kubernetes object with unitialized id, bailing")
+		}
+
+		if kubernetesID == id {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func (c *Controller) enqueueResyncDeletedObjects() {
 	c.workqueue.AddRateLimited(NewResyncDeletedObjects())
 }
@@ -289,9 +436,9 @@ func (c *Controller) enqueueResyncDeletedObjects() {
 func (c *Controller) enqueueWorkQueueItem(obj interface{}, itemType WorkQueueItemType) {
 
 	item := c.syncer.createWorkQueueItem(obj)
-	item.itemType = itemType
 
 	if item != nil {
+		item.itemType = itemType
 		c.workqueue.AddRateLimited(*item)
 	}
 }
@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana/grafanatest"
+)
+
+func TestResyncDeletesObjectsMissingFromKubernetes(t *testing.T) {
+	kept := newDashboard("kept", `{"title": "kept"}`, "")
+
+	f := newFixture(t)
+
+	keptUid, err := f.grafanaClient.PostDashboard(context.Background(), kept.Spec.JSON, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orphanUid, err := f.grafanaClient.PostDashboard(context.Background(), `{"title": "orphan"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kept.Status.GrafanaID = keptUid
+
+	f = f.withObjects(kept)
+	c := f.newController(newDashboardController)
+
+	if err := c.resyncDeletedObjects(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if ids, _ := f.grafanaClient.GetAllDashboardIds(context.Background()); !reflect.DeepEqual(ids, []string{keptUid}) {
+		t.Errorf("expected only %s to remain after deleting %s, got %v", keptUid, orphanUid, ids)
+	}
+}
+
+func TestResyncKeepsObjectsHoldingNumericIdsAfterUpgrade(t *testing.T) {
+	server := grafanatest.NewServerWithVersion("9.0.0")
+	defer server.Close()
+
+	grafanaClient, err := grafana.NewClient(server.URL, grafana.ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	// numericId returns the numeric id of a grafana object
+	numericId := func(object *grafana.Object, err error) string {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var model struct {
+			Id int `json:"id"`
+		}
+
+		if err := json.Unmarshal([]byte(object.JSON), &model); err != nil {
+			t.Fatal(err)
+		}
+
+		return fmt.Sprint(model.Id)
+	}
+
+	dataSourceUid, err := grafanaClient.PostDataSource(ctx, `{"name": "prometheus", "type": "prometheus"}`, grafana.NO_ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := grafanaClient.PostDataSource(ctx, `{"name": "orphan", "type": "prometheus"}`, grafana.NO_ID); err != nil {
+		t.Fatal(err)
+	}
+
+	alertNotificationUid, err := grafanaClient.PostAlertNotification(ctx, `{"name": "email", "type": "email"}`, grafana.NO_ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// synced before the upgrade and not synced again yet
+	dataSource := newDataSource("prometheus", `{"name": "prometheus", "type": "prometheus"}`)
+	dataSource.Status.GrafanaID = numericId(grafanaClient.GetDataSource(ctx, dataSourceUid))
+
+	alertNotification := newAlertNotification("email", `{"name": "email", "type": "email"}`)
+	alertNotification.Status.GrafanaID = numericId(grafanaClient.GetAlertNotification(ctx, alertNotificationUid))
+
+	f := newFixture(t, dataSource, alertNotification)
+
+	for _, newController := range []func(*fixture) *Controller{
+		func(f *fixture) *Controller {
+			return NewDataSourceController(f.client, f.kubeclient, grafanaClient,
+				f.informers.Grafana().V1alpha1().DataSources(),
+				f.informers.Grafana().V1alpha1().Organizations())
+		},
+		func(f *fixture) *Controller {
+			return NewAlertNotificationController(f.client, f.kubeclient, grafanaClient,
+				f.informers.Grafana().V1alpha1().AlertNotifications(),
+				f.informers.Grafana().V1alpha1().Organizations())
+		},
+	} {
+		c := f.newController(newController)
+
+		if err := c.resyncDeletedObjects(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if names := server.DataSourceNames(); !reflect.DeepEqual(names, []string{"prometheus"}) {
+		t.Errorf("expected only the orphaned data source to be deleted, got %v", names)
+	}
+
+	if names := server.AlertNotificationNames(); !reflect.DeepEqual(names, []string{"email"}) {
+		t.Errorf("expected the alert notification to be kept, got %v", names)
+	}
+}
+
+func TestResyncBailsOnUninitializedObjects(t *testing.T) {
+	unsynced := newDashboard("unsynced", `{"title": "unsynced"}`, "")
+
+	f := newFixture(t, unsynced)
+
+	if _, err := f.grafanaClient.PostDashboard(context.Background(), unsynced.Spec.JSON, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	c := f.newController(newDashboardController)
+
+	if err := c.resyncDeletedObjects(context.Background()); err == nil {
+		t.Error("expected resync to fail while an object has no grafana id")
+	}
+
+	if calls := f.grafanaClient.CallsTo("DeleteDashboard"); len(calls) != 0 {
+		t.Errorf("expected nothing to be deleted, got %v", calls)
+	}
+}
+
+func TestFailedResyncIsRequeued(t *testing.T) {
+	f := newFixture(t)
+	f.grafanaClient.FailNext("GetAllFolderIds", errors.New("connection refused"))
+
+	c := f.newController(newFolderController)
+
+	item := NewResyncDeletedObjects()
+	f.process(c, item)
+
+	if c.workqueue.NumRequeues(item) != 1 {
+		t.Error("expected a failed resync to be requeued")
+	}
+
+	f.process(c, item)
+
+	if calls := f.grafanaClient.CallsTo("GetAllFolderIds"); len(calls) != 2 || calls[1].Err != nil {
+		t.Errorf("expected the resync to succeed on the second attempt, got %v", calls)
+	}
+}
@@ -1,7 +1,12 @@
 package controllers
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"strings"
+
 	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
 
 	"k8s.io/apimachinery/pkg/labels"
@@ -19,30 +24,82 @@ import (
 
 // DashboardSyncer is the controller implementation for Dashboard resources
 type DashboardSyncer struct {
-	grafanaDashboardsLister listers.DashboardLister
-	grafanaFoldersLister    listers.FolderLister
-	grafanaClient           grafana.Interface
-	grafanaclientset        clientset.Interface
+	grafanaDashboardsLister    listers.DashboardLister
+	grafanaFoldersLister       listers.FolderLister
+	grafanaOrganizationsLister listers.OrganizationLister
+	grafanaTeamsLister         listers.TeamLister
+	grafanaLibraryPanelsLister listers.LibraryPanelLister
+	grafanaClient              grafana.Interface
+	grafanaclientset           clientset.Interface
 }
 
-// NewDashboardController returns a new grafana dashboard controller
+// NewDashboardController returns a new grafana dashboard controller.  Dashboards are synced again
+// whenever a library panel they reference is created in grafana, renamed or deleted.
 func NewDashboardController(
 	grafanaclientset clientset.Interface,
 	kubeclientset kubernetes.Interface,
 	grafanaClient grafana.Interface,
 	grafanaDashboardInformer informers.DashboardInformer,
-	grafanaFolderInformer informers.FolderInformer) *Controller {
+	grafanaFolderInformer informers.FolderInformer,
+	grafanaOrganizationInformer informers.OrganizationInformer,
+	grafanaTeamInformer informers.TeamInformer,
+	grafanaLibraryPanelInformer informers.LibraryPanelInformer) *Controller {
 
 	syncer := &DashboardSyncer{
-		grafanaDashboardsLister: grafanaDashboardInformer.Lister(),
-		grafanaFoldersLister:    grafanaFolderInformer.Lister(),
-		grafanaClient:           grafanaClient,
-		grafanaclientset:        grafanaclientset,
+		grafanaDashboardsLister:    grafanaDashboardInformer.Lister(),
+		grafanaFoldersLister:       grafanaFolderInformer.Lister(),
+		grafanaOrganizationsLister: grafanaOrganizationInformer.Lister(),
+		grafanaTeamsLister:         grafanaTeamInformer.Lister(),
+		grafanaLibraryPanelsLister: grafanaLibraryPanelInformer.Lister(),
+		grafanaClient:              grafanaClient,
+		grafanaclientset:           grafanaclientset,
 	}
 
 	controller := NewController(grafanaDashboardInformer.Informer(),
 		kubeclientset,
 		syncer)
+	controller.watchOrganizations(grafanaOrganizationInformer)
+
+	enqueueDashboards := func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+
+		libraryPanel, ok := obj.(*v1alpha1.LibraryPanel)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("expected library panel but got %#v", obj))
+			return
+		}
+
+		dashboards, err := syncer.grafanaDashboardsLister.Dashboards(libraryPanel.Namespace).List(labels.Everything())
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+
+		for _, dashboard := range dashboards {
+			if referencesLibraryPanel(dashboard, libraryPanel.Name) {
+				controller.enqueueWorkQueueItem(dashboard, AddOrUpdate)
+			}
+		}
+	}
+
+	controller.watchInformer(grafanaLibraryPanelInformer.Informer(), cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueueDashboards,
+		UpdateFunc: func(old, new interface{}) {
+			oldLibraryPanel, oldOk := old.(*v1alpha1.LibraryPanel)
+			newLibraryPanel, newOk := new.(*v1alpha1.LibraryPanel)
+
+			if oldOk && newOk &&
+				oldLibraryPanel.Status == newLibraryPanel.Status &&
+				libraryPanelName(oldLibraryPanel) == libraryPanelName(newLibraryPanel) {
+				return
+			}
+
+			enqueueDashboards(new)
+		},
+		DeleteFunc: enqueueDashboards,
+	})
 
 	return controller
 }
@@ -55,11 +112,11 @@ func (s *DashboardSyncer) getRuntimeObjectByName(name string, namespace string)
 	return s.grafanaDashboardsLister.Dashboards(namespace).Get(name)
 }
 
-func (s *DashboardSyncer) deleteObjectById(id string) error {
-	return s.grafanaClient.DeleteDashboard(id)
+func (s *DashboardSyncer) deleteObjectById(ctx context.Context, id string) error {
+	return s.grafanaClient.DeleteDashboard(ctx, id)
 }
 
-func (s *DashboardSyncer) updateObject(object runtime.Object) error {
+func (s *DashboardSyncer) updateObject(ctx context.Context, object runtime.Object) error {
 	var err error
 	var id string
 
@@ -68,6 +125,22 @@ func (s *DashboardSyncer) updateObject(object runtime.Object) error {
 		return fmt.Errorf("expected dashboard in but got %#v", object)
 	}
 
+	ctx, orgID, grafanaID, err := targetOrganization(ctx,
+		s.grafanaOrganizationsLister,
+		grafanaDashboard.Namespace,
+		grafanaDashboard.Spec.OrganizationName,
+		grafanaDashboard.Status.GrafanaOrgID,
+		grafanaDashboard.Status.GrafanaID,
+		s.deleteObjectById)
+	if err != nil {
+		return err
+	}
+
+	dashboardJson, err := s.resolveLibraryPanels(grafanaDashboard, orgID)
+	if err != nil {
+		return err
+	}
+
 	if grafanaDashboard.Spec.FolderName != "" {
 		folder, err := s.grafanaFoldersLister.Folders(grafanaDashboard.Namespace).Get(grafanaDashboard.Spec.FolderName)
 
@@ -75,9 +148,13 @@ func (s *DashboardSyncer) updateObject(object runtime.Object) error {
 			return err
 		}
 
-		id, err = s.grafanaClient.PostDashboardWithFolder(grafanaDashboard.Spec.JSON, folder.Status.GrafanaIDForDashboards, grafanaDashboard.Status.GrafanaID)
+		if folder.Status.GrafanaOrgID != orgID {
+			return fmt.Errorf("folder %s is not in the same organization as dashboard %s", folder.Name, grafanaDashboard.Name)
+		}
+
+		id, err = s.grafanaClient.PostDashboardWithFolder(ctx, dashboardJson, folder.Status.GrafanaIDForDashboards, folder.Status.GrafanaID, grafanaID)
 	} else {
-		id, err = s.grafanaClient.PostDashboard(grafanaDashboard.Spec.JSON, grafanaDashboard.Status.GrafanaID)
+		id, err = s.grafanaClient.PostDashboard(ctx, dashboardJson, grafanaID)
 	}
 
 	if err != nil {
@@ -86,15 +163,140 @@ func (s *DashboardSyncer) updateObject(object runtime.Object) error {
 
 	grafanaDashboardCopy := grafanaDashboard.DeepCopy()
 	grafanaDashboardCopy.Status.GrafanaID = id
+	grafanaDashboardCopy.Status.GrafanaOrgID = orgID
 
-	_, err = s.grafanaclientset.GrafanaV1alpha1().Dashboards(grafanaDashboard.Namespace).UpdateStatus(grafanaDashboardCopy)
+	updatedDashboard, err := s.grafanaclientset.GrafanaV1alpha1().Dashboards(grafanaDashboard.Namespace).UpdateStatus(grafanaDashboardCopy)
 	if err != nil {
 		return err
 	}
+
+	granted, err := reconcilePermissions(ctx,
+		s.grafanaClient,
+		s.grafanaTeamsLister,
+		grafanaDashboard.Namespace,
+		orgID,
+		grafanaDashboard.Spec.Permissions,
+		grafanaDashboard.Spec.ReplacePermissions,
+		nil,
+		grantedInOrganization(grafanaDashboard.Status.GrantedPermissions, grafanaDashboard.Status.GrafanaOrgID, orgID),
+		func() ([]grafana.Permission, error) { return s.grafanaClient.GetDashboardPermissions(ctx, id) },
+		func(permissions []grafana.Permission) error {
+			return s.grafanaClient.SetDashboardPermissions(ctx, id, permissions)
+		})
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(granted, updatedDashboard.Status.GrantedPermissions) {
+		return nil
+	}
+
+	updatedDashboard = updatedDashboard.DeepCopy()
+	updatedDashboard.Status.GrantedPermissions = granted
+
+	_, err = s.grafanaclientset.GrafanaV1alpha1().Dashboards(grafanaDashboard.Namespace).UpdateStatus(updatedDashboard)
+	return err
+}
+
+// resolveLibraryPanels replaces the libraryPanelName of every panel, including panels nested in
+// rows, with a reference to the uid of that LibraryPanel object.  Library panels are looked up in
+// the dashboard's namespace and must be in the organization orgID.
+func (s *DashboardSyncer) resolveLibraryPanels(dashboard *v1alpha1.Dashboard, orgID string) (string, error) {
+	if !strings.Contains(dashboard.Spec.JSON, `"libraryPanelName"`) {
+		return dashboard.Spec.JSON, nil
+	}
+
+	var model map[string]interface{}
+
+	if err := json.Unmarshal([]byte(dashboard.Spec.JSON), &model); err != nil {
+		return "", err
+	}
+
+	if err := s.resolvePanels(model, dashboard, orgID); err != nil {
+		return "", err
+	}
+
+	dashboardJson, err := json.Marshal(model)
+	if err != nil {
+		return "", err
+	}
+
+	return string(dashboardJson), nil
+}
+
+func (s *DashboardSyncer) resolvePanels(parent map[string]interface{}, dashboard *v1alpha1.Dashboard, orgID string) error {
+	panels, _ := parent["panels"].([]interface{})
+
+	for _, p := range panels {
+		panel, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if name, ok := panel["libraryPanelName"].(string); ok {
+			libraryPanel, err := s.grafanaLibraryPanelsLister.LibraryPanels(dashboard.Namespace).Get(name)
+			if err != nil {
+				return err
+			}
+
+			if libraryPanel.Status.GrafanaID == grafana.NO_ID {
+				return fmt.Errorf("library panel %s has not been created in grafana yet", libraryPanel.Name)
+			}
+
+			if libraryPanel.Status.GrafanaOrgID != orgID {
+				return fmt.Errorf("library panel %s is not in the same organization as dashboard %s", libraryPanel.Name, dashboard.Name)
+			}
+
+			delete(panel, "libraryPanelName")
+			panel["libraryPanel"] = map[string]interface{}{
+				"uid":  libraryPanel.Status.GrafanaID,
+				"name": libraryPanelName(libraryPanel),
+			}
+		}
+
+		// collapsed rows hold their panels
+		if err := s.resolvePanels(panel, dashboard, orgID); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (s *DashboardSyncer) getAllKubernetesObjectIDs() ([]string, error) {
+// referencesLibraryPanel returns true if a panel of dashboard, including panels nested in rows,
+// sets libraryPanelName to name.  Dashboards with invalid json reference none.
+func referencesLibraryPanel(dashboard *v1alpha1.Dashboard, name string) bool {
+	if !strings.Contains(dashboard.Spec.JSON, `"libraryPanelName"`) {
+		return false
+	}
+
+	var model map[string]interface{}
+
+	if err := json.Unmarshal([]byte(dashboard.Spec.JSON), &model); err != nil {
+		return false
+	}
+
+	return panelsReference(model, name)
+}
+
+func panelsReference(parent map[string]interface{}, name string) bool {
+	panels, _ := parent["panels"].([]interface{})
+
+	for _, p := range panels {
+		panel, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if panelName, _ := panel["libraryPanelName"].(string); panelName == name || panelsReference(panel, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *DashboardSyncer) getAllKubernetesObjectIDs(orgID string) ([]string, error) {
 	dashboards, err := s.grafanaDashboardsLister.List(labels.Everything())
 
 	if err != nil {
@@ -104,14 +306,19 @@ func (s *DashboardSyncer) getAllKubernetesObjectIDs() ([]string, error) {
 	ids := make([]string, 0)
 
 	for _, dashboard := range dashboards {
+		// objects without an id may be in any organization
+		if dashboard.Status.GrafanaOrgID != orgID && dashboard.Status.GrafanaID != grafana.NO_ID {
+			continue
+		}
+
 		ids = append(ids, dashboard.Status.GrafanaID)
 	}
 
 	return ids, nil
 }
 
-func (s *DashboardSyncer) getAllGrafanaObjectIDs() ([]string, error) {
-	return s.grafanaClient.GetAllDashboardIds()
+func (s *DashboardSyncer) getAllGrafanaObjectIDs(ctx context.Context) ([]string, error) {
+	return s.grafanaClient.GetAllDashboardIds(ctx)
 }
 
 func (s *DashboardSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
@@ -130,7 +337,7 @@ func (s *DashboardSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
 		return nil
 	}
 
-	item := NewWorkQueueItem(key, dashboard.DeepCopyObject(), dashboard.Status.GrafanaID) // todo: confirm this doesnt need null checking
+	item := NewWorkQueueItem(key, dashboard.DeepCopyObject(), dashboard.Status.GrafanaID, dashboard.Status.GrafanaOrgID) // todo: confirm this doesnt need null checking
 
 	return &item
 }
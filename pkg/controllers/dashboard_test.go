@@ -1,65 +1,259 @@
 package controllers
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	k8sfake "k8s.io/client-go/kubernetes/fake"
-	"k8s.io/client-go/tools/record"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
 
-	grafanacontroller "github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
-	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned/fake"
-	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions"
-	grafana "github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana/fake"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
 )
 
-func newGrafanaDashboard(name string, dashboardJson string) *grafanacontroller.GrafanaDashboard {
-	return &grafanacontroller.GrafanaDashboard{
-		TypeMeta: metav1.TypeMeta{APIVersion: grafanacontroller.SchemeGroupVersion.String()},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: metav1.NamespaceDefault,
-		},
-		Spec: grafanacontroller.GrafanaDashboardSpec{
-			DashboardJSON: dashboardJson,
+func newDashboard(name string, dashboardJson string, folderName string) *v1alpha1.Dashboard {
+	return &v1alpha1.Dashboard{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: newObjectMeta(name),
+		Spec: v1alpha1.DashboardSpec{
+			FolderName: folderName,
+			JSON:       dashboardJson,
 		},
 	}
 }
 
-func newDashboardController(f *fixture) (*Controller, informers.SharedInformerFactory) {
-	f.client = fake.NewSimpleClientset(f.objects...)
-	f.kubeclient = k8sfake.NewSimpleClientset(f.kubeobjects...)
-	f.grafanaClient = grafana.NewGrafanaClientFake("https://example.com", FAKE_UID)
+func newDashboardController(f *fixture) *Controller {
+	return NewDashboardController(f.client, f.kubeclient, f.grafanaClient,
+		f.informers.Grafana().V1alpha1().Dashboards(),
+		f.informers.Grafana().V1alpha1().Folders(),
+		f.informers.Grafana().V1alpha1().Organizations(),
+		f.informers.Grafana().V1alpha1().Teams(),
+		f.informers.Grafana().V1alpha1().LibraryPanels())
+}
+
+func (f *fixture) getDashboard(name string) *v1alpha1.Dashboard {
+	dashboard, err := f.client.GrafanaV1alpha1().Dashboards(metav1.NamespaceDefault).Get(name, metav1.GetOptions{})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return dashboard
+}
+
+func TestCreatesDashboard(t *testing.T) {
+	dashboard := newDashboard("test", `{"title": "test"}`, "")
+
+	f := newFixture(t, dashboard)
+	c := f.newController(newDashboardController)
+
+	if err := f.sync(c, newItem(dashboard, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	uid := f.getDashboard("test").Status.GrafanaID
+	if uid == "" {
+		t.Fatal("expected the dashboard status to be updated with its grafana uid")
+	}
+
+	if _, err := f.grafanaClient.GetDashboard(context.Background(), uid); err != nil {
+		t.Errorf("expected dashboard in grafana: %v", err)
+	}
+
+	f.expectEvent("Normal " + SuccessSynced)
+}
+
+func TestUpdatesDashboardInPlace(t *testing.T) {
+	dashboard := newDashboard("test", `{"title": "test"}`, "")
+	dashboard.Status.GrafanaID = "existing"
+
+	f := newFixture(t, dashboard)
+	c := f.newController(newDashboardController)
+
+	for i := 0; i < 2; i++ {
+		if err := f.sync(c, newItem(dashboard, AddOrUpdate, "existing", t)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	object, err := f.grafanaClient.GetDashboard(context.Background(), "existing")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if object.Version != 2 {
+		t.Errorf("expected the dashboard to be overwritten, got version %d", object.Version)
+	}
+}
+
+func TestCreatesDashboardInFolder(t *testing.T) {
+	folder := newFolder("folder", `{"title": "folder"}`)
+	dashboard := newDashboard("test", `{"title": "test"}`, "folder")
+
+	f := newFixture(t)
+
+	folderUid, folderId, err := f.grafanaClient.PostFolder(context.Background(), folder.Spec.JSON, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	folder.Status.GrafanaID = folderUid
+	folder.Status.GrafanaIDForDashboards = folderId
+
+	f = f.withObjects(folder, dashboard)
+	c := f.newController(newDashboardController)
+
+	if err := f.sync(c, newItem(dashboard, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	inFolder, ok := f.grafanaClient.DashboardFolderUid(context.Background(), f.getDashboard("test").Status.GrafanaID)
+	if !ok || inFolder != folderUid {
+		t.Errorf("expected dashboard in folder %s but got %q", folderUid, inFolder)
+	}
+}
+
+func TestDashboardInMissingFolderIsRequeued(t *testing.T) {
+	dashboard := newDashboard("test", `{"title": "test"}`, "missing")
+
+	f := newFixture(t, dashboard)
+	c := f.newController(newDashboardController)
+
+	item := newItem(dashboard, AddOrUpdate, "", t)
+	f.process(c, item)
+
+	if c.workqueue.NumRequeues(item) != 1 {
+		t.Error("expected a dashboard whose folder does not exist yet to be requeued")
+	}
+}
+
+func TestResolvesLibraryPanelReferences(t *testing.T) {
+	libraryPanel := newLibraryPanel("cpu", `{"type": "graph"}`, "")
+	libraryPanel.Spec.Name = "CPU usage"
+	libraryPanel.Status.GrafanaID = "panel-uid"
 
-	i := informers.NewSharedInformerFactory(f.client, noResyncPeriodFunc())
+	dashboard := newDashboard("test", `{
+		"title": "test",
+		"panels": [
+			{"id": 1, "libraryPanelName": "cpu"},
+			{"id": 2, "type": "row", "collapsed": true, "panels": [{"id": 3, "libraryPanelName": "cpu"}]}
+		]
+	}`, "")
 
-	c := NewDashboardController(f.client, f.kubeclient,
-		f.grafanaClient, i.Grafana().V1alpha1().GrafanaDashboards())
+	f := newFixture(t, libraryPanel, dashboard)
+	c := f.newController(newDashboardController)
 
-	c.informerSynced = alwaysReady
-	c.syncer.(*DashboardSyncer).recorder = &record.FakeRecorder{}
+	if err := f.sync(c, newItem(dashboard, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := f.grafanaClient.CallsTo("PostDashboard")
+	if len(calls) != 1 {
+		t.Fatalf("expected one post, got %v", calls)
+	}
 
-	for _, d := range f.grafanaDashboardLister {
-		i.Grafana().V1alpha1().GrafanaDashboards().Informer().GetIndexer().Add(d)
+	type panel struct {
+		LibraryPanelName string            `json:"libraryPanelName"`
+		LibraryPanel     map[string]string `json:"libraryPanel"`
+		Panels           []panel           `json:"panels"`
 	}
 
-	return c, i
+	var posted struct {
+		Panels []panel `json:"panels"`
+	}
+
+	if err := json.Unmarshal([]byte(calls[0].Args[0]), &posted); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(posted.Panels) != 2 || len(posted.Panels[1].Panels) != 1 {
+		t.Fatalf("expected the panel layout to be kept, got %+v", posted.Panels)
+	}
+
+	expected := map[string]string{"uid": "panel-uid", "name": "CPU usage"}
+
+	for _, p := range []panel{posted.Panels[0], posted.Panels[1].Panels[0]} {
+		if p.LibraryPanelName != "" || p.LibraryPanel["uid"] != expected["uid"] || p.LibraryPanel["name"] != expected["name"] {
+			t.Errorf("expected the reference to be replaced with %v, got %+v", expected, p)
+		}
+	}
+}
+
+func TestDashboardWaitsForLibraryPanel(t *testing.T) {
+	dashboard := newDashboard("test", `{"title": "test", "panels": [{"libraryPanelName": "cpu"}]}`, "")
+
+	f := newFixture(t, newLibraryPanel("cpu", `{"type": "graph"}`, ""), dashboard)
+	c := f.newController(newDashboardController)
+
+	if err := f.sync(c, newItem(dashboard, AddOrUpdate, "", t)); err == nil {
+		t.Error("expected an error for a library panel that has not been created in grafana yet")
+	}
+
+	if calls := f.grafanaClient.CallsTo("PostDashboard"); len(calls) != 0 {
+		t.Errorf("expected nothing to be posted, got %v", calls)
+	}
 }
 
-func TestCreatesGrafanaDashboard(t *testing.T) {
+func TestLibraryPanelChangeSyncsDashboardsReferencingIt(t *testing.T) {
+	nested := newDashboard("nested", `{"title": "nested", "panels": [{"type": "row", "panels": [{"libraryPanelName": "cpu"}]}]}`, "")
+	plain := newDashboard("plain", `{"title": "plain", "panels": [{"type": "graph"}]}`, "")
+
+	f := newFixture(t, nested, plain)
+	c := f.newController(newDashboardController)
+
+	libraryPanelInformer := f.informers.Grafana().V1alpha1().LibraryPanels().Informer()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	go libraryPanelInformer.Run(stopCh)
+
+	if !cache.WaitForCacheSync(stopCh, libraryPanelInformer.HasSynced) {
+		t.Fatal("library panel informer did not sync")
+	}
+
+	// library panels no dashboard references are ignored
+	if _, err := f.client.GrafanaV1alpha1().LibraryPanels(metav1.NamespaceDefault).Create(newLibraryPanel("memory", `{"type": "graph"}`, "")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.client.GrafanaV1alpha1().LibraryPanels(metav1.NamespaceDefault).Create(newLibraryPanel("cpu", `{"type": "graph"}`, "")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return c.workqueue.Len() > 0, nil
+	})
+	if err != nil {
+		t.Fatal("expected the dashboard to be enqueued")
+	}
+
+	item, _ := c.workqueue.Get()
+	if key := item.(WorkQueueItem).key; key != "default/nested" || c.workqueue.Len() != 0 {
+		t.Errorf("expected only default/nested to be enqueued, got %v and %d more", key, c.workqueue.Len())
+	}
+}
+
+func TestDeletesDashboard(t *testing.T) {
+	dashboard := newDashboard("test", `{"title": "test"}`, "")
 
 	f := newFixture(t)
-	dashboardJson := "{ 'test': 'test' }"
 
-	dashboard := newGrafanaDashboard("test", dashboardJson)
-	item := NewWorkQueueItem(getKey(dashboard, t), nil, "")
+	uid, err := f.grafanaClient.PostDashboard(context.Background(), dashboard.Spec.JSON, "")
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	f.grafanaDashboardLister = append(f.grafanaDashboardLister, dashboard)
-	f.objects = append(f.objects, dashboard)
+	c := f.newController(newDashboardController)
 
-	dashboard.Status.GrafanaUID = FAKE_UID
-	f.expectUpdateGrafanaObject(dashboard, dashboard.Namespace, "grafanadashboards")
-	f.expectGrafanaPost(dashboardJson)
+	if err := f.sync(c, newItem(dashboard, Delete, uid, t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if ids, _ := f.grafanaClient.GetAllDashboardIds(context.Background()); len(ids) != 0 {
+		t.Errorf("expected dashboard to be deleted from grafana but found %v", ids)
+	}
 
-	f.runController(newDashboardController, item, true, false)
+	f.expectEvent("Normal " + SuccessDeleted)
 }
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/labels"
@@ -19,9 +20,10 @@ import (
 
 // DataSourceSyncer is the controller implementation for DataSource resources
 type DataSourceSyncer struct {
-	grafanaDataSourcesLister listers.DataSourceLister
-	grafanaClient            grafana.Interface
-	grafanaclientset         clientset.Interface
+	grafanaDataSourcesLister   listers.DataSourceLister
+	grafanaOrganizationsLister listers.OrganizationLister
+	grafanaClient              grafana.Interface
+	grafanaclientset           clientset.Interface
 }
 
 // NewDataSourceController returns a new grafana DataSource controller
@@ -29,17 +31,20 @@ func NewDataSourceController(
 	grafanaclientset clientset.Interface,
 	kubeclientset kubernetes.Interface,
 	grafanaClient grafana.Interface,
-	grafanaDataSourceInformer informers.DataSourceInformer) *Controller {
+	grafanaDataSourceInformer informers.DataSourceInformer,
+	grafanaOrganizationInformer informers.OrganizationInformer) *Controller {
 
 	syncer := &DataSourceSyncer{
-		grafanaDataSourcesLister: grafanaDataSourceInformer.Lister(),
-		grafanaClient:            grafanaClient,
-		grafanaclientset:         grafanaclientset,
+		grafanaDataSourcesLister:   grafanaDataSourceInformer.Lister(),
+		grafanaOrganizationsLister: grafanaOrganizationInformer.Lister(),
+		grafanaClient:              grafanaClient,
+		grafanaclientset:           grafanaclientset,
 	}
 
 	controller := NewController(grafanaDataSourceInformer.Informer(),
 		kubeclientset,
 		syncer)
+	controller.watchOrganizations(grafanaOrganizationInformer)
 
 	return controller
 }
@@ -48,7 +53,7 @@ func (s *DataSourceSyncer) getType() string {
 	return prometheus.TypeDataSource
 }
 
-func (s *DataSourceSyncer) getAllKubernetesObjectIDs() ([]string, error) {
+func (s *DataSourceSyncer) getAllKubernetesObjectIDs(orgID string) ([]string, error) {
 	dataSources, err := s.grafanaDataSourcesLister.List(labels.Everything())
 
 	if err != nil {
@@ -58,32 +63,52 @@ func (s *DataSourceSyncer) getAllKubernetesObjectIDs() ([]string, error) {
 	ids := make([]string, 0)
 
 	for _, dataSource := range dataSources {
+		// objects without an id may be in any organization
+		if dataSource.Status.GrafanaOrgID != orgID && dataSource.Status.GrafanaID != grafana.NO_ID {
+			continue
+		}
+
 		ids = append(ids, dataSource.Status.GrafanaID)
 	}
 
 	return ids, nil
 }
 
-func (s *DataSourceSyncer) getAllGrafanaObjectIDs() ([]string, error) {
-	return s.grafanaClient.GetAllDataSourceIds()
+func (s *DataSourceSyncer) getAllGrafanaObjectIDs(ctx context.Context) ([]string, error) {
+	return s.grafanaClient.GetAllDataSourceIds(ctx)
+}
+
+func (s *DataSourceSyncer) getGrafanaObjectNumericIDs(ctx context.Context) (map[string]string, error) {
+	return s.grafanaClient.GetDataSourceNumericIds(ctx)
 }
 
 func (s *DataSourceSyncer) getRuntimeObjectByName(name string, namespace string) (runtime.Object, error) {
 	return s.grafanaDataSourcesLister.DataSources(namespace).Get(name)
 }
 
-func (s *DataSourceSyncer) deleteObjectById(id string) error {
-	return s.grafanaClient.DeleteDataSource(id)
+func (s *DataSourceSyncer) deleteObjectById(ctx context.Context, id string) error {
+	return s.grafanaClient.DeleteDataSource(ctx, id)
 }
 
-func (s *DataSourceSyncer) updateObject(object runtime.Object) error {
+func (s *DataSourceSyncer) updateObject(ctx context.Context, object runtime.Object) error {
 
 	grafanaDataSource, ok := object.(*v1alpha1.DataSource)
 	if !ok {
 		return fmt.Errorf("expected dataSource in but got %#v", object)
 	}
 
-	id, err := s.grafanaClient.PostDataSource(grafanaDataSource.Spec.JSON, grafanaDataSource.Status.GrafanaID)
+	ctx, orgID, grafanaID, err := targetOrganization(ctx,
+		s.grafanaOrganizationsLister,
+		grafanaDataSource.Namespace,
+		grafanaDataSource.Spec.OrganizationName,
+		grafanaDataSource.Status.GrafanaOrgID,
+		grafanaDataSource.Status.GrafanaID,
+		s.deleteObjectById)
+	if err != nil {
+		return err
+	}
+
+	id, err := s.grafanaClient.PostDataSource(ctx, grafanaDataSource.Spec.JSON, grafanaID)
 
 	// If an error occurs during Update, we'll requeue the item so we can
 	// attempt processing again later. THis could have been caused by a
@@ -96,6 +121,7 @@ func (s *DataSourceSyncer) updateObject(object runtime.Object) error {
 	// current state of the world
 	grafanaDataSourceCopy := grafanaDataSource.DeepCopy()
 	grafanaDataSourceCopy.Status.GrafanaID = id
+	grafanaDataSourceCopy.Status.GrafanaOrgID = orgID
 	_, err = s.grafanaclientset.GrafanaV1alpha1().DataSources(grafanaDataSource.Namespace).UpdateStatus(grafanaDataSourceCopy)
 
 	if err != nil {
@@ -120,7 +146,7 @@ func (s *DataSourceSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
 		return nil
 	}
 
-	item := NewWorkQueueItem(key, dataSource.DeepCopyObject(), dataSource.Status.GrafanaID) // todo: confirm this doesnt need null checking
+	item := NewWorkQueueItem(key, dataSource.DeepCopyObject(), dataSource.Status.GrafanaID, dataSource.Status.GrafanaOrgID) // todo: confirm this doesnt need null checking
 
 	return &item
 }
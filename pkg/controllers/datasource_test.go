@@ -1,65 +1,97 @@
 package controllers
 
 import (
-	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned/fake"
-	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions"
-	grafana "github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana/fake"
+	"context"
 	"testing"
 
-	grafanacontroller "github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
-
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	k8sfake "k8s.io/client-go/kubernetes/fake"
-	"k8s.io/client-go/tools/record"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
 )
 
-func newGrafanaDataSource(name string, dataSourceJson string) *grafanacontroller.GrafanaDataSource {
-	return &grafanacontroller.GrafanaDataSource{
-		TypeMeta: metav1.TypeMeta{APIVersion: grafanacontroller.SchemeGroupVersion.String()},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: metav1.NamespaceDefault,
-		},
-		Spec: grafanacontroller.GrafanaDataSourceSpec{
-			DataSourceJSON: dataSourceJson,
+func newDataSource(name string, dataSourceJson string) *v1alpha1.DataSource {
+	return &v1alpha1.DataSource{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: newObjectMeta(name),
+		Spec: v1alpha1.DataSourceSpec{
+			JSON: dataSourceJson,
 		},
 	}
 }
 
-func newDataSourceController(f *fixture) (*Controller, informers.SharedInformerFactory) {
-	f.client = fake.NewSimpleClientset(f.objects...)
-	f.kubeclient = k8sfake.NewSimpleClientset(f.kubeobjects...)
-	f.grafanaClient = grafana.NewGrafanaClientFake("https://example.com", FAKE_UID)
+func newDataSourceController(f *fixture) *Controller {
+	return NewDataSourceController(f.client, f.kubeclient, f.grafanaClient,
+		f.informers.Grafana().V1alpha1().DataSources(),
+		f.informers.Grafana().V1alpha1().Organizations())
+}
+
+func (f *fixture) getDataSource(name string) *v1alpha1.DataSource {
+	dataSource, err := f.client.GrafanaV1alpha1().DataSources(metav1.NamespaceDefault).Get(name, metav1.GetOptions{})
+	if err != nil {
+		f.t.Fatal(err)
+	}
 
-	i := informers.NewSharedInformerFactory(f.client, noResyncPeriodFunc())
+	return dataSource
+}
+
+func TestCreatesAndUpdatesDataSource(t *testing.T) {
+	dataSource := newDataSource("test", `{"name": "test", "type": "prometheus"}`)
+
+	f := newFixture(t, dataSource)
+	c := f.newController(newDataSourceController)
+
+	if err := f.sync(c, newItem(dataSource, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
 
-	c := NewDataSourceController(f.client, f.kubeclient,
-		f.grafanaClient, i.Grafana().V1alpha1().GrafanaDataSources())
+	id := f.getDataSource("test").Status.GrafanaID
 
-	c.informerSynced = alwaysReady
-	c.syncer.(*DataSourceSyncer).recorder = &record.FakeRecorder{}
+	updated := f.getDataSource("test")
+	f.index(updated)
 
-	for _, d := range f.grafanaDataSourceLister {
-		i.Grafana().V1alpha1().GrafanaDataSources().Informer().GetIndexer().Add(d)
+	if err := f.sync(c, newItem(updated, AddOrUpdate, id, t)); err != nil {
+		t.Fatal(err)
 	}
 
-	return c, i
+	if ids, _ := f.grafanaClient.GetAllDataSourceIds(context.Background()); len(ids) != 1 || ids[0] != id {
+		t.Errorf("expected the data source to be updated in place, got %v", ids)
+	}
 }
 
-func TestCreatesGrafanaDataSource(t *testing.T) {
+func TestRejectedDataSourceIsNotRetried(t *testing.T) {
+	dataSource := newDataSource("test", `{"name": "test", "type": "prometheus"}`)
+
+	f := newFixture(t, dataSource)
+
+	// a data source with the same name already exists in grafana
+	if _, err := f.grafanaClient.PostDataSource(context.Background(), dataSource.Spec.JSON, ""); err != nil {
+		t.Fatal(err)
+	}
 
-	f := newFixture(t)
-	dataSourceJson := "{ 'test': 'test' }"
+	c := f.newController(newDataSourceController)
 
-	dataSource := newGrafanaDataSource("test", dataSourceJson)
-	item := NewWorkQueueItem(getKey(dataSource, t), nil, "")
+	item := newItem(dataSource, AddOrUpdate, "", t)
+	f.process(c, item)
 
-	f.grafanaDataSourceLister = append(f.grafanaDataSourceLister, dataSource)
-	f.objects = append(f.objects, dataSource)
+	if c.workqueue.NumRequeues(item) != 0 {
+		t.Error("expected a data source grafana rejected to not be requeued")
+	}
 
-	dataSource.Status.GrafanaID = FAKE_UID
-	f.expectUpdateGrafanaObject(dataSource, dataSource.Namespace, "grafanadatasources")
-	f.expectGrafanaPost(dataSourceJson)
+	f.expectEvent("Warning " + ErrSyncFailed)
+}
+
+func TestInvalidDataSourceJsonIsNotRetried(t *testing.T) {
+	dataSource := newDataSource("test", `{"name": `)
+
+	f := newFixture(t, dataSource)
+	c := f.newController(newDataSourceController)
+
+	item := newItem(dataSource, AddOrUpdate, "", t)
+	f.process(c, item)
+
+	if c.workqueue.NumRequeues(item) != 0 {
+		t.Error("expected a data source with invalid json to not be requeued")
+	}
 
-	f.runController(newDataSourceController, item, true, false)
+	f.expectEvent("Warning " + ErrSyncFailed)
 }
@@ -0,0 +1,442 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+// DefaultEventAnnotationReasons are the reasons of the Warning events annotated by default
+const DefaultEventAnnotationReasons = "OOMKilled,BackOff,FailedScheduling"
+
+// EventAnnotationFilter selects the Warning events that are annotated
+type EventAnnotationFilter struct {
+	// Namespaces of the events.  Empty selects every namespace.
+	Namespaces []string
+	// Reasons of the events.  Empty selects every reason.
+	Reasons []string
+	// Selector matches the labels of the objects the events are about.  Objects that cannot be
+	// looked up only match an empty selector.
+	Selector labels.Selector
+}
+
+// NewEventAnnotationFilter parses comma separated namespaces and reasons and a label selector
+func NewEventAnnotationFilter(namespaces string, reasons string, selector string) (*EventAnnotationFilter, error) {
+	parsedSelector, err := labels.Parse(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventAnnotationFilter{
+		Namespaces: splitList(namespaces),
+		Reasons:    splitList(reasons),
+		Selector:   parsedSelector,
+	}, nil
+}
+
+func splitList(list string) []string {
+	var values []string
+
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// matches never selects events about Events or events recorded by this controller's own
+// controllers.  Annotating the sync failures they record would loop.
+func (f *EventAnnotationFilter) matches(event *corev1.Event) bool {
+	if event.Type != corev1.EventTypeWarning {
+		return false
+	}
+
+	if event.InvolvedObject.Kind == "Event" || isControllerAgent(event.Source.Component) {
+		return false
+	}
+
+	return (len(f.Namespaces) == 0 || containsString(f.Namespaces, event.Namespace)) &&
+		(len(f.Reasons) == 0 || containsString(f.Reasons, event.Reason))
+}
+
+// isControllerAgent returns true if component is the name NewController records events as
+func isControllerAgent(component string) bool {
+	return strings.HasPrefix(component, "grafana-") && strings.HasSuffix(component, "-controller")
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// involvedObjectListers list the objects events can be about in one namespace, or in every
+// namespace.  nodes is only set for the first namespace.  Nodes are not namespaced.
+type involvedObjectListers struct {
+	pods         corelisters.PodLister
+	nodes        corelisters.NodeLister
+	deployments  appslisters.DeploymentLister
+	replicaSets  appslisters.ReplicaSetLister
+	statefulSets appslisters.StatefulSetLister
+	daemonSets   appslisters.DaemonSetLister
+}
+
+// EventAnnotationSyncer adds an annotation in the default organization for every Warning event
+// that passes its filter.  kubernetes counts repeats of an event on the same Event object so an
+// event is annotated again only when its count grows.
+type EventAnnotationSyncer struct {
+	eventListers  []corelisters.EventLister
+	objectListers []involvedObjectListers
+	grafanaClient grafana.Interface
+	filter        *EventAnnotationFilter
+
+	// since skips the events that happened before the controller started.  They were annotated by
+	// the controller's previous run.
+	since time.Time
+
+	// annotatedCounts are the counts last annotated by event uid
+	annotatedCounts map[string]int32
+	lock            sync.Mutex
+}
+
+// NewEventAnnotationController returns a controller annotating the Warning events filter selects.
+// eventInformers watch the events of one namespace each, or of every namespace.  If the filter
+// has a selector, the objects events are about are looked up in objectInformerFactories, one per
+// namespace of eventInformers.
+func NewEventAnnotationController(
+	kubeclientset kubernetes.Interface,
+	grafanaClient grafana.Interface,
+	eventInformers []coreinformers.EventInformer,
+	objectInformerFactories []kubeinformers.SharedInformerFactory,
+	filter *EventAnnotationFilter) *Controller {
+
+	syncer := &EventAnnotationSyncer{
+		grafanaClient:   grafanaClient,
+		filter:          filter,
+		since:           time.Now(),
+		annotatedCounts: make(map[string]int32),
+	}
+
+	for _, eventInformer := range eventInformers {
+		syncer.eventListers = append(syncer.eventListers, eventInformer.Lister())
+	}
+
+	controller := NewController(eventInformers[0].Informer(), kubeclientset, syncer)
+	controller.quiet = true
+
+	// the events of other namespaces are queued like those of the first
+	for _, eventInformer := range eventInformers[1:] {
+		controller.watchInformer(eventInformer.Informer(), cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				controller.enqueueWorkQueueItem(obj, AddOrUpdate)
+			},
+			UpdateFunc: func(old, new interface{}) {
+				controller.enqueueWorkQueueItem(new, AddOrUpdate)
+			},
+			DeleteFunc: func(obj interface{}) {
+				controller.enqueueWorkQueueItem(obj, Delete)
+			},
+		})
+	}
+
+	if filter.Selector == nil || filter.Selector.Empty() {
+		return controller
+	}
+
+	// the lookups of the objects events are about only wait for their informers to sync
+	noop := cache.ResourceEventHandlerFuncs{}
+
+	for i, factory := range objectInformerFactories {
+		listers := involvedObjectListers{
+			pods:         factory.Core().V1().Pods().Lister(),
+			deployments:  factory.Apps().V1().Deployments().Lister(),
+			replicaSets:  factory.Apps().V1().ReplicaSets().Lister(),
+			statefulSets: factory.Apps().V1().StatefulSets().Lister(),
+			daemonSets:   factory.Apps().V1().DaemonSets().Lister(),
+		}
+
+		controller.watchInformer(factory.Core().V1().Pods().Informer(), noop)
+		controller.watchInformer(factory.Apps().V1().Deployments().Informer(), noop)
+		controller.watchInformer(factory.Apps().V1().ReplicaSets().Informer(), noop)
+		controller.watchInformer(factory.Apps().V1().StatefulSets().Informer(), noop)
+		controller.watchInformer(factory.Apps().V1().DaemonSets().Informer(), noop)
+
+		if i == 0 {
+			listers.nodes = factory.Core().V1().Nodes().Lister()
+			controller.watchInformer(factory.Core().V1().Nodes().Informer(), noop)
+		}
+
+		syncer.objectListers = append(syncer.objectListers, listers)
+	}
+
+	return controller
+}
+
+func (s *EventAnnotationSyncer) getType() string {
+	return prometheus.TypeEventAnnotation
+}
+
+// getRuntimeObjectByName looks the event up in the lister of each namespace
+func (s *EventAnnotationSyncer) getRuntimeObjectByName(name string, namespace string) (runtime.Object, error) {
+	var err error
+
+	for _, lister := range s.eventListers {
+		var event *corev1.Event
+
+		event, err = lister.Events(namespace).Get(name)
+		if !k8serrors.IsNotFound(err) {
+			return event, err
+		}
+	}
+
+	return nil, err
+}
+
+// deleteObjectById forgets the count of the event with uid id.  Its annotations are kept.
+func (s *EventAnnotationSyncer) deleteObjectById(ctx context.Context, id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.annotatedCounts, id)
+
+	return nil
+}
+
+func (s *EventAnnotationSyncer) updateObject(ctx context.Context, object runtime.Object) error {
+	event, ok := object.(*corev1.Event)
+	if !ok {
+		return fmt.Errorf("expected event but got %#v", object)
+	}
+
+	if !s.filter.matches(event) || eventTime(event).Before(s.since) {
+		return nil
+	}
+
+	count := eventCount(event)
+
+	s.lock.Lock()
+	annotated := s.annotatedCounts[string(event.UID)]
+	s.lock.Unlock()
+
+	if count <= annotated {
+		return nil
+	}
+
+	matches, err := s.involvedObjectMatches(event)
+	if err != nil {
+		return err
+	}
+
+	if !matches {
+		return nil
+	}
+
+	// the count is taken before posting so a copy of the event processed at the same time is not
+	// annotated twice
+	s.lock.Lock()
+	if s.annotatedCounts[string(event.UID)] >= count {
+		s.lock.Unlock()
+		return nil
+	}
+	s.annotatedCounts[string(event.UID)] = count
+	s.lock.Unlock()
+
+	annotationJson, err := eventAnnotationJson(event, count)
+	if err == nil {
+		_, err = s.grafanaClient.AddAnnotation(ctx, annotationJson)
+	}
+
+	if err != nil {
+		s.lock.Lock()
+		if s.annotatedCounts[string(event.UID)] == count {
+			s.annotatedCounts[string(event.UID)] = annotated
+		}
+		s.lock.Unlock()
+
+		return err
+	}
+
+	klog.Infof("Annotated event %s %s of %s %s/%s", event.Reason, event.UID, event.InvolvedObject.Kind, event.InvolvedObject.Namespace, event.InvolvedObject.Name)
+
+	return nil
+}
+
+// involvedObjectMatches matches the labels of the object an event is about against the filter's
+// selector.  Objects that have been deleted, or are in a namespace that is not watched, do not
+// match.
+func (s *EventAnnotationSyncer) involvedObjectMatches(event *corev1.Event) (bool, error) {
+	if s.filter.Selector == nil || s.filter.Selector.Empty() {
+		return true, nil
+	}
+
+	for _, listers := range s.objectListers {
+		object, err := listers.get(event.InvolvedObject)
+		if k8serrors.IsNotFound(err) || (err == nil && object == nil) {
+			continue
+		}
+
+		if err != nil {
+			return false, err
+		}
+
+		return s.filter.Selector.Matches(labels.Set(object.GetLabels())), nil
+	}
+
+	return false, nil
+}
+
+// get returns the object ref points to.  nil is returned for kinds that are not supported.
+func (l *involvedObjectListers) get(ref corev1.ObjectReference) (metav1.Object, error) {
+	switch ref.Kind {
+	case "Pod":
+		return l.pods.Pods(ref.Namespace).Get(ref.Name)
+	case "Node":
+		if l.nodes == nil {
+			return nil, nil
+		}
+
+		return l.nodes.Get(ref.Name)
+	case "Deployment":
+		return l.deployments.Deployments(ref.Namespace).Get(ref.Name)
+	case "ReplicaSet":
+		return l.replicaSets.ReplicaSets(ref.Namespace).Get(ref.Name)
+	case "StatefulSet":
+		return l.statefulSets.StatefulSets(ref.Namespace).Get(ref.Name)
+	case "DaemonSet":
+		return l.daemonSets.DaemonSets(ref.Namespace).Get(ref.Name)
+	}
+
+	return nil, nil
+}
+
+// eventCount is the number of times an event has happened.  Event series count their repeats
+// separately.
+func eventCount(event *corev1.Event) int32 {
+	count := event.Count
+
+	if event.Series != nil && event.Series.Count > count {
+		count = event.Series.Count
+	}
+
+	if count < 1 {
+		count = 1
+	}
+
+	return count
+}
+
+// eventTime is when an event last happened
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+
+	return event.CreationTimestamp.Time
+}
+
+// eventAnnotationJson returns an organization wide annotation for an event.  Tags identify the
+// reason and the object the event is about so dashboards can select the events they show.
+func eventAnnotationJson(event *corev1.Event, count int32) (string, error) {
+	ref := event.InvolvedObject
+
+	tags := []string{
+		"event",
+		"reason:" + event.Reason,
+		"kind:" + ref.Kind,
+		"namespace:" + ref.Namespace,
+		"name:" + ref.Name,
+	}
+
+	if event.Source.Host != "" {
+		tags = append(tags, "node:"+event.Source.Host)
+	}
+
+	text := fmt.Sprintf("%s %s/%s %s: %s", ref.Kind, ref.Namespace, ref.Name, event.Reason, event.Message)
+	if count > 1 {
+		text = fmt.Sprintf("%s (x%d)", text, count)
+	}
+
+	annotationJson, err := json.Marshal(map[string]interface{}{
+		"text": text,
+		"tags": tags,
+		"time": epochMillis(eventTime(event)),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(annotationJson), nil
+}
+
+// getAllKubernetesObjectIDs returns nothing.  Event annotations are never deleted.
+func (s *EventAnnotationSyncer) getAllKubernetesObjectIDs(orgID string) ([]string, error) {
+	return nil, nil
+}
+
+// getAllGrafanaObjectIDs returns nothing.  Event annotations are never deleted.
+func (s *EventAnnotationSyncer) getAllGrafanaObjectIDs(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+// createWorkQueueItem skips the events the filter does not select.  The item's id is the event's
+// uid so its count is forgotten once the event is deleted.
+func (s *EventAnnotationSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
+	var key string
+	var err error
+
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	event, ok := obj.(*corev1.Event)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("expected event in workqueue but got %#v", obj))
+		return nil
+	}
+
+	if !s.filter.matches(event) {
+		return nil
+	}
+
+	item := NewWorkQueueItem(key, event.DeepCopyObject(), string(event.UID), "")
+
+	return &item
+}
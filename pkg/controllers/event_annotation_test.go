@@ -0,0 +1,292 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubeinformers "k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+)
+
+var eventTimestamp = time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC)
+
+func newWarningEvent(name string, reason string, podName string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+			UID:       types.UID(name + "-uid"),
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: metav1.NamespaceDefault,
+			Name:      podName,
+		},
+		Type:          corev1.EventTypeWarning,
+		Reason:        reason,
+		Message:       "Back-off restarting failed container",
+		Count:         1,
+		LastTimestamp: metav1.NewTime(eventTimestamp),
+		Source:        corev1.EventSource{Host: "node-1"},
+	}
+}
+
+// newEventAnnotationController returns a controller annotating the events filter selects since
+// before eventTimestamp and the informer factory its listers read from
+func (f *fixture) newEventAnnotationController(filter *EventAnnotationFilter) (*Controller, kubeinformers.SharedInformerFactory) {
+	kubeInformers := kubeinformers.NewSharedInformerFactory(f.kubeclient, 0)
+
+	c := NewEventAnnotationController(f.kubeclient, f.grafanaClient,
+		[]coreinformers.EventInformer{kubeInformers.Core().V1().Events()},
+		[]kubeinformers.SharedInformerFactory{kubeInformers},
+		filter)
+	c.informerSynced = alwaysReady
+	c.recorder = f.recorder
+	c.syncer.(*EventAnnotationSyncer).since = eventTimestamp.Add(-time.Minute)
+
+	return c, kubeInformers
+}
+
+func (f *fixture) syncEvent(c *Controller, kubeInformers kubeinformers.SharedInformerFactory, event *corev1.Event) {
+	if err := kubeInformers.Core().V1().Events().Informer().GetIndexer().Update(event); err != nil {
+		f.t.Fatal(err)
+	}
+
+	if err := f.sync(c, newItem(event, AddOrUpdate, string(event.UID), f.t)); err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+func (f *fixture) addedAnnotationTexts() []string {
+	var texts []string
+
+	for _, annotationJson := range f.grafanaClient.AddedAnnotations(context.Background()) {
+		var annotation struct {
+			Text string `json:"text"`
+		}
+
+		if err := json.Unmarshal([]byte(annotationJson), &annotation); err != nil {
+			f.t.Fatal(err)
+		}
+
+		texts = append(texts, annotation.Text)
+	}
+
+	return texts
+}
+
+func newEventAnnotationFilter(t *testing.T, namespaces string, selector string) *EventAnnotationFilter {
+	filter, err := NewEventAnnotationFilter(namespaces, DefaultEventAnnotationReasons, selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return filter
+}
+
+func TestAnnotatesWarningEventOncePerCount(t *testing.T) {
+	f := newFixture(t)
+
+	c, kubeInformers := f.newEventAnnotationController(newEventAnnotationFilter(t, "", ""))
+
+	event := newWarningEvent("api.1", "BackOff", "api")
+	f.syncEvent(c, kubeInformers, event)
+
+	// a resync of the same event is not annotated again
+	f.syncEvent(c, kubeInformers, event.DeepCopy())
+
+	event = event.DeepCopy()
+	event.Count = 3
+	event.LastTimestamp = metav1.NewTime(eventTimestamp.Add(time.Minute))
+	f.syncEvent(c, kubeInformers, event)
+
+	expected := []string{
+		"Pod default/api BackOff: Back-off restarting failed container",
+		"Pod default/api BackOff: Back-off restarting failed container (x3)",
+	}
+
+	if texts := f.addedAnnotationTexts(); !reflect.DeepEqual(texts, expected) {
+		t.Errorf("expected annotations %v but got %v", expected, texts)
+	}
+
+	expectedTags := []string{"event", "reason:BackOff", "kind:Pod", "namespace:default", "name:api", "node:node-1"}
+
+	if tags := f.addedAnnotationTags(); len(tags) == 0 || !reflect.DeepEqual(tags[0], expectedTags) {
+		t.Errorf("expected tags %v but got %v", expectedTags, tags)
+	}
+
+	if len(f.recorder.Events) != 0 {
+		t.Errorf("expected the event annotation controller not to record events")
+	}
+}
+
+func TestAnnotatesDeletedEventAgain(t *testing.T) {
+	f := newFixture(t)
+
+	c, kubeInformers := f.newEventAnnotationController(newEventAnnotationFilter(t, "", ""))
+
+	event := newWarningEvent("api.1", "BackOff", "api")
+	f.syncEvent(c, kubeInformers, event)
+
+	if err := f.sync(c, newItem(event, Delete, string(event.UID), t)); err != nil {
+		t.Fatal(err)
+	}
+
+	f.syncEvent(c, kubeInformers, event)
+
+	if added := f.grafanaClient.AddedAnnotations(context.Background()); len(added) != 2 {
+		t.Errorf("expected an event recreated after its deletion to be annotated again but got %v", added)
+	}
+}
+
+func TestFiltersEvents(t *testing.T) {
+	f := newFixture(t)
+
+	c, _ := f.newEventAnnotationController(newEventAnnotationFilter(t, "default, monitoring", ""))
+
+	normal := newWarningEvent("normal", "BackOff", "api")
+	normal.Type = corev1.EventTypeNormal
+
+	otherReason := newWarningEvent("other-reason", "FailedMount", "api")
+
+	otherNamespace := newWarningEvent("other-namespace", "BackOff", "api")
+	otherNamespace.Namespace = "kube-system"
+
+	for _, event := range []*corev1.Event{normal, otherReason, otherNamespace} {
+		if item := c.syncer.createWorkQueueItem(event); item != nil {
+			t.Errorf("expected event %s to be filtered but got %+v", event.Name, item)
+		}
+	}
+
+	if item := c.syncer.createWorkQueueItem(newWarningEvent("selected", "OOMKilled", "api")); item == nil {
+		t.Errorf("expected event selected to be queued")
+	}
+}
+
+func TestFiltersEventsOfItsOwnControllers(t *testing.T) {
+	f := newFixture(t)
+
+	// every reason is selected
+	filter, err := NewEventAnnotationFilter("", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ := f.newEventAnnotationController(filter)
+
+	// recorded when syncing an event fails
+	aboutEvent := newWarningEvent("about-event", ErrSyncFailed, "api")
+	aboutEvent.InvolvedObject.Kind = "Event"
+
+	recorded := newWarningEvent("recorded", ErrSyncFailed, "api")
+	recorded.Source = corev1.EventSource{Component: "grafana-dashboard-controller"}
+
+	for _, event := range []*corev1.Event{aboutEvent, recorded} {
+		if item := c.syncer.createWorkQueueItem(event); item != nil {
+			t.Errorf("expected event %s to be filtered but got %+v", event.Name, item)
+		}
+	}
+
+	if item := c.syncer.createWorkQueueItem(newWarningEvent("selected", ErrSyncFailed, "api")); item == nil {
+		t.Errorf("expected event selected to be queued")
+	}
+}
+
+func TestSkipsEventsBeforeStart(t *testing.T) {
+	f := newFixture(t)
+
+	c, kubeInformers := f.newEventAnnotationController(newEventAnnotationFilter(t, "", ""))
+
+	event := newWarningEvent("api.1", "BackOff", "api")
+	event.LastTimestamp = metav1.NewTime(eventTimestamp.Add(-time.Hour))
+
+	f.syncEvent(c, kubeInformers, event)
+
+	if added := f.grafanaClient.AddedAnnotations(context.Background()); len(added) != 0 {
+		t.Errorf("expected no annotations for an event before the controller started but got %v", added)
+	}
+}
+
+func TestFiltersEventsByInvolvedObjectLabels(t *testing.T) {
+	f := newFixture(t)
+
+	c, kubeInformers := f.newEventAnnotationController(newEventAnnotationFilter(t, "", "team=platform"))
+
+	for name, labels := range map[string]map[string]string{
+		"api":    {"team": "platform"},
+		"worker": {"team": "data"},
+	} {
+		pod := &corev1.Pod{ObjectMeta: newObjectMeta(name)}
+		pod.Labels = labels
+
+		if err := kubeInformers.Core().V1().Pods().Informer().GetIndexer().Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f.syncEvent(c, kubeInformers, newWarningEvent("api.1", "BackOff", "api"))
+	f.syncEvent(c, kubeInformers, newWarningEvent("worker.1", "BackOff", "worker"))
+	f.syncEvent(c, kubeInformers, newWarningEvent("deleted.1", "BackOff", "deleted"))
+
+	expected := []string{"Pod default/api BackOff: Back-off restarting failed container"}
+
+	if texts := f.addedAnnotationTexts(); !reflect.DeepEqual(texts, expected) {
+		t.Errorf("expected annotations %v but got %v", expected, texts)
+	}
+}
+
+func TestAnnotatesEventsOfEveryWatchedNamespace(t *testing.T) {
+	f := newFixture(t)
+
+	var eventInformers []coreinformers.EventInformer
+	var objectInformerFactories []kubeinformers.SharedInformerFactory
+
+	for _, namespace := range []string{metav1.NamespaceDefault, "monitoring"} {
+		eventInformers = append(eventInformers, kubeinformers.NewSharedInformerFactoryWithOptions(f.kubeclient, 0,
+			kubeinformers.WithNamespace(namespace)).Core().V1().Events())
+		objectInformerFactories = append(objectInformerFactories, kubeinformers.NewSharedInformerFactoryWithOptions(f.kubeclient, 0,
+			kubeinformers.WithNamespace(namespace)))
+	}
+
+	c := NewEventAnnotationController(f.kubeclient, f.grafanaClient, eventInformers, objectInformerFactories,
+		newEventAnnotationFilter(t, "default,monitoring", "team=platform"))
+	c.informerSynced = alwaysReady
+	c.recorder = f.recorder
+	c.syncer.(*EventAnnotationSyncer).since = eventTimestamp.Add(-time.Minute)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "prometheus", Namespace: "monitoring"}}
+	pod.Labels = map[string]string{"team": "platform"}
+
+	if err := objectInformerFactories[1].Core().V1().Pods().Informer().GetIndexer().Add(pod); err != nil {
+		t.Fatal(err)
+	}
+
+	event := newWarningEvent("prometheus.1", "OOMKilled", "prometheus")
+	event.Namespace = "monitoring"
+	event.InvolvedObject.Namespace = "monitoring"
+
+	if err := eventInformers[1].Informer().GetIndexer().Add(event); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.sync(c, newItem(event, AddOrUpdate, string(event.UID), t)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"Pod monitoring/prometheus OOMKilled: Back-off restarting failed container"}
+
+	if texts := f.addedAnnotationTexts(); !reflect.DeepEqual(texts, expected) {
+		t.Errorf("expected annotations %v but got %v", expected, texts)
+	}
+
+	// the pod is read from the informer, not the api
+	if actions := f.kubeclient.Actions(); len(actions) != 0 {
+		t.Errorf("expected no api requests but got %v", actions)
+	}
+}
@@ -1,13 +1,20 @@
 package controllers
 
 import (
+	"context"
 	"fmt"
 	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+	"reflect"
 
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	rbacinformers "k8s.io/client-go/informers/rbac/v1"
 	"k8s.io/client-go/kubernetes"
+	rbaclisters "k8s.io/client-go/listers/rbac/v1"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
@@ -19,31 +26,140 @@ import (
 
 // FolderSyncer is the controller implementation for Folder resources
 type FolderSyncer struct {
-	grafanaFoldersLister listers.FolderLister
-	grafanaClient        grafana.Interface
-	grafanaclientset     clientset.Interface
+	grafanaFoldersLister       listers.FolderLister
+	grafanaOrganizationsLister listers.OrganizationLister
+	grafanaTeamsLister         listers.TeamLister
+	grafanaClient              grafana.Interface
+	grafanaclientset           clientset.Interface
+	kubeclientset              kubernetes.Interface
+
+	// rbacPermissions derives folder permissions from RBAC when set
+	rbacPermissions           *RBACPermissions
+	roleBindingsLister        rbaclisters.RoleBindingLister
+	clusterRoleBindingsLister rbaclisters.ClusterRoleBindingLister
 }
 
-// NewFolderController returns a new grafana Folder controller
+// NewFolderController returns a new grafana Folder controller.  Folder permissions are derived
+// from the RBAC bindings of their namespace if rbacPermissions is not nil.  rbacInformers are
+// only used then and the folders bindings apply to are synced when the bindings change.
 func NewFolderController(
 	grafanaclientset clientset.Interface,
 	kubeclientset kubernetes.Interface,
 	grafanaClient grafana.Interface,
-	grafanaFolderInformer informers.FolderInformer) *Controller {
+	grafanaFolderInformer informers.FolderInformer,
+	grafanaOrganizationInformer informers.OrganizationInformer,
+	grafanaTeamInformer informers.TeamInformer,
+	rbacPermissions *RBACPermissions,
+	rbacInformers rbacinformers.Interface) *Controller {
 
 	syncer := &FolderSyncer{
-		grafanaFoldersLister: grafanaFolderInformer.Lister(),
-		grafanaClient:        grafanaClient,
-		grafanaclientset:     grafanaclientset,
+		grafanaFoldersLister:       grafanaFolderInformer.Lister(),
+		grafanaOrganizationsLister: grafanaOrganizationInformer.Lister(),
+		grafanaTeamsLister:         grafanaTeamInformer.Lister(),
+		grafanaClient:              grafanaClient,
+		grafanaclientset:           grafanaclientset,
+		kubeclientset:              kubeclientset,
+		rbacPermissions:            rbacPermissions,
 	}
 
 	controller := NewController(grafanaFolderInformer.Informer(),
 		kubeclientset,
 		syncer)
+	controller.watchOrganizations(grafanaOrganizationInformer)
+
+	if rbacPermissions != nil {
+		syncer.roleBindingsLister = rbacInformers.RoleBindings().Lister()
+		syncer.clusterRoleBindingsLister = rbacInformers.ClusterRoleBindings().Lister()
+
+		syncer.watchRBAC(controller, rbacInformers)
+	}
 
 	return controller
 }
 
+// watchRBAC syncs the folders in the namespace of a RoleBinding, or every folder for a
+// ClusterRoleBinding, when a binding to a mapped role changes
+func (s *FolderSyncer) watchRBAC(controller *Controller, rbacInformers rbacinformers.Interface) {
+	enqueueFolders := func(namespace string) {
+		folders, err := s.grafanaFoldersLister.Folders(namespace).List(labels.Everything())
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+
+		for _, folder := range folders {
+			controller.enqueueWorkQueueItem(folder, AddOrUpdate)
+		}
+	}
+
+	// mapped returns true if obj is a binding to a mapped role.  Bindings whose final state is
+	// unknown are assumed to be mapped.
+	mapped := func(obj interface{}) bool {
+		switch binding := obj.(type) {
+		case *rbacv1.RoleBinding:
+			_, ok := s.rbacPermissions.roleLevel(binding.RoleRef)
+			return ok
+		case *rbacv1.ClusterRoleBinding:
+			_, ok := s.rbacPermissions.roleLevel(binding.RoleRef)
+			return ok
+		}
+
+		return true
+	}
+
+	handler := func(enqueue func(obj interface{})) cache.ResourceEventHandler {
+		return cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				if mapped(obj) {
+					enqueue(obj)
+				}
+			},
+			UpdateFunc: func(old, new interface{}) {
+				oldMeta, oldErr := meta.Accessor(old)
+				newMeta, newErr := meta.Accessor(new)
+
+				// periodic resyncs change nothing
+				if oldErr == nil && newErr == nil && oldMeta.GetResourceVersion() == newMeta.GetResourceVersion() {
+					return
+				}
+
+				if mapped(old) || mapped(new) {
+					enqueue(new)
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+
+				if mapped(obj) {
+					enqueue(obj)
+				}
+			},
+		}
+	}
+
+	controller.watchInformer(rbacInformers.RoleBindings().Informer(), handler(func(obj interface{}) {
+		key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+
+		namespace, _, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+
+		enqueueFolders(namespace)
+	}))
+
+	controller.watchInformer(rbacInformers.ClusterRoleBindings().Informer(), handler(func(obj interface{}) {
+		enqueueFolders(metav1.NamespaceAll)
+	}))
+}
+
 func (s *FolderSyncer) getType() string {
 	return prometheus.TypeFolder
 }
@@ -52,18 +168,29 @@ func (s *FolderSyncer) getRuntimeObjectByName(name string, namespace string) (ru
 	return s.grafanaFoldersLister.Folders(namespace).Get(name)
 }
 
-func (s *FolderSyncer) deleteObjectById(id string) error {
-	return s.grafanaClient.DeleteFolder(id)
+func (s *FolderSyncer) deleteObjectById(ctx context.Context, id string) error {
+	return s.grafanaClient.DeleteFolder(ctx, id)
 }
 
-func (s *FolderSyncer) updateObject(object runtime.Object) error {
+func (s *FolderSyncer) updateObject(ctx context.Context, object runtime.Object) error {
 
 	grafanaFolder, ok := object.(*v1alpha1.Folder)
 	if !ok {
 		return fmt.Errorf("expected folder in but got %#v", object)
 	}
 
-	id, idForDashboards, err := s.grafanaClient.PostFolder(grafanaFolder.Spec.JSON, grafanaFolder.Status.GrafanaID)
+	ctx, orgID, grafanaID, err := targetOrganization(ctx,
+		s.grafanaOrganizationsLister,
+		grafanaFolder.Namespace,
+		grafanaFolder.Spec.OrganizationName,
+		grafanaFolder.Status.GrafanaOrgID,
+		grafanaFolder.Status.GrafanaID,
+		s.deleteObjectById)
+	if err != nil {
+		return err
+	}
+
+	id, idForDashboards, err := s.grafanaClient.PostFolder(ctx, grafanaFolder.Spec.JSON, grafanaID)
 
 	if err != nil {
 		return err
@@ -71,16 +198,61 @@ func (s *FolderSyncer) updateObject(object runtime.Object) error {
 
 	grafanaFolderCopy := grafanaFolder.DeepCopy()
 	grafanaFolderCopy.Status.GrafanaID = id
+	grafanaFolderCopy.Status.GrafanaOrgID = orgID
 	grafanaFolderCopy.Status.GrafanaIDForDashboards = idForDashboards
 
-	_, err = s.grafanaclientset.GrafanaV1alpha1().Folders(grafanaFolder.Namespace).UpdateStatus(grafanaFolderCopy)
+	updatedFolder, err := s.grafanaclientset.GrafanaV1alpha1().Folders(grafanaFolder.Namespace).UpdateStatus(grafanaFolderCopy)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	replace := grafanaFolder.Spec.ReplacePermissions
+	var derived []grafana.Permission
+
+	// folders follow the rbac of their namespace so the whole acl is replaced
+	if s.rbacPermissions != nil {
+		derived, err = s.rbacPermissions.folderPermissions(s.roleBindingsLister,
+			s.clusterRoleBindingsLister,
+			s.grafanaTeamsLister,
+			grafanaFolder.Namespace,
+			orgID,
+			func() (map[string]string, error) { return s.grafanaClient.GetOrgUserIds(ctx) })
+		if err != nil {
+			return err
+		}
+
+		replace = true
+	}
+
+	granted, err := reconcilePermissions(ctx,
+		s.grafanaClient,
+		s.grafanaTeamsLister,
+		grafanaFolder.Namespace,
+		orgID,
+		grafanaFolder.Spec.Permissions,
+		replace,
+		derived,
+		grantedInOrganization(grafanaFolder.Status.GrantedPermissions, grafanaFolder.Status.GrafanaOrgID, orgID),
+		func() ([]grafana.Permission, error) { return s.grafanaClient.GetFolderPermissions(ctx, id) },
+		func(permissions []grafana.Permission) error {
+			return s.grafanaClient.SetFolderPermissions(ctx, id, permissions)
+		})
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(granted, updatedFolder.Status.GrantedPermissions) {
+		return nil
+	}
+
+	updatedFolder = updatedFolder.DeepCopy()
+	updatedFolder.Status.GrantedPermissions = granted
+
+	_, err = s.grafanaclientset.GrafanaV1alpha1().Folders(grafanaFolder.Namespace).UpdateStatus(updatedFolder)
+	return err
 }
 
-func (s *FolderSyncer) getAllKubernetesObjectIDs() ([]string, error) {
+func (s *FolderSyncer) getAllKubernetesObjectIDs(orgID string) ([]string, error) {
 	Folders, err := s.grafanaFoldersLister.List(labels.Everything())
 
 	if err != nil {
@@ -90,14 +262,19 @@ func (s *FolderSyncer) getAllKubernetesObjectIDs() ([]string, error) {
 	ids := make([]string, 0)
 
 	for _, Folder := range Folders {
+		// objects without an id may be in any organization
+		if Folder.Status.GrafanaOrgID != orgID && Folder.Status.GrafanaID != grafana.NO_ID {
+			continue
+		}
+
 		ids = append(ids, Folder.Status.GrafanaID)
 	}
 
 	return ids, nil
 }
 
-func (s *FolderSyncer) getAllGrafanaObjectIDs() ([]string, error) {
-	return s.grafanaClient.GetAllFolderIds()
+func (s *FolderSyncer) getAllGrafanaObjectIDs(ctx context.Context) ([]string, error) {
+	return s.grafanaClient.GetAllFolderIds(ctx)
 }
 
 func (s *FolderSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
@@ -116,7 +293,7 @@ func (s *FolderSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
 		return nil
 	}
 
-	item := NewWorkQueueItem(key, folder.DeepCopyObject(), folder.Status.GrafanaID) // todo: confirm this doesnt need null checking
+	item := NewWorkQueueItem(key, folder.DeepCopyObject(), folder.Status.GrafanaID, folder.Status.GrafanaOrgID) // todo: confirm this doesnt need null checking
 
 	return &item
 }
@@ -1,65 +1,97 @@
 package controllers
 
 import (
+	"context"
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	k8sfake "k8s.io/client-go/kubernetes/fake"
-	"k8s.io/client-go/tools/record"
 
-	grafanacontroller "github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
-	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned/fake"
-	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions"
-	grafana "github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana/fake"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
 )
 
-func newGrafanaDashboard(name string, dashboardJson string) *grafanacontroller.GrafanaDashboard {
-	return &grafanacontroller.GrafanaDashboard{
-		TypeMeta: metav1.TypeMeta{APIVersion: grafanacontroller.SchemeGroupVersion.String()},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: metav1.NamespaceDefault,
-		},
-		Spec: grafanacontroller.GrafanaDashboardSpec{
-			DashboardJSON: dashboardJson,
+func newFolder(name string, folderJson string) *v1alpha1.Folder {
+	return &v1alpha1.Folder{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: newObjectMeta(name),
+		Spec: v1alpha1.FolderSpec{
+			JSON: folderJson,
 		},
 	}
 }
 
-func newDashboardController(f *fixture) (*Controller, informers.SharedInformerFactory) {
-	f.client = fake.NewSimpleClientset(f.objects...)
-	f.kubeclient = k8sfake.NewSimpleClientset(f.kubeobjects...)
-	f.grafanaClient = grafana.NewGrafanaClientFake("https://example.com", FAKE_UID)
+func newFolderController(f *fixture) *Controller {
+	return NewFolderController(f.client, f.kubeclient, f.grafanaClient,
+		f.informers.Grafana().V1alpha1().Folders(),
+		f.informers.Grafana().V1alpha1().Organizations(),
+		f.informers.Grafana().V1alpha1().Teams(),
+		nil,
+		nil)
+}
+
+func (f *fixture) getFolder(name string) *v1alpha1.Folder {
+	folder, err := f.client.GrafanaV1alpha1().Folders(metav1.NamespaceDefault).Get(name, metav1.GetOptions{})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return folder
+}
+
+func TestCreatesFolder(t *testing.T) {
+	folder := newFolder("test", `{"title": "test"}`)
+
+	f := newFixture(t, folder)
+	c := f.newController(newFolderController)
+
+	if err := f.sync(c, newItem(folder, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	status := f.getFolder("test").Status
+	if status.GrafanaID == "" || status.GrafanaIDForDashboards == "" {
+		t.Fatalf("expected the folder status to be updated with its grafana ids, got %#v", status)
+	}
 
-	i := informers.NewSharedInformerFactory(f.client, noResyncPeriodFunc())
+	if _, err := f.grafanaClient.GetFolder(context.Background(), status.GrafanaID); err != nil {
+		t.Errorf("expected folder in grafana: %v", err)
+	}
+}
 
-	c := NewDashboardController(f.client, f.kubeclient,
-		f.grafanaClient, i.Grafana().V1alpha1().GrafanaDashboards())
+func TestRecreatesFolderMissingFromGrafana(t *testing.T) {
+	folder := newFolder("test", `{"title": "test"}`)
+	folder.Status.GrafanaID = "deleted"
 
-	c.informerSynced = alwaysReady
-	c.syncer.(*DashboardSyncer).recorder = &record.FakeRecorder{}
+	f := newFixture(t, folder)
+	c := f.newController(newFolderController)
 
-	for _, d := range f.grafanaDashboardLister {
-		i.Grafana().V1alpha1().GrafanaDashboards().Informer().GetIndexer().Add(d)
+	if err := f.sync(c, newItem(folder, AddOrUpdate, "deleted", t)); err != nil {
+		t.Fatal(err)
 	}
 
-	return c, i
+	uid := f.getFolder("test").Status.GrafanaID
+	if _, err := f.grafanaClient.GetFolder(context.Background(), uid); err != nil {
+		t.Errorf("expected folder to be recreated in grafana: %v", err)
+	}
 }
 
-func TestCreatesGrafanaDashboard(t *testing.T) {
+func TestDeletesFolderNoLongerInKubernetes(t *testing.T) {
+	folder := newFolder("test", `{"title": "test"}`)
 
 	f := newFixture(t)
-	dashboardJson := "{ 'test': 'test' }"
 
-	dashboard := newGrafanaDashboard("test", dashboardJson)
-	item := NewWorkQueueItem(getKey(dashboard, t), nil, "")
+	uid, _, err := f.grafanaClient.PostFolder(context.Background(), folder.Spec.JSON, "")
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	f.grafanaDashboardLister = append(f.grafanaDashboardLister, dashboard)
-	f.objects = append(f.objects, dashboard)
+	c := f.newController(newFolderController)
 
-	dashboard.Status.GrafanaUID = FAKE_UID
-	f.expectUpdateGrafanaObject(dashboard, dashboard.Namespace, "grafanadashboards")
-	f.expectGrafanaPost(dashboardJson)
+	// an update for an object that has since been removed from the lister
+	if err := f.sync(c, newItem(folder, AddOrUpdate, uid, t)); err != nil {
+		t.Fatal(err)
+	}
 
-	f.runController(newDashboardController, item, true, false)
+	if calls := f.grafanaClient.CallsTo("DeleteFolder"); len(calls) != 1 || calls[0].Args[0] != uid {
+		t.Errorf("expected folder %s to be deleted, got %v", uid, calls)
+	}
 }
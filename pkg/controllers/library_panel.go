@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	clientset "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned"
+	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions/grafana/v1alpha1"
+	listers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/listers/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+// LibraryPanelSyncer is the controller implementation for LibraryPanel resources
+type LibraryPanelSyncer struct {
+	grafanaLibraryPanelsLister listers.LibraryPanelLister
+	grafanaFoldersLister       listers.FolderLister
+	grafanaOrganizationsLister listers.OrganizationLister
+	grafanaClient              grafana.Interface
+	grafanaclientset           clientset.Interface
+
+	// library panels are not marked in grafana.  only recorded ones are garbage collected
+	managedIDs *managedIDs
+}
+
+// NewLibraryPanelController returns a new grafana LibraryPanel controller
+func NewLibraryPanelController(
+	grafanaclientset clientset.Interface,
+	kubeclientset kubernetes.Interface,
+	grafanaClient grafana.Interface,
+	grafanaLibraryPanelInformer informers.LibraryPanelInformer,
+	grafanaFolderInformer informers.FolderInformer,
+	grafanaOrganizationInformer informers.OrganizationInformer) *Controller {
+
+	syncer := &LibraryPanelSyncer{
+		grafanaLibraryPanelsLister: grafanaLibraryPanelInformer.Lister(),
+		grafanaFoldersLister:       grafanaFolderInformer.Lister(),
+		grafanaOrganizationsLister: grafanaOrganizationInformer.Lister(),
+		grafanaClient:              grafanaClient,
+		grafanaclientset:           grafanaclientset,
+		managedIDs:                 newManagedIDs(),
+	}
+
+	controller := NewController(grafanaLibraryPanelInformer.Informer(),
+		kubeclientset,
+		syncer)
+	controller.watchOrganizations(grafanaOrganizationInformer)
+
+	return controller
+}
+
+func (s *LibraryPanelSyncer) getType() string {
+	return prometheus.TypeLibraryPanel
+}
+
+func (s *LibraryPanelSyncer) getRuntimeObjectByName(name string, namespace string) (runtime.Object, error) {
+	return s.grafanaLibraryPanelsLister.LibraryPanels(namespace).Get(name)
+}
+
+func (s *LibraryPanelSyncer) deleteObjectById(ctx context.Context, id string) error {
+	if err := s.grafanaClient.DeleteLibraryPanel(ctx, id); err != nil {
+		return err
+	}
+
+	s.managedIDs.remove(grafana.OrgID(ctx), id)
+	return nil
+}
+
+func (s *LibraryPanelSyncer) updateObject(ctx context.Context, object runtime.Object) error {
+
+	grafanaLibraryPanel, ok := object.(*v1alpha1.LibraryPanel)
+	if !ok {
+		return fmt.Errorf("expected library panel in but got %#v", object)
+	}
+
+	ctx, orgID, grafanaID, err := targetOrganization(ctx,
+		s.grafanaOrganizationsLister,
+		grafanaLibraryPanel.Namespace,
+		grafanaLibraryPanel.Spec.OrganizationName,
+		grafanaLibraryPanel.Status.GrafanaOrgID,
+		grafanaLibraryPanel.Status.GrafanaID,
+		s.deleteObjectById)
+	if err != nil {
+		return err
+	}
+
+	var folderId, folderUid string
+
+	if grafanaLibraryPanel.Spec.FolderName != "" {
+		folder, err := s.grafanaFoldersLister.Folders(grafanaLibraryPanel.Namespace).Get(grafanaLibraryPanel.Spec.FolderName)
+		if err != nil {
+			return err
+		}
+
+		if folder.Status.GrafanaID == grafana.NO_ID {
+			return fmt.Errorf("folder %s has not been created in grafana yet", folder.Name)
+		}
+
+		if folder.Status.GrafanaOrgID != orgID {
+			return fmt.Errorf("folder %s is not in the same organization as library panel %s", folder.Name, grafanaLibraryPanel.Name)
+		}
+
+		folderId = folder.Status.GrafanaIDForDashboards
+		folderUid = folder.Status.GrafanaID
+	}
+
+	libraryPanelJson, err := libraryPanelJson(grafanaLibraryPanel)
+	if err != nil {
+		return err
+	}
+
+	id, err := s.grafanaClient.PostLibraryPanel(ctx, libraryPanelJson, folderId, folderUid, grafanaID)
+
+	if err != nil {
+		return err
+	}
+
+	s.managedIDs.add(orgID, id)
+
+	grafanaLibraryPanelCopy := grafanaLibraryPanel.DeepCopy()
+	grafanaLibraryPanelCopy.Status.GrafanaID = id
+	grafanaLibraryPanelCopy.Status.GrafanaOrgID = orgID
+
+	_, err = s.grafanaclientset.GrafanaV1alpha1().LibraryPanels(grafanaLibraryPanel.Namespace).UpdateStatus(grafanaLibraryPanelCopy)
+
+	return err
+}
+
+// libraryPanelJson returns the name and model PostLibraryPanel expects
+func libraryPanelJson(libraryPanel *v1alpha1.LibraryPanel) (string, error) {
+	var model map[string]interface{}
+
+	if err := json.Unmarshal([]byte(libraryPanel.Spec.JSON), &model); err != nil {
+		return "", err
+	}
+
+	libraryPanelJson, err := json.Marshal(map[string]interface{}{
+		"name":  libraryPanelName(libraryPanel),
+		"model": model,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(libraryPanelJson), nil
+}
+
+// libraryPanelName returns the name of a library panel in grafana
+func libraryPanelName(libraryPanel *v1alpha1.LibraryPanel) string {
+	return defaultString(libraryPanel.Spec.Name, libraryPanel.Name)
+}
+
+func (s *LibraryPanelSyncer) getAllKubernetesObjectIDs(orgID string) ([]string, error) {
+	libraryPanels, err := s.grafanaLibraryPanelsLister.List(labels.Everything())
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+
+	for _, libraryPanel := range libraryPanels {
+		// objects without an id may be in any organization
+		if libraryPanel.Status.GrafanaOrgID != orgID && libraryPanel.Status.GrafanaID != grafana.NO_ID {
+			continue
+		}
+
+		if libraryPanel.Status.GrafanaID != grafana.NO_ID {
+			s.managedIDs.add(orgID, libraryPanel.Status.GrafanaID)
+		}
+
+		ids = append(ids, libraryPanel.Status.GrafanaID)
+	}
+
+	return ids, nil
+}
+
+// getAllGrafanaObjectIDs returns the library panels the syncer created or found in the status of
+// a LibraryPanel.  Library panels created in the ui are never deleted.
+func (s *LibraryPanelSyncer) getAllGrafanaObjectIDs(ctx context.Context) ([]string, error) {
+	ids, err := s.grafanaClient.GetAllLibraryPanelIds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.managedIDs.filter(grafana.OrgID(ctx), ids), nil
+}
+
+func (s *LibraryPanelSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
+	var key string
+	var err error
+	var libraryPanel *v1alpha1.LibraryPanel
+	var ok bool
+
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	if libraryPanel, ok = obj.(*v1alpha1.LibraryPanel); !ok {
+		utilruntime.HandleError(fmt.Errorf("expected library panel in workqueue but got %#v", obj))
+		return nil
+	}
+
+	item := NewWorkQueueItem(key, libraryPanel.DeepCopyObject(), libraryPanel.Status.GrafanaID, libraryPanel.Status.GrafanaOrgID)
+
+	return &item
+}
@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+)
+
+func newLibraryPanel(name string, panelJson string, folderName string) *v1alpha1.LibraryPanel {
+	return &v1alpha1.LibraryPanel{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: newObjectMeta(name),
+		Spec: v1alpha1.LibraryPanelSpec{
+			FolderName: folderName,
+			JSON:       panelJson,
+		},
+	}
+}
+
+func newLibraryPanelController(f *fixture) *Controller {
+	return NewLibraryPanelController(f.client, f.kubeclient, f.grafanaClient,
+		f.informers.Grafana().V1alpha1().LibraryPanels(),
+		f.informers.Grafana().V1alpha1().Folders(),
+		f.informers.Grafana().V1alpha1().Organizations())
+}
+
+func (f *fixture) getLibraryPanel(name string) *v1alpha1.LibraryPanel {
+	libraryPanel, err := f.client.GrafanaV1alpha1().LibraryPanels(metav1.NamespaceDefault).Get(name, metav1.GetOptions{})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return libraryPanel
+}
+
+func TestCreatesLibraryPanel(t *testing.T) {
+	libraryPanel := newLibraryPanel("cpu", `{"type": "graph", "title": "CPU"}`, "")
+
+	f := newFixture(t, libraryPanel)
+	c := f.newController(newLibraryPanelController)
+
+	if err := f.sync(c, newItem(libraryPanel, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	uid := f.getLibraryPanel("cpu").Status.GrafanaID
+	if uid == "" {
+		t.Fatal("expected the library panel status to be updated with its grafana uid")
+	}
+
+	if _, err := f.grafanaClient.GetLibraryPanel(context.Background(), uid); err != nil {
+		t.Errorf("expected library panel in grafana: %v", err)
+	}
+
+	calls := f.grafanaClient.CallsTo("PostLibraryPanel")
+	if len(calls) != 1 {
+		t.Fatalf("expected one post, got %v", calls)
+	}
+
+	var posted struct {
+		Name  string                 `json:"name"`
+		Model map[string]interface{} `json:"model"`
+	}
+
+	if err := json.Unmarshal([]byte(calls[0].Args[0]), &posted); err != nil {
+		t.Fatal(err)
+	}
+
+	if posted.Name != "cpu" || posted.Model["title"] != "CPU" {
+		t.Errorf("expected the name to default to the object's name and the model to be posted, got %+v", posted)
+	}
+}
+
+func TestCreatesLibraryPanelInFolder(t *testing.T) {
+	f := newFixture(t)
+
+	folder := f.createFolder("panels")
+	libraryPanel := newLibraryPanel("cpu", `{"type": "graph"}`, "panels")
+
+	f = f.withObjects(folder, libraryPanel)
+	c := f.newController(newLibraryPanelController)
+
+	if err := f.sync(c, newItem(libraryPanel, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := f.grafanaClient.CallsTo("PostLibraryPanel")
+	if len(calls) != 1 || calls[0].Args[1] != folder.Status.GrafanaIDForDashboards || calls[0].Args[2] != folder.Status.GrafanaID {
+		t.Errorf("expected the library panel to be posted to folder %s, got %v", folder.Status.GrafanaID, calls)
+	}
+}
+
+func TestLibraryPanelWaitsForFolder(t *testing.T) {
+	libraryPanel := newLibraryPanel("cpu", `{"type": "graph"}`, "panels")
+
+	f := newFixture(t, newFolder("panels", `{"title": "panels"}`), libraryPanel)
+	c := f.newController(newLibraryPanelController)
+
+	if err := f.sync(c, newItem(libraryPanel, AddOrUpdate, "", t)); err == nil {
+		t.Error("expected an error for a folder that has not been created in grafana yet")
+	}
+
+	if calls := f.grafanaClient.CallsTo("PostLibraryPanel"); len(calls) != 0 {
+		t.Errorf("expected nothing to be posted, got %v", calls)
+	}
+}
+
+func TestResyncOnlyDeletesManagedLibraryPanels(t *testing.T) {
+	libraryPanel := newLibraryPanel("cpu", `{"type": "graph", "title": "CPU"}`, "")
+
+	f := newFixture(t, libraryPanel)
+	c := f.newController(newLibraryPanelController)
+
+	if err := f.sync(c, newItem(libraryPanel, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	managedUid := f.getLibraryPanel("cpu").Status.GrafanaID
+
+	// e.g. created in the ui
+	unmanagedUid, err := f.grafanaClient.PostLibraryPanel(context.Background(), `{"name": "memory", "model": {"type": "graph"}}`, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the library panel is deleted without its delete being handled
+	if err := f.informers.Grafana().V1alpha1().LibraryPanels().Informer().GetIndexer().Delete(libraryPanel); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.resyncDeletedObjects(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.grafanaClient.GetLibraryPanel(context.Background(), managedUid); err == nil {
+		t.Errorf("expected managed library panel %s to be deleted", managedUid)
+	}
+
+	if _, err := f.grafanaClient.GetLibraryPanel(context.Background(), unmanagedUid); err != nil {
+		t.Errorf("expected library panel %s created in the ui to be kept: %v", unmanagedUid, err)
+	}
+}
@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"sync"
+)
+
+// managedIDs records the grafana objects a syncer created or found in the status of a kubernetes
+// object.  Grafana has no way to mark some object types as managed, so garbage collection of
+// those types only deletes recorded objects and leaves objects created by hand alone.  The record
+// is kept in memory.  An object whose kubernetes object is deleted while the controller is not
+// running is left in grafana.
+type managedIDs struct {
+	lock sync.Mutex
+	ids  map[string]bool
+}
+
+func newManagedIDs() *managedIDs {
+	return &managedIDs{
+		ids: make(map[string]bool),
+	}
+}
+
+func managedIDKey(orgID string, id string) string {
+	return orgID + "/" + id
+}
+
+// add records the object id in the organization orgID
+func (m *managedIDs) add(orgID string, id string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.ids[managedIDKey(orgID, id)] = true
+}
+
+// remove forgets the object id in the organization orgID once it is deleted
+func (m *managedIDs) remove(orgID string, id string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.ids, managedIDKey(orgID, id))
+}
+
+// filter returns the ids recorded in the organization orgID
+func (m *managedIDs) filter(orgID string, ids []string) []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var managed []string
+
+	for _, id := range ids {
+		if m.ids[managedIDKey(orgID, id)] {
+			managed = append(managed, id)
+		}
+	}
+
+	return managed
+}
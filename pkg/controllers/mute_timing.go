@@ -0,0 +1,222 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	clientset "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned"
+	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions/grafana/v1alpha1"
+	listers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/listers/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+// MuteTimingSyncer is the controller implementation for MuteTiming resources
+type MuteTimingSyncer struct {
+	grafanaMuteTimingsLister   listers.MuteTimingLister
+	grafanaOrganizationsLister listers.OrganizationLister
+	grafanaClient              grafana.Interface
+	grafanaclientset           clientset.Interface
+
+	// other provisioning clients, like terraform, create mute timings with the same provenance.
+	// only recorded ones are garbage collected
+	managedIDs *managedIDs
+}
+
+// NewMuteTimingController returns a new grafana MuteTiming controller
+func NewMuteTimingController(
+	grafanaclientset clientset.Interface,
+	kubeclientset kubernetes.Interface,
+	grafanaClient grafana.Interface,
+	grafanaMuteTimingInformer informers.MuteTimingInformer,
+	grafanaOrganizationInformer informers.OrganizationInformer) *Controller {
+
+	syncer := &MuteTimingSyncer{
+		grafanaMuteTimingsLister:   grafanaMuteTimingInformer.Lister(),
+		grafanaOrganizationsLister: grafanaOrganizationInformer.Lister(),
+		grafanaClient:              grafanaClient,
+		grafanaclientset:           grafanaclientset,
+		managedIDs:                 newManagedIDs(),
+	}
+
+	controller := NewController(grafanaMuteTimingInformer.Informer(),
+		kubeclientset,
+		syncer)
+	controller.watchOrganizations(grafanaOrganizationInformer)
+
+	return controller
+}
+
+func (s *MuteTimingSyncer) getType() string {
+	return prometheus.TypeMuteTiming
+}
+
+func (s *MuteTimingSyncer) getRuntimeObjectByName(name string, namespace string) (runtime.Object, error) {
+	return s.grafanaMuteTimingsLister.MuteTimings(namespace).Get(name)
+}
+
+func (s *MuteTimingSyncer) deleteObjectById(ctx context.Context, id string) error {
+	if err := s.grafanaClient.DeleteMuteTiming(ctx, id); err != nil {
+		return err
+	}
+
+	s.managedIDs.remove(grafana.OrgID(ctx), id)
+	return nil
+}
+
+func (s *MuteTimingSyncer) updateObject(ctx context.Context, object runtime.Object) error {
+
+	grafanaMuteTiming, ok := object.(*v1alpha1.MuteTiming)
+	if !ok {
+		return fmt.Errorf("expected mute timing in but got %#v", object)
+	}
+
+	ctx, orgID, grafanaID, err := targetOrganization(ctx,
+		s.grafanaOrganizationsLister,
+		grafanaMuteTiming.Namespace,
+		grafanaMuteTiming.Spec.OrganizationName,
+		grafanaMuteTiming.Status.GrafanaOrgID,
+		grafanaMuteTiming.Status.GrafanaID,
+		s.deleteObjectById)
+	if err != nil {
+		return err
+	}
+
+	muteTimingJson, err := muteTimingJson(grafanaMuteTiming)
+	if err != nil {
+		return err
+	}
+
+	id, err := s.grafanaClient.PostMuteTiming(ctx, muteTimingJson, grafanaID)
+
+	if err != nil {
+		return err
+	}
+
+	s.managedIDs.add(orgID, id)
+
+	grafanaMuteTimingCopy := grafanaMuteTiming.DeepCopy()
+	grafanaMuteTimingCopy.Status.GrafanaID = id
+	grafanaMuteTimingCopy.Status.GrafanaOrgID = orgID
+
+	_, err = s.grafanaclientset.GrafanaV1alpha1().MuteTimings(grafanaMuteTiming.Namespace).UpdateStatus(grafanaMuteTimingCopy)
+	if err != nil {
+		return err
+	}
+
+	// a renamed mute timing can only be deleted once the policy tree no longer uses it.  until
+	// then it is left for the resync of deleted objects, which only deletes recorded names
+	if grafanaID != grafana.NO_ID && grafanaID != id {
+		s.managedIDs.add(orgID, grafanaID)
+
+		if err := s.deleteObjectById(ctx, grafanaID); err != nil {
+			utilruntime.HandleError(fmt.Errorf("deleting renamed mute timing %s: %v", grafanaID, err))
+		}
+	}
+
+	return nil
+}
+
+// muteTimingJson adds the name of a mute timing to its json
+func muteTimingJson(muteTiming *v1alpha1.MuteTiming) (string, error) {
+	var model map[string]interface{}
+
+	if err := json.Unmarshal([]byte(muteTiming.Spec.JSON), &model); err != nil {
+		return "", err
+	}
+
+	if name, _ := model["name"].(string); name == "" {
+		model["name"] = muteTiming.Name
+	}
+
+	muteTimingJson, err := json.Marshal(model)
+	if err != nil {
+		return "", err
+	}
+
+	return string(muteTimingJson), nil
+}
+
+func (s *MuteTimingSyncer) getAllKubernetesObjectIDs(orgID string) ([]string, error) {
+	muteTimings, err := s.grafanaMuteTimingsLister.List(labels.Everything())
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+
+	for _, muteTiming := range muteTimings {
+		// objects without an id may be in any organization
+		if muteTiming.Status.GrafanaOrgID != orgID && muteTiming.Status.GrafanaID != grafana.NO_ID {
+			continue
+		}
+
+		if muteTiming.Status.GrafanaID != grafana.NO_ID {
+			s.managedIDs.add(orgID, muteTiming.Status.GrafanaID)
+		}
+
+		ids = append(ids, muteTiming.Status.GrafanaID)
+	}
+
+	return ids, nil
+}
+
+// getAllGrafanaObjectIDs returns the mute timings the syncer created or found in the status of a
+// MuteTiming.  Mute timings created by hand or by other provisioning clients are never deleted.
+func (s *MuteTimingSyncer) getAllGrafanaObjectIDs(ctx context.Context) ([]string, error) {
+	ids, err := s.grafanaClient.GetAllMuteTimingIds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.managedIDs.filter(grafana.OrgID(ctx), ids), nil
+}
+
+func (s *MuteTimingSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
+	var key string
+	var err error
+	var muteTiming *v1alpha1.MuteTiming
+	var ok bool
+
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	if muteTiming, ok = obj.(*v1alpha1.MuteTiming); !ok {
+		utilruntime.HandleError(fmt.Errorf("expected mute timing in workqueue but got %#v", obj))
+		return nil
+	}
+
+	item := NewWorkQueueItem(key, muteTiming.DeepCopyObject(), muteTiming.Status.GrafanaID, muteTiming.Status.GrafanaOrgID)
+
+	return &item
+}
+
+// muteTimingName returns the grafana name of the mute timing name in namespace.  The mute timing
+// must have been created in the organization orgID.
+func muteTimingName(lister listers.MuteTimingLister, namespace string, name string, orgID string) (string, error) {
+	muteTiming, err := lister.MuteTimings(namespace).Get(name)
+	if err != nil {
+		return "", err
+	}
+
+	if muteTiming.Status.GrafanaID == grafana.NO_ID {
+		return "", fmt.Errorf("mute timing %s has not been created in grafana yet", name)
+	}
+
+	if muteTiming.Status.GrafanaOrgID != orgID {
+		return "", fmt.Errorf("mute timing %s is not in the same organization as its route", name)
+	}
+
+	return muteTiming.Status.GrafanaID, nil
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+)
+
+func newMuteTiming(name string, muteTimingJson string) *v1alpha1.MuteTiming {
+	return &v1alpha1.MuteTiming{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: newObjectMeta(name),
+		Spec: v1alpha1.MuteTimingSpec{
+			JSON: muteTimingJson,
+		},
+	}
+}
+
+func newMuteTimingController(f *fixture) *Controller {
+	return NewMuteTimingController(f.client, f.kubeclient, f.grafanaClient,
+		f.informers.Grafana().V1alpha1().MuteTimings(),
+		f.informers.Grafana().V1alpha1().Organizations())
+}
+
+func (f *fixture) getMuteTiming(name string) *v1alpha1.MuteTiming {
+	muteTiming, err := f.client.GrafanaV1alpha1().MuteTimings(metav1.NamespaceDefault).Get(name, metav1.GetOptions{})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return muteTiming
+}
+
+func TestCreatesMuteTiming(t *testing.T) {
+	muteTiming := newMuteTiming("weekends", `{"time_intervals": [{"weekdays": ["saturday", "sunday"]}]}`)
+
+	f := newFixture(t, muteTiming)
+	c := f.newController(newMuteTimingController)
+
+	if err := f.sync(c, newItem(muteTiming, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if id := f.getMuteTiming("weekends").Status.GrafanaID; id != "weekends" {
+		t.Fatalf("expected the name to default to the object's name, got %q", id)
+	}
+
+	if _, err := f.grafanaClient.GetMuteTiming(context.Background(), "weekends"); err != nil {
+		t.Errorf("expected mute timing in grafana: %v", err)
+	}
+}
+
+func TestRenamedMuteTimingDeletesOldName(t *testing.T) {
+	f := newFixture(t)
+
+	if _, err := f.grafanaClient.PostMuteTiming(context.Background(), `{"name": "old"}`, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	muteTiming := newMuteTiming("weekends", `{"name": "new"}`)
+	muteTiming.Status.GrafanaID = "old"
+
+	f = f.withObjects(muteTiming)
+	c := f.newController(newMuteTimingController)
+
+	if err := f.sync(c, newItem(muteTiming, AddOrUpdate, "old", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if id := f.getMuteTiming("weekends").Status.GrafanaID; id != "new" {
+		t.Errorf("expected the status to record the new name, got %q", id)
+	}
+
+	if _, err := f.grafanaClient.GetMuteTiming(context.Background(), "old"); !grafana.IsNotFound(err) {
+		t.Errorf("expected the old mute timing to be deleted, got %v", err)
+	}
+}
+
+func TestRenamedMuteTimingInUseIsDeletedByResync(t *testing.T) {
+	f := newFixture(t)
+
+	if _, err := f.grafanaClient.PostMuteTiming(context.Background(), `{"name": "old"}`, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	muteTiming := newMuteTiming("weekends", `{"name": "new"}`)
+	muteTiming.Status.GrafanaID = "old"
+
+	f = f.withObjects(muteTiming)
+	f.grafanaClient.FailNext("DeleteMuteTiming", &grafana.APIError{StatusCode: 409, Message: "mute timing is in use"})
+	c := f.newController(newMuteTimingController)
+
+	if err := f.sync(c, newItem(muteTiming, AddOrUpdate, "old", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	f.index(f.getMuteTiming("weekends"))
+
+	if err := c.resyncDeletedObjects(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.grafanaClient.GetMuteTiming(context.Background(), "old"); !grafana.IsNotFound(err) {
+		t.Errorf("expected the old mute timing to be deleted once it is no longer used, got %v", err)
+	}
+}
+
+func TestResyncOnlyDeletesManagedMuteTimings(t *testing.T) {
+	muteTiming := newMuteTiming("weekends", `{"time_intervals": [{"weekdays": ["saturday", "sunday"]}]}`)
+
+	f := newFixture(t, muteTiming)
+	c := f.newController(newMuteTimingController)
+
+	if err := f.sync(c, newItem(muteTiming, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	// e.g. created by terraform, which uses the same provisioning api
+	if _, err := f.grafanaClient.PostMuteTiming(context.Background(), `{"name": "terraform"}`, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// the mute timing is deleted without its delete being handled
+	if err := f.informers.Grafana().V1alpha1().MuteTimings().Informer().GetIndexer().Delete(muteTiming); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.resyncDeletedObjects(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.grafanaClient.GetMuteTiming(context.Background(), "weekends"); err == nil {
+		t.Error("expected managed mute timing weekends to be deleted")
+	}
+
+	if _, err := f.grafanaClient.GetMuteTiming(context.Background(), "terraform"); err != nil {
+		t.Errorf("expected mute timing terraform created by another client to be kept: %v", err)
+	}
+}
@@ -0,0 +1,605 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	clientset "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned"
+	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions/grafana/v1alpha1"
+	listers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/listers/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+// RouteSkipped is the reason of the warning recorded on a NotificationRoute that is left out of
+// the policy tree because its organization does not resolve
+const RouteSkipped = "RouteSkipped"
+
+// matcherOperators are the operators of a matcher.  Two character operators are checked first.
+var matcherOperators = []string{"=~", "!~", "!=", "="}
+
+// NotificationPolicySyncer is the controller implementation for NotificationPolicy resources.  The
+// policy tree of an organization is built from its NotificationPolicy and every NotificationRoute
+// in the organization.
+type NotificationPolicySyncer struct {
+	grafanaNotificationPoliciesLister listers.NotificationPolicyLister
+	grafanaNotificationRoutesLister   listers.NotificationRouteLister
+	grafanaContactPointsLister        listers.ContactPointLister
+	grafanaMuteTimingsLister          listers.MuteTimingLister
+	grafanaOrganizationsLister        listers.OrganizationLister
+	grafanaClient                     grafana.Interface
+	grafanaclientset                  clientset.Interface
+
+	// recordWarning records a warning event on a NotificationRoute left out of the tree
+	recordWarning func(object runtime.Object, reason string, message string)
+
+	// trees edited in the ui or by other provisioning clients are never reset.  only organizations
+	// whose tree the syncer set are recorded
+	managedIDs *managedIDs
+}
+
+// NewNotificationPolicyController returns a new grafana NotificationPolicy controller.  Policies
+// are synced again whenever a NotificationRoute, ContactPoint or MuteTiming changes.
+func NewNotificationPolicyController(
+	grafanaclientset clientset.Interface,
+	kubeclientset kubernetes.Interface,
+	grafanaClient grafana.Interface,
+	grafanaNotificationPolicyInformer informers.NotificationPolicyInformer,
+	grafanaNotificationRouteInformer informers.NotificationRouteInformer,
+	grafanaContactPointInformer informers.ContactPointInformer,
+	grafanaMuteTimingInformer informers.MuteTimingInformer,
+	grafanaOrganizationInformer informers.OrganizationInformer) *Controller {
+
+	syncer := &NotificationPolicySyncer{
+		grafanaNotificationPoliciesLister: grafanaNotificationPolicyInformer.Lister(),
+		grafanaNotificationRoutesLister:   grafanaNotificationRouteInformer.Lister(),
+		grafanaContactPointsLister:        grafanaContactPointInformer.Lister(),
+		grafanaMuteTimingsLister:          grafanaMuteTimingInformer.Lister(),
+		grafanaOrganizationsLister:        grafanaOrganizationInformer.Lister(),
+		grafanaClient:                     grafanaClient,
+		grafanaclientset:                  grafanaclientset,
+		managedIDs:                        newManagedIDs(),
+	}
+
+	controller := NewController(grafanaNotificationPolicyInformer.Informer(),
+		kubeclientset,
+		syncer)
+	controller.watchOrganizations(grafanaOrganizationInformer)
+	syncer.recordWarning = controller.recordWarning
+
+	enqueuePolicies := func() {
+		policies, err := syncer.grafanaNotificationPoliciesLister.List(labels.Everything())
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+
+		for _, policy := range policies {
+			controller.enqueueWorkQueueItem(policy, AddOrUpdate)
+		}
+	}
+
+	// the policy controller updates the status of routes.  only spec changes rebuild the tree
+	controller.watchInformer(grafanaNotificationRouteInformer.Informer(), cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { enqueuePolicies() },
+		UpdateFunc: func(old, new interface{}) {
+			oldRoute, oldOk := old.(*v1alpha1.NotificationRoute)
+			newRoute, newOk := new.(*v1alpha1.NotificationRoute)
+
+			if oldOk && newOk && reflect.DeepEqual(oldRoute.Spec, newRoute.Spec) {
+				return
+			}
+
+			enqueuePolicies()
+		},
+		DeleteFunc: func(obj interface{}) { enqueuePolicies() },
+	})
+
+	// contact points and mute timings are referenced by name once they are created in grafana
+	for _, informer := range []cache.SharedIndexInformer{grafanaContactPointInformer.Informer(), grafanaMuteTimingInformer.Informer()} {
+		controller.watchInformer(informer, cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) { enqueuePolicies() },
+			UpdateFunc: func(old, new interface{}) {
+				if sameResourceVersion(old, new) {
+					return
+				}
+
+				enqueuePolicies()
+			},
+			DeleteFunc: func(obj interface{}) { enqueuePolicies() },
+		})
+	}
+
+	// routes whose organization does not resolve are merged once it is created in grafana
+	controller.watchInformer(grafanaOrganizationInformer.Informer(), cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { enqueuePolicies() },
+		UpdateFunc: func(old, new interface{}) {
+			oldOrganization, oldOk := old.(*v1alpha1.Organization)
+			newOrganization, newOk := new.(*v1alpha1.Organization)
+
+			if oldOk && newOk && oldOrganization.Status == newOrganization.Status {
+				return
+			}
+
+			enqueuePolicies()
+		},
+		DeleteFunc: func(obj interface{}) { enqueuePolicies() },
+	})
+
+	return controller
+}
+
+// sameResourceVersion reports whether an update is a periodic resync of an unchanged object
+func sameResourceVersion(old, new interface{}) bool {
+	oldAccessor, err := meta.Accessor(old)
+	if err != nil {
+		return false
+	}
+
+	newAccessor, err := meta.Accessor(new)
+	if err != nil {
+		return false
+	}
+
+	return oldAccessor.GetResourceVersion() == newAccessor.GetResourceVersion()
+}
+
+func (s *NotificationPolicySyncer) getType() string {
+	return prometheus.TypeNotificationPolicy
+}
+
+// getRuntimeObjectByName ignores namespace.  NotificationPolicies are not namespaced.
+func (s *NotificationPolicySyncer) getRuntimeObjectByName(name string, namespace string) (runtime.Object, error) {
+	return s.grafanaNotificationPoliciesLister.Get(name)
+}
+
+// deleteObjectById restores grafana's default tree.  Policies that never managed the tree, like a
+// second policy for an organization, have no id and leave it alone.  Routes that were merged into
+// a deleted policy are detached.
+func (s *NotificationPolicySyncer) deleteObjectById(ctx context.Context, id string) error {
+	if id == grafana.NO_ID {
+		return nil
+	}
+
+	if err := s.grafanaClient.ResetNotificationPolicy(ctx); err != nil {
+		return err
+	}
+
+	s.managedIDs.remove(grafana.OrgID(ctx), id)
+
+	return s.detachRoutes(func(notificationRoute *v1alpha1.NotificationRoute) bool {
+		_, err := s.grafanaNotificationPoliciesLister.Get(notificationRoute.Status.PolicyName)
+		return errors.IsNotFound(err)
+	})
+}
+
+func (s *NotificationPolicySyncer) updateObject(ctx context.Context, object runtime.Object) error {
+
+	grafanaNotificationPolicy, ok := object.(*v1alpha1.NotificationPolicy)
+	if !ok {
+		return fmt.Errorf("expected notification policy in but got %#v", object)
+	}
+
+	ctx, orgID, _, err := targetOrganization(ctx,
+		s.grafanaOrganizationsLister,
+		grafanaNotificationPolicy.Spec.OrganizationNamespace,
+		grafanaNotificationPolicy.Spec.OrganizationName,
+		grafanaNotificationPolicy.Status.GrafanaOrgID,
+		grafanaNotificationPolicy.Status.GrafanaID,
+		s.deleteObjectById)
+	if err != nil {
+		return err
+	}
+
+	// the error is retried so another policy takes over once the owner is deleted
+	owner, err := s.organizationOwner(orgID)
+	if err != nil {
+		return err
+	}
+
+	if owner != nil && owner.Name != grafanaNotificationPolicy.Name {
+		return fmt.Errorf("the policy tree of the organization is managed by notification policy %s", owner.Name)
+	}
+
+	if grafanaNotificationPolicy.Spec.Route.Receiver == "" {
+		return grafana.Invalidf("the route of notification policy %s has no receiver", grafanaNotificationPolicy.Name)
+	}
+
+	root, err := s.policyRoute(&grafanaNotificationPolicy.Spec.Route, "", orgID)
+	if err != nil {
+		return err
+	}
+
+	notificationRoutes, err := s.organizationRoutes(orgID)
+	if err != nil {
+		return err
+	}
+
+	// routes of NotificationRoutes are matched before the policy's own routes.  a route whose
+	// contact points or mute timings can not be resolved fails the whole policy so a partial tree
+	// is never set.
+	routes := make([]map[string]interface{}, 0, len(notificationRoutes))
+	merged := make(map[string]bool, len(notificationRoutes))
+
+	for _, notificationRoute := range notificationRoutes {
+		route, err := s.policyRoute(&notificationRoute.Spec.Route, notificationRoute.Namespace, orgID)
+		if err != nil {
+			return grafana.Wrapf(err, "notification route %s/%s", notificationRoute.Namespace, notificationRoute.Name)
+		}
+
+		if label := grafanaNotificationPolicy.Spec.NamespaceLabel; label != "" {
+			matchers, _ := route["object_matchers"].([][]string)
+			route["object_matchers"] = append([][]string{{label, "=", notificationRoute.Namespace}}, matchers...)
+		}
+
+		routes = append(routes, route)
+		merged[notificationRoute.Namespace+"/"+notificationRoute.Name] = true
+	}
+
+	if ownRoutes, ok := root["routes"].([]map[string]interface{}); ok {
+		routes = append(routes, ownRoutes...)
+	}
+	root["routes"] = routes
+
+	policyJson, err := json.Marshal(root)
+	if err != nil {
+		return err
+	}
+
+	if err := s.grafanaClient.SetNotificationPolicy(ctx, string(policyJson)); err != nil {
+		return err
+	}
+
+	s.managedIDs.add(orgID, grafana.NotificationPolicyId)
+
+	grafanaNotificationPolicyCopy := grafanaNotificationPolicy.DeepCopy()
+	grafanaNotificationPolicyCopy.Status.GrafanaID = grafana.NotificationPolicyId
+	grafanaNotificationPolicyCopy.Status.GrafanaOrgID = orgID
+
+	_, err = s.grafanaclientset.GrafanaV1alpha1().NotificationPolicies().UpdateStatus(grafanaNotificationPolicyCopy)
+	if err != nil {
+		return err
+	}
+
+	for _, notificationRoute := range notificationRoutes {
+		if notificationRoute.Status.PolicyName == grafanaNotificationPolicy.Name {
+			continue
+		}
+
+		if err := s.setRoutePolicyName(notificationRoute, grafanaNotificationPolicy.Name); err != nil {
+			return err
+		}
+	}
+
+	// routes that moved to another organization are no longer merged into this policy
+	return s.detachRoutes(func(notificationRoute *v1alpha1.NotificationRoute) bool {
+		return notificationRoute.Status.PolicyName == grafanaNotificationPolicy.Name &&
+			!merged[notificationRoute.Namespace+"/"+notificationRoute.Name]
+	})
+}
+
+// detachRoutes clears the policy name of NotificationRoutes that are not merged into their policy
+// anymore.  detached is called with each route that records a policy name.
+func (s *NotificationPolicySyncer) detachRoutes(detached func(notificationRoute *v1alpha1.NotificationRoute) bool) error {
+	notificationRoutes, err := s.grafanaNotificationRoutesLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	for _, notificationRoute := range notificationRoutes {
+		if notificationRoute.Status.PolicyName == "" || !detached(notificationRoute) {
+			continue
+		}
+
+		if err := s.setRoutePolicyName(notificationRoute, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *NotificationPolicySyncer) setRoutePolicyName(notificationRoute *v1alpha1.NotificationRoute, policyName string) error {
+	notificationRouteCopy := notificationRoute.DeepCopy()
+	notificationRouteCopy.Status.PolicyName = policyName
+
+	_, err := s.grafanaclientset.GrafanaV1alpha1().NotificationRoutes(notificationRoute.Namespace).UpdateStatus(notificationRouteCopy)
+
+	return err
+}
+
+// organizationOwner returns the policy that manages the tree of the organization orgID.  It is
+// the oldest policy in the organization.
+func (s *NotificationPolicySyncer) organizationOwner(orgID string) (*v1alpha1.NotificationPolicy, error) {
+	policies, err := s.grafanaNotificationPoliciesLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var owner *v1alpha1.NotificationPolicy
+
+	for _, policy := range policies {
+		policyOrgID, err := resolveOrganization(s.grafanaOrganizationsLister, policy.Spec.OrganizationNamespace, policy.Spec.OrganizationName)
+		if err != nil || policyOrgID != orgID {
+			continue
+		}
+
+		if owner == nil ||
+			policy.CreationTimestamp.Before(&owner.CreationTimestamp) ||
+			(policy.CreationTimestamp.Equal(&owner.CreationTimestamp) && policy.Name < owner.Name) {
+			owner = policy
+		}
+	}
+
+	return owner, nil
+}
+
+// organizationRoutes returns the NotificationRoutes in the organization orgID sorted by namespace
+// and name so the tree does not change between syncs.  Routes whose organization does not resolve
+// are skipped with a warning so they do not hold back the trees of other organizations.
+func (s *NotificationPolicySyncer) organizationRoutes(orgID string) ([]*v1alpha1.NotificationRoute, error) {
+	notificationRoutes, err := s.grafanaNotificationRoutesLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []*v1alpha1.NotificationRoute
+
+	for _, notificationRoute := range notificationRoutes {
+		// the organization of the route may be the organization of the policy once it is synced
+		routeOrgID, err := resolveOrganization(s.grafanaOrganizationsLister, notificationRoute.Namespace, notificationRoute.Spec.OrganizationName)
+		if err != nil {
+			s.recordWarning(notificationRoute, RouteSkipped, fmt.Sprintf("route is not merged into a policy tree: %v", err))
+			continue
+		}
+
+		if routeOrgID == orgID {
+			routes = append(routes, notificationRoute)
+		}
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Namespace != routes[j].Namespace {
+			return routes[i].Namespace < routes[j].Namespace
+		}
+
+		return routes[i].Name < routes[j].Name
+	})
+
+	return routes, nil
+}
+
+// policyRoute converts a route and its children to grafana's model.  ContactPoints and MuteTimings
+// are looked up in namespace and must be in the organization orgID.  A policy has no namespace so
+// its routes can not reference them.
+func (s *NotificationPolicySyncer) policyRoute(route *v1alpha1.PolicyRoute, namespace string, orgID string) (map[string]interface{}, error) {
+	model := make(map[string]interface{})
+
+	receiver := route.Receiver
+	if route.ContactPointName != "" {
+		if namespace == "" {
+			return nil, grafana.Invalidf("contactPointName %s can only be used in notification routes", route.ContactPointName)
+		}
+
+		var err error
+
+		receiver, err = contactPointReceiver(s.grafanaContactPointsLister, namespace, route.ContactPointName, orgID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if receiver != "" {
+		model["receiver"] = receiver
+	}
+
+	matchers := make([][]string, 0, len(route.Matchers))
+
+	for _, matcher := range route.Matchers {
+		parsed, err := parseMatcher(matcher)
+		if err != nil {
+			return nil, err
+		}
+
+		matchers = append(matchers, parsed)
+	}
+
+	if len(matchers) > 0 {
+		model["object_matchers"] = matchers
+	}
+
+	if len(route.GroupBy) > 0 {
+		model["group_by"] = route.GroupBy
+	}
+
+	if route.Continue {
+		model["continue"] = true
+	}
+
+	for field, value := range map[string]string{
+		"group_wait":      route.GroupWait,
+		"group_interval":  route.GroupInterval,
+		"repeat_interval": route.RepeatInterval,
+	} {
+		if value != "" {
+			model[field] = value
+		}
+	}
+
+	muteTimeIntervals := append([]string{}, route.MuteTimeIntervals...)
+
+	for _, name := range route.MuteTimingNames {
+		if namespace == "" {
+			return nil, grafana.Invalidf("muteTimingNames %s can only be used in notification routes", name)
+		}
+
+		muteTiming, err := muteTimingName(s.grafanaMuteTimingsLister, namespace, name, orgID)
+		if err != nil {
+			return nil, err
+		}
+
+		muteTimeIntervals = append(muteTimeIntervals, muteTiming)
+	}
+
+	if len(muteTimeIntervals) > 0 {
+		model["mute_time_intervals"] = muteTimeIntervals
+	}
+
+	if len(route.Routes) > 0 {
+		routes := make([]map[string]interface{}, 0, len(route.Routes))
+
+		for i := range route.Routes {
+			child, err := s.policyRoute(&route.Routes[i], namespace, orgID)
+			if err != nil {
+				return nil, err
+			}
+
+			routes = append(routes, child)
+		}
+
+		model["routes"] = routes
+	}
+
+	return model, nil
+}
+
+// parseMatcher splits a matcher like severity="critical" into grafana's [label, operator, value]
+func parseMatcher(matcher string) ([]string, error) {
+	i := strings.IndexAny(matcher, "=!~")
+	if i <= 0 {
+		return nil, grafana.Invalidf("matcher %q must be a label, an operator and a value", matcher)
+	}
+
+	label := strings.TrimSpace(matcher[:i])
+
+	for _, operator := range matcherOperators {
+		if !strings.HasPrefix(matcher[i:], operator) {
+			continue
+		}
+
+		value := strings.TrimSpace(matcher[i+len(operator):])
+
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, grafana.WrapInvalid(err, "matcher %q", matcher)
+			}
+
+			value = unquoted
+		}
+
+		return []string{label, operator, value}, nil
+	}
+
+	return nil, grafana.Invalidf("matcher %q has an unsupported operator", matcher)
+}
+
+func (s *NotificationPolicySyncer) getAllKubernetesObjectIDs(orgID string) ([]string, error) {
+	notificationPolicies, err := s.grafanaNotificationPoliciesLister.List(labels.Everything())
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+
+	for _, notificationPolicy := range notificationPolicies {
+		// objects without an id may be in any organization
+		if notificationPolicy.Status.GrafanaOrgID != orgID && notificationPolicy.Status.GrafanaID != grafana.NO_ID {
+			continue
+		}
+
+		// policies that do not manage the tree of their organization never get an id
+		if notificationPolicy.Status.GrafanaID == grafana.NO_ID && !s.mayOwnTree(notificationPolicy) {
+			continue
+		}
+
+		if notificationPolicy.Status.GrafanaID != grafana.NO_ID {
+			s.managedIDs.add(orgID, notificationPolicy.Status.GrafanaID)
+		}
+
+		ids = append(ids, notificationPolicy.Status.GrafanaID)
+	}
+
+	return ids, nil
+}
+
+// mayOwnTree returns false if another policy manages the tree of the organization of policy.  A
+// policy whose organization does not resolve yet may still become the owner.
+func (s *NotificationPolicySyncer) mayOwnTree(policy *v1alpha1.NotificationPolicy) bool {
+	orgID, err := resolveOrganization(s.grafanaOrganizationsLister, policy.Spec.OrganizationNamespace, policy.Spec.OrganizationName)
+	if err != nil {
+		return true
+	}
+
+	owner, err := s.organizationOwner(orgID)
+	if err != nil {
+		return true
+	}
+
+	return owner == nil || owner.Name == policy.Name
+}
+
+// getAllGrafanaObjectIDs returns NotificationPolicyId if the syncer set the tree through the
+// provisioning api.  grafana's default tree and trees set in the ui or by other provisioning
+// clients are left alone.
+func (s *NotificationPolicySyncer) getAllGrafanaObjectIDs(ctx context.Context) ([]string, error) {
+	policy, err := s.grafanaClient.GetNotificationPolicy(ctx)
+	if err != nil {
+		// grafana is too old for the provisioning api
+		if grafana.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var model struct {
+		Provenance string `json:"provenance"`
+	}
+
+	if err := json.Unmarshal([]byte(policy.JSON), &model); err != nil {
+		return nil, err
+	}
+
+	if model.Provenance != "api" {
+		return nil, nil
+	}
+
+	return s.managedIDs.filter(grafana.OrgID(ctx), []string{grafana.NotificationPolicyId}), nil
+}
+
+func (s *NotificationPolicySyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
+	var key string
+	var err error
+	var notificationPolicy *v1alpha1.NotificationPolicy
+	var ok bool
+
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	if notificationPolicy, ok = obj.(*v1alpha1.NotificationPolicy); !ok {
+		utilruntime.HandleError(fmt.Errorf("expected notification policy in workqueue but got %#v", obj))
+		return nil
+	}
+
+	item := NewWorkQueueItem(key, notificationPolicy.DeepCopyObject(), notificationPolicy.Status.GrafanaID, notificationPolicy.Status.GrafanaOrgID)
+
+	return &item
+}
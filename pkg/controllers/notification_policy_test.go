@@ -0,0 +1,444 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+)
+
+type postedRoute struct {
+	Receiver          string        `json:"receiver"`
+	ObjectMatchers    [][]string    `json:"object_matchers"`
+	MuteTimeIntervals []string      `json:"mute_time_intervals"`
+	Routes            []postedRoute `json:"routes"`
+}
+
+func newNotificationPolicy(name string, receiver string, created time.Time) *v1alpha1.NotificationPolicy {
+	return &v1alpha1.NotificationPolicy{
+		TypeMeta: metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: v1alpha1.NotificationPolicySpec{
+			Route: v1alpha1.PolicyRoute{Receiver: receiver},
+		},
+	}
+}
+
+func newNotificationRoute(name string, namespace string, route v1alpha1.PolicyRoute) *v1alpha1.NotificationRoute {
+	return &v1alpha1.NotificationRoute{
+		TypeMeta: metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: v1alpha1.NotificationRouteSpec{
+			Route: route,
+		},
+	}
+}
+
+func newNotificationPolicyController(f *fixture) *Controller {
+	return NewNotificationPolicyController(f.client, f.kubeclient, f.grafanaClient,
+		f.informers.Grafana().V1alpha1().NotificationPolicies(),
+		f.informers.Grafana().V1alpha1().NotificationRoutes(),
+		f.informers.Grafana().V1alpha1().ContactPoints(),
+		f.informers.Grafana().V1alpha1().MuteTimings(),
+		f.informers.Grafana().V1alpha1().Organizations())
+}
+
+// createContactPoint creates a contact point in the fake grafana and returns a ContactPoint object
+// in namespace recording its uid
+func (f *fixture) createContactPoint(name string, namespace string) *v1alpha1.ContactPoint {
+	contactPoint := newContactPoint(name, `{"name": "`+name+`", "type": "email"}`)
+	contactPoint.Namespace = namespace
+
+	uid, err := f.grafanaClient.PostContactPoint(context.Background(), contactPoint.Spec.JSON, "")
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	contactPoint.Status.GrafanaID = uid
+
+	return contactPoint
+}
+
+func (f *fixture) getNotificationPolicy(name string) *v1alpha1.NotificationPolicy {
+	notificationPolicy, err := f.client.GrafanaV1alpha1().NotificationPolicies().Get(name, metav1.GetOptions{})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return notificationPolicy
+}
+
+func (f *fixture) getPolicyTree() postedRoute {
+	object, err := f.grafanaClient.GetNotificationPolicy(context.Background())
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	var tree postedRoute
+	if err := json.Unmarshal([]byte(object.JSON), &tree); err != nil {
+		f.t.Fatal(err)
+	}
+
+	return tree
+}
+
+func TestMergesNotificationRoutesIntoPolicy(t *testing.T) {
+	f := newFixture(t)
+
+	f.createContactPoint("email", metav1.NamespaceDefault)
+	teamContactPoint := f.createContactPoint("team-a-pager", "team-a")
+
+	if _, err := f.grafanaClient.PostMuteTiming(context.Background(), `{"name": "nights"}`, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	muteTiming := newMuteTiming("nights", `{}`)
+	muteTiming.Namespace = "team-a"
+	muteTiming.Status.GrafanaID = "nights"
+
+	policy := newNotificationPolicy("alerting", "email", time.Now())
+	policy.Spec.NamespaceLabel = "namespace"
+	policy.Spec.Route.Routes = []v1alpha1.PolicyRoute{
+		{Receiver: "email", Matchers: []string{`severity="critical"`}},
+	}
+
+	teamRoute := newNotificationRoute("api", "team-a", v1alpha1.PolicyRoute{
+		ContactPointName: "team-a-pager",
+		Matchers:         []string{`app=~"api.*"`},
+		MuteTimingNames:  []string{"nights"},
+	})
+
+	f = f.withObjects(teamContactPoint, muteTiming, policy, teamRoute)
+	c := f.newController(newNotificationPolicyController)
+
+	if err := f.sync(c, newItem(policy, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := postedRoute{
+		Receiver: "email",
+		Routes: []postedRoute{
+			{
+				Receiver:          "team-a-pager",
+				ObjectMatchers:    [][]string{{"namespace", "=", "team-a"}, {"app", "=~", "api.*"}},
+				MuteTimeIntervals: []string{"nights"},
+			},
+			{
+				Receiver:       "email",
+				ObjectMatchers: [][]string{{"severity", "=", "critical"}},
+			},
+		},
+	}
+
+	if tree := f.getPolicyTree(); !reflect.DeepEqual(tree, expected) {
+		t.Errorf("expected the team route before the policy's routes\n%+v\ngot\n%+v", expected, tree)
+	}
+
+	updated, err := f.client.GrafanaV1alpha1().NotificationPolicies().Get("alerting", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updated.Status.GrafanaID != grafana.NotificationPolicyId {
+		t.Errorf("expected the policy status to be updated, got %q", updated.Status.GrafanaID)
+	}
+
+	route, err := f.client.GrafanaV1alpha1().NotificationRoutes("team-a").Get("api", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if route.Status.PolicyName != "alerting" {
+		t.Errorf("expected the route to record its policy, got %q", route.Status.PolicyName)
+	}
+}
+
+func TestRouteWithUnsyncedContactPointFailsPolicy(t *testing.T) {
+	f := newFixture(t)
+
+	f.createContactPoint("email", metav1.NamespaceDefault)
+
+	pending := newContactPoint("pager", `{"type": "email"}`)
+	pending.Namespace = "team-a"
+
+	policy := newNotificationPolicy("alerting", "email", time.Now())
+	teamRoute := newNotificationRoute("api", "team-a", v1alpha1.PolicyRoute{ContactPointName: "pager"})
+
+	f = f.withObjects(pending, policy, teamRoute)
+	c := f.newController(newNotificationPolicyController)
+
+	if err := f.sync(c, newItem(policy, AddOrUpdate, "", t)); err == nil {
+		t.Fatal("expected an error so the policy is synced again once the contact point is")
+	}
+
+	if calls := f.grafanaClient.CallsTo("SetNotificationPolicy"); len(calls) != 0 {
+		t.Errorf("expected a partial tree not to be set, got %v", calls)
+	}
+
+	route, err := f.client.GrafanaV1alpha1().NotificationRoutes("team-a").Get("api", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if route.Status.PolicyName != "" {
+		t.Errorf("expected an unresolved route not to record a policy, got %q", route.Status.PolicyName)
+	}
+}
+
+func TestRouteInUnsyncedOrganizationIsSkipped(t *testing.T) {
+	f := newFixture(t)
+
+	f.createContactPoint("email", metav1.NamespaceDefault)
+
+	organization := newOrganization("team-org", `{"name": "team-org"}`)
+	organization.Namespace = "team-a"
+
+	policy := newNotificationPolicy("alerting", "email", time.Now())
+
+	teamRoute := newNotificationRoute("api", "team-a", v1alpha1.PolicyRoute{Receiver: "email"})
+	teamRoute.Spec.OrganizationName = "team-org"
+
+	otherRoute := newNotificationRoute("web", "team-b", v1alpha1.PolicyRoute{Receiver: "email"})
+
+	f = f.withObjects(organization, policy, teamRoute, otherRoute)
+	c := f.newController(newNotificationPolicyController)
+
+	if err := f.sync(c, newItem(policy, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	f.expectEvent("Warning " + RouteSkipped)
+
+	expected := postedRoute{
+		Receiver: "email",
+		Routes:   []postedRoute{{Receiver: "email"}},
+	}
+
+	if tree := f.getPolicyTree(); !reflect.DeepEqual(tree, expected) {
+		t.Errorf("expected only the route of the resolved organization\n%+v\ngot\n%+v", expected, tree)
+	}
+}
+
+func TestDetachedRoutesClearPolicyName(t *testing.T) {
+	f := newFixture(t)
+
+	f.createContactPoint("email", metav1.NamespaceDefault)
+
+	organization := newOrganization("team-org", `{"name": "team-org"}`)
+	organization.Namespace = "team-a"
+	organization.Status.GrafanaID = "2"
+
+	policy := newNotificationPolicy("alerting", "email", time.Now())
+
+	// the route moved to another organization since it was merged
+	moved := newNotificationRoute("moved", "team-a", v1alpha1.PolicyRoute{Receiver: "email"})
+	moved.Spec.OrganizationName = "team-org"
+	moved.Status.PolicyName = "alerting"
+
+	merged := newNotificationRoute("merged", "team-a", v1alpha1.PolicyRoute{Receiver: "email"})
+	merged.Status.PolicyName = "alerting"
+
+	f = f.withObjects(organization, policy, moved, merged)
+	c := f.newController(newNotificationPolicyController)
+
+	if err := f.sync(c, newItem(policy, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	getPolicyName := func(name string) string {
+		route, err := f.client.GrafanaV1alpha1().NotificationRoutes("team-a").Get(name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return route.Status.PolicyName
+	}
+
+	if policyName := getPolicyName("moved"); policyName != "" {
+		t.Errorf("expected the moved route to be detached, got %q", policyName)
+	}
+
+	if policyName := getPolicyName("merged"); policyName != "alerting" {
+		t.Errorf("expected the merged route to keep its policy, got %q", policyName)
+	}
+
+	// deleting the policy detaches every route merged into it
+	if err := f.informers.Grafana().V1alpha1().NotificationPolicies().Informer().GetIndexer().Delete(policy); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.sync(c, newItem(policy, Delete, grafana.NotificationPolicyId, t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if policyName := getPolicyName("merged"); policyName != "" {
+		t.Errorf("expected the route of a deleted policy to be detached, got %q", policyName)
+	}
+}
+
+func TestSecondNotificationPolicyIsRejected(t *testing.T) {
+	f := newFixture(t)
+
+	f.createContactPoint("email", metav1.NamespaceDefault)
+
+	first := newNotificationPolicy("first", "email", time.Now().Add(-time.Hour))
+	second := newNotificationPolicy("second", "email", time.Now())
+
+	f = f.withObjects(first, second)
+	c := f.newController(newNotificationPolicyController)
+
+	if err := f.sync(c, newItem(second, AddOrUpdate, "", t)); err == nil {
+		t.Fatal("expected an error for a second policy in the organization")
+	}
+
+	if calls := f.grafanaClient.CallsTo("SetNotificationPolicy"); len(calls) != 0 {
+		t.Errorf("expected nothing to be set, got %v", calls)
+	}
+
+	// deleting the second policy must not reset the tree managed by the first
+	if err := f.sync(c, newItem(second, Delete, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := f.grafanaClient.CallsTo("ResetNotificationPolicy"); len(calls) != 0 {
+		t.Errorf("expected the tree not to be reset, got %v", calls)
+	}
+}
+
+func TestDeletedNotificationPolicyResetsTree(t *testing.T) {
+	f := newFixture(t)
+
+	f.createContactPoint("email", metav1.NamespaceDefault)
+
+	if err := f.grafanaClient.SetNotificationPolicy(context.Background(), `{"receiver": "email"}`); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := newNotificationPolicy("alerting", "email", time.Now())
+	c := f.newController(newNotificationPolicyController)
+
+	if err := f.sync(c, newItem(policy, Delete, grafana.NotificationPolicyId, t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if tree := f.getPolicyTree(); tree.Receiver != "grafana-default-email" {
+		t.Errorf("expected grafana's default tree, got %+v", tree)
+	}
+}
+
+func TestResyncIgnoresPoliciesThatDoNotManageTheTree(t *testing.T) {
+	f := newFixture(t)
+
+	f.createContactPoint("email", metav1.NamespaceDefault)
+
+	first := newNotificationPolicy("first", "email", time.Now().Add(-time.Hour))
+	second := newNotificationPolicy("second", "email", time.Now())
+
+	f = f.withObjects(first, second)
+	c := f.newController(newNotificationPolicyController)
+
+	if err := f.sync(c, newItem(first, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	f.index(f.getNotificationPolicy("first"))
+
+	if err := c.resyncDeletedObjects(context.Background()); err != nil {
+		t.Fatalf("expected the policy without a tree not to block garbage collection: %v", err)
+	}
+
+	if calls := f.grafanaClient.CallsTo("ResetNotificationPolicy"); len(calls) != 0 {
+		t.Errorf("expected the tree not to be reset, got %v", calls)
+	}
+}
+
+func TestResyncOnlyResetsManagedTree(t *testing.T) {
+	f := newFixture(t)
+
+	f.createContactPoint("email", metav1.NamespaceDefault)
+
+	// e.g. set by terraform, which uses the same provisioning api
+	if err := f.grafanaClient.SetNotificationPolicy(context.Background(), `{"receiver": "email"}`); err != nil {
+		t.Fatal(err)
+	}
+
+	c := f.newController(newNotificationPolicyController)
+
+	if err := c.resyncDeletedObjects(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := f.grafanaClient.CallsTo("ResetNotificationPolicy"); len(calls) != 0 {
+		t.Errorf("expected a tree set by another client to be kept, got %v", calls)
+	}
+
+	policy := newNotificationPolicy("alerting", "email", time.Now())
+
+	f = f.withObjects(policy)
+	c = f.newController(newNotificationPolicyController)
+
+	if err := f.sync(c, newItem(policy, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the policy is deleted without its delete being handled
+	if err := f.informers.Grafana().V1alpha1().NotificationPolicies().Informer().GetIndexer().Delete(policy); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.resyncDeletedObjects(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := f.grafanaClient.CallsTo("ResetNotificationPolicy"); len(calls) != 1 {
+		t.Errorf("expected the managed tree to be reset, got %v", calls)
+	}
+}
+
+func TestParseMatcher(t *testing.T) {
+	tests := []struct {
+		matcher  string
+		expected []string
+	}{
+		{`severity=critical`, []string{"severity", "=", "critical"}},
+		{`severity = "critical"`, []string{"severity", "=", "critical"}},
+		{`team!=ops`, []string{"team", "!=", "ops"}},
+		{`app=~"api.*"`, []string{"app", "=~", "api.*"}},
+		{`app!~db`, []string{"app", "!~", "db"}},
+		{`=critical`, nil},
+		{`severity`, nil},
+		{`severity~critical`, nil},
+	}
+
+	for _, tt := range tests {
+		actual, err := parseMatcher(tt.matcher)
+
+		if tt.expected == nil {
+			if err == nil {
+				t.Errorf("expected %q to be rejected, got %q", tt.matcher, actual)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", tt.matcher, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("expected %q for %q, got %q", tt.expected, tt.matcher, actual)
+		}
+	}
+}
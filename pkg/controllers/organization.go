@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	clientset "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned"
+	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions/grafana/v1alpha1"
+	listers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/listers/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+// OrganizationSyncer is the controller implementation for Organization resources
+type OrganizationSyncer struct {
+	grafanaOrganizationsLister listers.OrganizationLister
+	grafanaClient              grafana.Interface
+	grafanaclientset           clientset.Interface
+
+	// organizations are not marked in grafana.  only recorded ones are garbage collected
+	managedIDs *managedIDs
+}
+
+// NewOrganizationController returns a new grafana Organization controller
+func NewOrganizationController(
+	grafanaclientset clientset.Interface,
+	kubeclientset kubernetes.Interface,
+	grafanaClient grafana.Interface,
+	grafanaOrganizationInformer informers.OrganizationInformer) *Controller {
+
+	syncer := &OrganizationSyncer{
+		grafanaOrganizationsLister: grafanaOrganizationInformer.Lister(),
+		grafanaClient:              grafanaClient,
+		grafanaclientset:           grafanaclientset,
+		managedIDs:                 newManagedIDs(),
+	}
+
+	controller := NewController(grafanaOrganizationInformer.Informer(),
+		kubeclientset,
+		syncer)
+
+	return controller
+}
+
+func (s *OrganizationSyncer) getType() string {
+	return prometheus.TypeOrganization
+}
+
+func (s *OrganizationSyncer) getRuntimeObjectByName(name string, namespace string) (runtime.Object, error) {
+	return s.grafanaOrganizationsLister.Organizations(namespace).Get(name)
+}
+
+func (s *OrganizationSyncer) deleteObjectById(ctx context.Context, id string) error {
+	if err := s.grafanaClient.DeleteOrganization(ctx, id); err != nil {
+		return err
+	}
+
+	s.managedIDs.remove("", id)
+	return nil
+}
+
+func (s *OrganizationSyncer) updateObject(ctx context.Context, object runtime.Object) error {
+
+	grafanaOrganization, ok := object.(*v1alpha1.Organization)
+	if !ok {
+		return fmt.Errorf("expected organization in but got %#v", object)
+	}
+
+	id, err := s.grafanaClient.PostOrganization(ctx, grafanaOrganization.Spec.JSON, grafanaOrganization.Status.GrafanaID)
+
+	if err != nil {
+		return err
+	}
+
+	s.managedIDs.add("", id)
+
+	grafanaOrganizationCopy := grafanaOrganization.DeepCopy()
+	grafanaOrganizationCopy.Status.GrafanaID = id
+
+	_, err = s.grafanaclientset.GrafanaV1alpha1().Organizations(grafanaOrganization.Namespace).UpdateStatus(grafanaOrganizationCopy)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// getAllKubernetesObjectIDs ignores orgID.  Organizations are not scoped to an organization.
+func (s *OrganizationSyncer) getAllKubernetesObjectIDs(orgID string) ([]string, error) {
+	organizations, err := s.grafanaOrganizationsLister.List(labels.Everything())
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+
+	for _, organization := range organizations {
+		if organization.Status.GrafanaID != grafana.NO_ID {
+			s.managedIDs.add("", organization.Status.GrafanaID)
+		}
+
+		ids = append(ids, organization.Status.GrafanaID)
+	}
+
+	return ids, nil
+}
+
+// getAllGrafanaObjectIDs returns the organizations the syncer created or found in the status of
+// an Organization.  Organizations created by hand are never deleted.
+func (s *OrganizationSyncer) getAllGrafanaObjectIDs(ctx context.Context) ([]string, error) {
+	ids, err := s.grafanaClient.GetAllOrganizationIds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.managedIDs.filter("", ids), nil
+}
+
+func (s *OrganizationSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
+	var key string
+	var err error
+	var organization *v1alpha1.Organization
+	var ok bool
+
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	if organization, ok = obj.(*v1alpha1.Organization); !ok {
+		utilruntime.HandleError(fmt.Errorf("expected organization in workqueue but got %#v", obj))
+		return nil
+	}
+
+	item := NewWorkQueueItem(key, organization.DeepCopyObject(), organization.Status.GrafanaID, "")
+
+	return &item
+}
+
+// resolveOrganization returns the grafana id of the Organization named organizationName in
+// namespace.  An empty name is the default organization and resolves to "".
+func resolveOrganization(lister listers.OrganizationLister, namespace string, organizationName string) (string, error) {
+	if organizationName == "" {
+		return "", nil
+	}
+
+	organization, err := lister.Organizations(namespace).Get(organizationName)
+	if err != nil {
+		return "", err
+	}
+
+	if organization.Status.GrafanaID == grafana.NO_ID {
+		return "", fmt.Errorf("organization %s has not been created in grafana yet", organizationName)
+	}
+
+	return organization.Status.GrafanaID, nil
+}
+
+// targetOrganization returns a context acting on the organization an object references, the
+// organization's id and the id the object has in it.  An object that moved to a different
+// organization is deleted from the old one and NO_ID is returned so it is created again.
+func targetOrganization(ctx context.Context,
+	lister listers.OrganizationLister,
+	namespace string,
+	organizationName string,
+	currentOrgID string,
+	currentID string,
+	deleteObjectById func(context.Context, string) error) (context.Context, string, string, error) {
+
+	orgID, err := resolveOrganization(lister, namespace, organizationName)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if currentID != grafana.NO_ID && currentOrgID != orgID {
+		if err := deleteObjectById(grafana.WithOrgID(ctx, currentOrgID), currentID); err != nil {
+			return nil, "", "", err
+		}
+
+		currentID = grafana.NO_ID
+	}
+
+	return grafana.WithOrgID(ctx, orgID), orgID, currentID, nil
+}
@@ -0,0 +1,224 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+)
+
+func newOrganization(name string, organizationJson string) *v1alpha1.Organization {
+	return &v1alpha1.Organization{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: newObjectMeta(name),
+		Spec: v1alpha1.OrganizationSpec{
+			JSON: organizationJson,
+		},
+	}
+}
+
+func newOrganizationController(f *fixture) *Controller {
+	return NewOrganizationController(f.client, f.kubeclient, f.grafanaClient,
+		f.informers.Grafana().V1alpha1().Organizations())
+}
+
+func (f *fixture) getOrganization(name string) *v1alpha1.Organization {
+	organization, err := f.client.GrafanaV1alpha1().Organizations(metav1.NamespaceDefault).Get(name, metav1.GetOptions{})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return organization
+}
+
+func TestCreatesOrganization(t *testing.T) {
+	organization := newOrganization("test", `{"name": "test"}`)
+
+	f := newFixture(t, organization)
+	c := f.newController(newOrganizationController)
+
+	if err := f.sync(c, newItem(organization, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	id := f.getOrganization("test").Status.GrafanaID
+	if id == "" {
+		t.Fatal("expected the organization status to be updated with its grafana id")
+	}
+
+	if _, err := f.grafanaClient.GetOrganization(context.Background(), id); err != nil {
+		t.Errorf("expected organization in grafana: %v", err)
+	}
+}
+
+func TestCreatesDashboardInOrganization(t *testing.T) {
+	f := newFixture(t)
+
+	orgID, err := f.grafanaClient.PostOrganization(context.Background(), `{"name": "test"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	organization := newOrganization("org", `{"name": "test"}`)
+	organization.Status.GrafanaID = orgID
+
+	dashboard := newDashboard("test", `{"title": "test"}`, "")
+	dashboard.Spec.OrganizationName = "org"
+
+	f = f.withObjects(organization, dashboard)
+	c := f.newController(newDashboardController)
+
+	if err := f.sync(c, newItem(dashboard, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	status := f.getDashboard("test").Status
+	if status.GrafanaOrgID != orgID {
+		t.Fatalf("expected dashboard status to record organization %s, got %#v", orgID, status)
+	}
+
+	if _, err := f.grafanaClient.GetDashboard(grafana.WithOrgID(context.Background(), orgID), status.GrafanaID); err != nil {
+		t.Errorf("expected dashboard in organization %s: %v", orgID, err)
+	}
+
+	if _, err := f.grafanaClient.GetDashboard(context.Background(), status.GrafanaID); err == nil {
+		t.Error("expected dashboard to not be in the default organization")
+	}
+}
+
+func TestDashboardWaitsForOrganization(t *testing.T) {
+	organization := newOrganization("org", `{"name": "test"}`)
+
+	dashboard := newDashboard("test", `{"title": "test"}`, "")
+	dashboard.Spec.OrganizationName = "org"
+
+	f := newFixture(t, organization, dashboard)
+	c := f.newController(newDashboardController)
+
+	if err := f.sync(c, newItem(dashboard, AddOrUpdate, "", t)); err == nil {
+		t.Fatal("expected an error syncing a dashboard before its organization is created")
+	}
+
+	if calls := f.grafanaClient.CallsTo("PostDashboard"); len(calls) != 0 {
+		t.Errorf("expected no dashboard to be posted, got %v", calls)
+	}
+}
+
+func TestMovesDashboardBetweenOrganizations(t *testing.T) {
+	f := newFixture(t)
+
+	orgID, err := f.grafanaClient.PostOrganization(context.Background(), `{"name": "test"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := f.grafanaClient.PostDashboard(context.Background(), `{"title": "test"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	organization := newOrganization("org", `{"name": "test"}`)
+	organization.Status.GrafanaID = orgID
+
+	dashboard := newDashboard("test", `{"title": "test"}`, "")
+	dashboard.Spec.OrganizationName = "org"
+	dashboard.Status.GrafanaID = uid
+
+	f = f.withObjects(organization, dashboard)
+	c := f.newController(newDashboardController)
+
+	if err := f.sync(c, newItem(dashboard, AddOrUpdate, uid, t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.grafanaClient.GetDashboard(context.Background(), uid); err == nil {
+		t.Error("expected dashboard to be deleted from the default organization")
+	}
+
+	status := f.getDashboard("test").Status
+	if _, err := f.grafanaClient.GetDashboard(grafana.WithOrgID(context.Background(), orgID), status.GrafanaID); err != nil {
+		t.Errorf("expected dashboard in organization %s: %v", orgID, err)
+	}
+}
+
+func TestResyncDeletesObjectsInEveryOrganization(t *testing.T) {
+	f := newFixture(t)
+
+	orgID, err := f.grafanaClient.PostOrganization(context.Background(), `{"name": "test"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orgCtx := grafana.WithOrgID(context.Background(), orgID)
+
+	kept, err := f.grafanaClient.PostDashboard(orgCtx, `{"title": "kept"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.grafanaClient.PostDashboard(orgCtx, `{"title": "orphan"}`, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	organization := newOrganization("org", `{"name": "test"}`)
+	organization.Status.GrafanaID = orgID
+
+	dashboard := newDashboard("kept", `{"title": "kept"}`, "")
+	dashboard.Spec.OrganizationName = "org"
+	dashboard.Status.GrafanaID = kept
+	dashboard.Status.GrafanaOrgID = orgID
+
+	f = f.withObjects(organization, dashboard)
+	c := f.newController(newDashboardController)
+
+	if err := c.resyncDeletedObjects(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := f.grafanaClient.GetAllDashboardIds(orgCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 1 || ids[0] != kept {
+		t.Errorf("expected only %s to remain in organization %s, got %v", kept, orgID, ids)
+	}
+}
+
+func TestResyncOnlyDeletesManagedOrganizations(t *testing.T) {
+	organization := newOrganization("managed", `{"name": "managed"}`)
+
+	f := newFixture(t, organization)
+	c := f.newController(newOrganizationController)
+
+	if err := f.sync(c, newItem(organization, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	managedID := f.getOrganization("managed").Status.GrafanaID
+
+	unmanagedID, err := f.grafanaClient.PostOrganization(context.Background(), `{"name": "created by hand"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the organization is deleted without its delete being handled
+	if err := f.informers.Grafana().V1alpha1().Organizations().Informer().GetIndexer().Delete(organization); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.resyncDeletedObjects(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.grafanaClient.GetOrganization(context.Background(), managedID); err == nil {
+		t.Errorf("expected managed organization %s to be deleted", managedID)
+	}
+
+	if _, err := f.grafanaClient.GetOrganization(context.Background(), unmanagedID); err != nil {
+		t.Errorf("expected organization %s created by hand to be kept: %v", unmanagedID, err)
+	}
+}
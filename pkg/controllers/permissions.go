@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	listers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/listers/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+)
+
+// reconcilePermissions reconciles the ACL of a folder or dashboard with permissions and derived,
+// permissions derived from elsewhere.  If replace is set they replace the whole ACL.  Otherwise
+// they are granted, the entries in granted, which an earlier reconcile granted, that are no
+// longer listed are revoked and the remaining entries are kept.  The ACL is read with get and only
+// written with set when it changes.  The entries granted now are returned so they can be recorded
+// for the next reconcile.
+func reconcilePermissions(ctx context.Context,
+	grafanaClient grafana.Interface,
+	lister listers.TeamLister,
+	namespace string,
+	orgID string,
+	permissions []v1alpha1.Permission,
+	replace bool,
+	derived []grafana.Permission,
+	granted []v1alpha1.GrantedPermission,
+	get func() ([]grafana.Permission, error),
+	set func([]grafana.Permission) error) ([]v1alpha1.GrantedPermission, error) {
+
+	// nothing to grant or revoke
+	if !replace && len(permissions) == 0 && len(derived) == 0 && len(granted) == 0 {
+		return nil, nil
+	}
+
+	desired, err := resolvePermissions(ctx, grafanaClient, lister, namespace, orgID, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	desired = highestPermissions(append(desired, derived...))
+
+	current, err := get()
+	if err != nil {
+		return nil, err
+	}
+
+	acl := desired
+	if !replace {
+		acl = mergePermissions(current, desired, granted)
+	}
+
+	if !samePermissions(current, acl) {
+		if err := set(acl); err != nil {
+			return nil, err
+		}
+	}
+
+	return grantedPermissions(desired), nil
+}
+
+// grantedInOrganization returns the entries granted in the organization grantedOrgID if the
+// object is still in the organization orgID.  Team and user ids of another organization mean
+// nothing in this one.
+func grantedInOrganization(granted []v1alpha1.GrantedPermission, grantedOrgID string, orgID string) []v1alpha1.GrantedPermission {
+	if grantedOrgID != orgID {
+		return nil
+	}
+
+	return granted
+}
+
+// mergePermissions adds desired to the entries of current that are neither granted to a role,
+// team or user in desired nor in granted
+func mergePermissions(current []grafana.Permission, desired []grafana.Permission, granted []v1alpha1.GrantedPermission) []grafana.Permission {
+	listed := make(map[v1alpha1.GrantedPermission]bool, len(desired)+len(granted))
+	for _, permission := range desired {
+		listed[grantedPermission(permission)] = true
+	}
+
+	for _, permission := range granted {
+		listed[permission] = true
+	}
+
+	merged := make([]grafana.Permission, 0, len(current)+len(desired))
+
+	for _, permission := range current {
+		if !listed[grantedPermission(permission)] {
+			merged = append(merged, permission)
+		}
+	}
+
+	return append(merged, desired...)
+}
+
+// grantedPermission returns the role, team or user permission is granted to
+func grantedPermission(permission grafana.Permission) v1alpha1.GrantedPermission {
+	return v1alpha1.GrantedPermission{
+		Role:   permission.Role,
+		TeamID: permission.TeamID,
+		UserID: permission.UserID,
+	}
+}
+
+// grantedPermissions returns the roles, teams and users permissions are granted to
+func grantedPermissions(permissions []grafana.Permission) []v1alpha1.GrantedPermission {
+	var granted []v1alpha1.GrantedPermission
+
+	for _, permission := range permissions {
+		granted = append(granted, grantedPermission(permission))
+	}
+
+	return granted
+}
+
+// highestPermissions keeps the highest permission of each role, team and user.  Grafana rejects
+// ACLs listing any of them twice.
+func highestPermissions(permissions []grafana.Permission) []grafana.Permission {
+	levels := make(map[grafana.Permission]int)
+	var order []grafana.Permission
+
+	for _, permission := range permissions {
+		level := permission.Permission
+		permission.Permission = 0
+
+		current, ok := levels[permission]
+		if !ok {
+			order = append(order, permission)
+		}
+
+		if !ok || level > current {
+			levels[permission] = level
+		}
+	}
+
+	highest := make([]grafana.Permission, 0, len(order))
+	for _, permission := range order {
+		permission.Permission = levels[permission]
+		highest = append(highest, permission)
+	}
+
+	return highest
+}
+
+// samePermissions reports whether a and b hold the same entries in any order
+func samePermissions(a []grafana.Permission, b []grafana.Permission) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	a = append([]grafana.Permission(nil), a...)
+	b = append([]grafana.Permission(nil), b...)
+
+	grafana.SortPermissions(a)
+	grafana.SortPermissions(b)
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// resolvePermissions converts the permissions of a spec to grafana permissions.  Users are looked
+// up in the organization ctx acts on.
+func resolvePermissions(ctx context.Context,
+	grafanaClient grafana.Interface,
+	lister listers.TeamLister,
+	namespace string,
+	orgID string,
+	permissions []v1alpha1.Permission) ([]grafana.Permission, error) {
+
+	var userIds map[string]string
+	resolved := make([]grafana.Permission, 0, len(permissions))
+
+	for _, permission := range permissions {
+		level, err := grafana.ParsePermission(permission.Permission)
+		if err != nil {
+			return nil, err
+		}
+
+		grafanaPermission := grafana.Permission{
+			Permission: level,
+		}
+
+		switch {
+		case permission.Role != "" && permission.TeamName == "" && permission.User == "":
+			if permission.Role != "Viewer" && permission.Role != "Editor" {
+				return nil, grafana.Invalidf("unknown role %q.  expected Viewer or Editor", permission.Role)
+			}
+
+			grafanaPermission.Role = permission.Role
+		case permission.TeamName != "" && permission.Role == "" && permission.User == "":
+			grafanaPermission.TeamID, err = resolveTeam(lister, namespace, orgID, permission.TeamName)
+			if err != nil {
+				return nil, err
+			}
+		case permission.User != "" && permission.Role == "" && permission.TeamName == "":
+			// users are only looked up if needed and then only once
+			if userIds == nil {
+				userIds, err = grafanaClient.GetOrgUserIds(ctx)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			userID, ok := userIds[strings.ToLower(permission.User)]
+			if !ok {
+				return nil, fmt.Errorf("grafana user %s not found", permission.User)
+			}
+
+			grafanaPermission.UserID = userID
+		default:
+			return nil, grafana.Invalidf("permission %+v must set exactly one of role, teamName and user", permission)
+		}
+
+		resolved = append(resolved, grafanaPermission)
+	}
+
+	return resolved, nil
+}
+
+// resolveTeam returns the grafana id of the Team named teamName in namespace.  The team must be
+// in the organization orgID.
+func resolveTeam(lister listers.TeamLister, namespace string, orgID string, teamName string) (string, error) {
+	team, err := lister.Teams(namespace).Get(teamName)
+	if err != nil {
+		return "", err
+	}
+
+	if team.Status.GrafanaID == grafana.NO_ID {
+		return "", fmt.Errorf("team %s has not been created in grafana yet", teamName)
+	}
+
+	if team.Status.GrafanaOrgID != orgID {
+		return "", fmt.Errorf("team %s is not in the same organization", teamName)
+	}
+
+	return team.Status.GrafanaID, nil
+}
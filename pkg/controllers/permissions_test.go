@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+)
+
+func TestFolderPermissionsReplaceACL(t *testing.T) {
+	f := newFixture(t)
+
+	team := f.createTeam("editors")
+	aliceID := f.grafanaClient.AddUser(context.Background(), "alice", "alice@example.com")
+
+	uid, _, err := f.grafanaClient.PostFolder(context.Background(), `{"title": "test"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = f.grafanaClient.SetFolderPermissions(context.Background(), uid, []grafana.Permission{
+		{Role: "Editor", Permission: grafana.PermissionEdit},
+		{TeamID: "1000", Permission: grafana.PermissionView},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	folder := newFolder("test", `{"title": "test"}`)
+	folder.Status.GrafanaID = uid
+	folder.Spec.Permissions = []v1alpha1.Permission{
+		{Role: "Viewer", Permission: "View"},
+		{User: "Alice@example.com", Permission: "Admin"},
+		{TeamName: "editors", Permission: "Edit"},
+	}
+	folder.Spec.ReplacePermissions = true
+
+	f = f.withObjects(team, folder)
+	c := f.newController(newFolderController)
+
+	if err := f.sync(c, newItem(folder, AddOrUpdate, uid, t)); err != nil {
+		t.Fatal(err)
+	}
+
+	permissions, err := f.grafanaClient.GetFolderPermissions(context.Background(), uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []grafana.Permission{
+		{Role: "Viewer", Permission: grafana.PermissionView},
+		{UserID: aliceID, Permission: grafana.PermissionAdmin},
+		{TeamID: team.Status.GrafanaID, Permission: grafana.PermissionEdit},
+	}
+
+	if !samePermissions(permissions, expected) {
+		t.Errorf("expected permissions %v, got %v", expected, permissions)
+	}
+
+	// a second sync finds nothing to change
+	if err := f.sync(c, newItem(folder, AddOrUpdate, uid, t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := f.grafanaClient.CallsTo("SetFolderPermissions"); len(calls) != 2 {
+		t.Errorf("expected permissions to be set once by the controller, got %v", calls)
+	}
+}
+
+func TestFolderReplacePermissionsClearACL(t *testing.T) {
+	folder := newFolder("test", `{"title": "test"}`)
+	folder.Spec.ReplacePermissions = true
+
+	f := newFixture(t, folder)
+	c := f.newController(newFolderController)
+
+	if err := f.sync(c, newItem(folder, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	permissions, err := f.grafanaClient.GetFolderPermissions(context.Background(), f.getFolder("test").Status.GrafanaID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(permissions) != 0 {
+		t.Errorf("expected an empty acl, got %v", permissions)
+	}
+}
+
+func TestFolderEmptyPermissionsKeepACL(t *testing.T) {
+	folder := newFolder("test", `{"title": "test"}`)
+	folder.Spec.Permissions = []v1alpha1.Permission{}
+
+	f := newFixture(t, folder)
+	c := f.newController(newFolderController)
+
+	if err := f.sync(c, newItem(folder, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := f.grafanaClient.CallsTo("SetFolderPermissions"); len(calls) != 0 {
+		t.Errorf("expected an empty list to leave the acl alone, got %v", calls)
+	}
+}
+
+func TestFolderPermissionsMergeIntoACL(t *testing.T) {
+	f := newFixture(t)
+
+	aliceID := f.grafanaClient.AddUser(context.Background(), "alice", "alice@example.com")
+
+	uid, _, err := f.grafanaClient.PostFolder(context.Background(), `{"title": "test"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	folder := newFolder("test", `{"title": "test"}`)
+	folder.Status.GrafanaID = uid
+	folder.Spec.Permissions = []v1alpha1.Permission{
+		{Role: "Viewer", Permission: "Edit"},
+		{User: "alice", Permission: "Admin"},
+	}
+
+	f = f.withObjects(folder)
+	c := f.newController(newFolderController)
+
+	if err := f.sync(c, newItem(folder, AddOrUpdate, uid, t)); err != nil {
+		t.Fatal(err)
+	}
+
+	permissions, err := f.grafanaClient.GetFolderPermissions(context.Background(), uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// listed entries replace the default Viewer permission and the Editor permission is kept
+	expected := []grafana.Permission{
+		{Role: "Viewer", Permission: grafana.PermissionEdit},
+		{Role: "Editor", Permission: grafana.PermissionEdit},
+		{UserID: aliceID, Permission: grafana.PermissionAdmin},
+	}
+
+	if !samePermissions(permissions, expected) {
+		t.Errorf("expected permissions %v, got %v", expected, permissions)
+	}
+}
+
+func TestInvalidPermissions(t *testing.T) {
+	tests := []v1alpha1.Permission{
+		{Role: "Viewer", User: "alice", Permission: "View"},
+		{Permission: "View"},
+		{Role: "Admin", Permission: "View"},
+		{Role: "Viewer", Permission: "Owner"},
+		{User: "unknown", Permission: "View"},
+	}
+
+	for _, test := range tests {
+		dashboard := newDashboard("test", `{"title": "test"}`, "")
+		dashboard.Spec.Permissions = []v1alpha1.Permission{test}
+
+		f := newFixture(t, dashboard)
+		c := f.newController(newDashboardController)
+
+		if err := f.sync(c, newItem(dashboard, AddOrUpdate, "", t)); err == nil {
+			t.Errorf("%+v: expected an error", test)
+		}
+
+		if calls := f.grafanaClient.CallsTo("SetDashboardPermissions"); len(calls) != 0 {
+			t.Errorf("%+v: expected no permissions to be set, got %v", test, calls)
+		}
+	}
+}
+
+func TestFolderRevokesPermissionsRemovedFromSpec(t *testing.T) {
+	folder := newFolder("test", `{"title": "test"}`)
+	folder.Spec.Permissions = []v1alpha1.Permission{
+		{Role: "Viewer", Permission: "Edit"},
+		{User: "alice", Permission: "Admin"},
+	}
+
+	f := newFixture(t, folder)
+	aliceID := f.grafanaClient.AddUser(context.Background(), "alice", "alice@example.com")
+	c := f.newController(newFolderController)
+
+	if err := f.sync(c, newItem(folder, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	synced := f.getFolder("test")
+
+	expectedGranted := []v1alpha1.GrantedPermission{{Role: "Viewer"}, {UserID: aliceID}}
+	if !reflect.DeepEqual(synced.Status.GrantedPermissions, expectedGranted) {
+		t.Errorf("expected granted permissions %v, got %v", expectedGranted, synced.Status.GrantedPermissions)
+	}
+
+	// alice is removed from the spec
+	synced.Spec.Permissions = synced.Spec.Permissions[:1]
+	f.index(synced)
+
+	if err := f.sync(c, newItem(synced, AddOrUpdate, synced.Status.GrafanaID, t)); err != nil {
+		t.Fatal(err)
+	}
+
+	permissions, err := f.grafanaClient.GetFolderPermissions(context.Background(), synced.Status.GrafanaID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the default Editor permission was never granted by the controller so it is kept
+	expected := []grafana.Permission{
+		{Role: "Viewer", Permission: grafana.PermissionEdit},
+		{Role: "Editor", Permission: grafana.PermissionEdit},
+	}
+
+	if !samePermissions(permissions, expected) {
+		t.Errorf("expected permissions %v, got %v", expected, permissions)
+	}
+}
+
+func TestDashboardKeepsTeamGrantsOfOtherObjects(t *testing.T) {
+	f := newFixture(t)
+
+	team := f.createTeam("editors")
+
+	id, err := f.grafanaClient.PostDashboard(context.Background(), `{"title": "test"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// e.g. granted by a dashboard in another namespace before this one was created
+	err = f.grafanaClient.SetDashboardPermissions(context.Background(), id, []grafana.Permission{
+		{TeamID: team.Status.GrafanaID, Permission: grafana.PermissionEdit},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dashboard := newDashboard("test", `{"title": "test"}`, "")
+	dashboard.Status.GrafanaID = id
+	dashboard.Spec.Permissions = []v1alpha1.Permission{
+		{Role: "Viewer", Permission: "View"},
+	}
+
+	f = f.withObjects(team, dashboard)
+	c := f.newController(newDashboardController)
+
+	if err := f.sync(c, newItem(dashboard, AddOrUpdate, id, t)); err != nil {
+		t.Fatal(err)
+	}
+
+	permissions, err := f.grafanaClient.GetDashboardPermissions(context.Background(), f.getDashboard("test").Status.GrafanaID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []grafana.Permission{
+		{TeamID: team.Status.GrafanaID, Permission: grafana.PermissionEdit},
+		{Role: "Viewer", Permission: grafana.PermissionView},
+	}
+
+	if !samePermissions(permissions, expected) {
+		t.Errorf("expected permissions %v, got %v", expected, permissions)
+	}
+}
@@ -0,0 +1,304 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	clientset "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned"
+	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions/grafana/v1alpha1"
+	listers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/listers/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+// defaultPlaylistInterval is how long grafana shows each dashboard of a playlist by default
+const defaultPlaylistInterval = "5m"
+
+// PlaylistSyncer is the controller implementation for Playlist resources
+type PlaylistSyncer struct {
+	grafanaPlaylistsLister     listers.PlaylistLister
+	grafanaDashboardsLister    listers.DashboardLister
+	grafanaOrganizationsLister listers.OrganizationLister
+	grafanaClient              grafana.Interface
+	grafanaclientset           clientset.Interface
+
+	// playlists are not marked in grafana.  only recorded ones are garbage collected
+	managedIDs *managedIDs
+}
+
+// NewPlaylistController returns a new grafana Playlist controller.  Playlists are synced again
+// whenever a dashboard in their namespace is created in grafana, changes its tags or is deleted.
+func NewPlaylistController(
+	grafanaclientset clientset.Interface,
+	kubeclientset kubernetes.Interface,
+	grafanaClient grafana.Interface,
+	grafanaPlaylistInformer informers.PlaylistInformer,
+	grafanaDashboardInformer informers.DashboardInformer,
+	grafanaOrganizationInformer informers.OrganizationInformer) *Controller {
+
+	syncer := &PlaylistSyncer{
+		grafanaPlaylistsLister:     grafanaPlaylistInformer.Lister(),
+		grafanaDashboardsLister:    grafanaDashboardInformer.Lister(),
+		grafanaOrganizationsLister: grafanaOrganizationInformer.Lister(),
+		grafanaClient:              grafanaClient,
+		grafanaclientset:           grafanaclientset,
+		managedIDs:                 newManagedIDs(),
+	}
+
+	controller := NewController(grafanaPlaylistInformer.Informer(),
+		kubeclientset,
+		syncer)
+	controller.watchOrganizations(grafanaOrganizationInformer)
+
+	enqueuePlaylists := func(obj interface{}) {
+		key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+
+		namespace, _, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+
+		playlists, err := syncer.grafanaPlaylistsLister.Playlists(namespace).List(labels.Everything())
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+
+		for _, playlist := range playlists {
+			controller.enqueueWorkQueueItem(playlist, AddOrUpdate)
+		}
+	}
+
+	controller.watchInformer(grafanaDashboardInformer.Informer(), cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueuePlaylists,
+		UpdateFunc: func(old, new interface{}) {
+			oldDashboard, oldOk := old.(*v1alpha1.Dashboard)
+			newDashboard, newOk := new.(*v1alpha1.Dashboard)
+
+			if oldOk && newOk &&
+				oldDashboard.Status.GrafanaID == newDashboard.Status.GrafanaID &&
+				oldDashboard.Status.GrafanaOrgID == newDashboard.Status.GrafanaOrgID &&
+				reflect.DeepEqual(dashboardTags(oldDashboard), dashboardTags(newDashboard)) {
+				return
+			}
+
+			enqueuePlaylists(new)
+		},
+		DeleteFunc: enqueuePlaylists,
+	})
+
+	return controller
+}
+
+func (s *PlaylistSyncer) getType() string {
+	return prometheus.TypePlaylist
+}
+
+func (s *PlaylistSyncer) getRuntimeObjectByName(name string, namespace string) (runtime.Object, error) {
+	return s.grafanaPlaylistsLister.Playlists(namespace).Get(name)
+}
+
+func (s *PlaylistSyncer) deleteObjectById(ctx context.Context, id string) error {
+	if err := s.grafanaClient.DeletePlaylist(ctx, id); err != nil {
+		return err
+	}
+
+	s.managedIDs.remove(grafana.OrgID(ctx), id)
+	return nil
+}
+
+func (s *PlaylistSyncer) updateObject(ctx context.Context, object runtime.Object) error {
+
+	grafanaPlaylist, ok := object.(*v1alpha1.Playlist)
+	if !ok {
+		return fmt.Errorf("expected playlist in but got %#v", object)
+	}
+
+	ctx, orgID, grafanaID, err := targetOrganization(ctx,
+		s.grafanaOrganizationsLister,
+		grafanaPlaylist.Namespace,
+		grafanaPlaylist.Spec.OrganizationName,
+		grafanaPlaylist.Status.GrafanaOrgID,
+		grafanaPlaylist.Status.GrafanaID,
+		s.deleteObjectById)
+	if err != nil {
+		return err
+	}
+
+	uids, err := s.dashboardUids(grafanaPlaylist, orgID)
+	if err != nil {
+		return err
+	}
+
+	items := make([]map[string]interface{}, 0, len(uids))
+	for _, uid := range uids {
+		items = append(items, map[string]interface{}{
+			"type":  "dashboard_by_uid",
+			"value": uid,
+		})
+	}
+
+	playlistJson, err := json.Marshal(map[string]interface{}{
+		"name":     defaultString(grafanaPlaylist.Spec.Name, grafanaPlaylist.Name),
+		"interval": defaultString(grafanaPlaylist.Spec.Interval, defaultPlaylistInterval),
+		"items":    items,
+	})
+	if err != nil {
+		return err
+	}
+
+	id, err := s.grafanaClient.PostPlaylist(ctx, string(playlistJson), grafanaID)
+
+	if err != nil {
+		return err
+	}
+
+	s.managedIDs.add(orgID, id)
+
+	grafanaPlaylistCopy := grafanaPlaylist.DeepCopy()
+	grafanaPlaylistCopy.Status.GrafanaID = id
+	grafanaPlaylistCopy.Status.GrafanaOrgID = orgID
+
+	_, err = s.grafanaclientset.GrafanaV1alpha1().Playlists(grafanaPlaylist.Namespace).UpdateStatus(grafanaPlaylistCopy)
+
+	return err
+}
+
+// dashboardUids resolves the items of a playlist to dashboard uids.  A dashboard referenced by
+// name must have been created in grafana in the organization orgID.  Tagged dashboards that have
+// not been created yet, or are in another organization, are left out.
+func (s *PlaylistSyncer) dashboardUids(playlist *v1alpha1.Playlist, orgID string) ([]string, error) {
+	var uids []string
+
+	for i, item := range playlist.Spec.Items {
+		if (item.DashboardName == "") == (item.Tag == "") {
+			return nil, grafana.Invalidf("item %d of playlist %s must set one of dashboardName and tag", i, playlist.Name)
+		}
+
+		if item.DashboardName != "" {
+			dashboard, err := s.grafanaDashboardsLister.Dashboards(playlist.Namespace).Get(item.DashboardName)
+			if err != nil {
+				return nil, err
+			}
+
+			if dashboard.Status.GrafanaID == grafana.NO_ID {
+				return nil, fmt.Errorf("dashboard %s has not been created in grafana yet", dashboard.Name)
+			}
+
+			if dashboard.Status.GrafanaOrgID != orgID {
+				return nil, fmt.Errorf("dashboard %s is not in the same organization as playlist %s", dashboard.Name, playlist.Name)
+			}
+
+			uids = append(uids, dashboard.Status.GrafanaID)
+			continue
+		}
+
+		dashboards, err := s.grafanaDashboardsLister.Dashboards(playlist.Namespace).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Slice(dashboards, func(i, j int) bool { return dashboards[i].Name < dashboards[j].Name })
+
+		for _, dashboard := range dashboards {
+			if dashboard.Status.GrafanaID == grafana.NO_ID || dashboard.Status.GrafanaOrgID != orgID {
+				continue
+			}
+
+			for _, tag := range dashboardTags(dashboard) {
+				if tag == item.Tag {
+					uids = append(uids, dashboard.Status.GrafanaID)
+					break
+				}
+			}
+		}
+	}
+
+	return uids, nil
+}
+
+// dashboardTags returns the tags in the json of a dashboard.  Dashboards with invalid json have
+// none.
+func dashboardTags(dashboard *v1alpha1.Dashboard) []string {
+	var model struct {
+		Tags []string `json:"tags"`
+	}
+
+	if err := json.Unmarshal([]byte(dashboard.Spec.JSON), &model); err != nil {
+		return nil
+	}
+
+	return model.Tags
+}
+
+func (s *PlaylistSyncer) getAllKubernetesObjectIDs(orgID string) ([]string, error) {
+	playlists, err := s.grafanaPlaylistsLister.List(labels.Everything())
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+
+	for _, playlist := range playlists {
+		// objects without an id may be in any organization
+		if playlist.Status.GrafanaOrgID != orgID && playlist.Status.GrafanaID != grafana.NO_ID {
+			continue
+		}
+
+		if playlist.Status.GrafanaID != grafana.NO_ID {
+			s.managedIDs.add(orgID, playlist.Status.GrafanaID)
+		}
+
+		ids = append(ids, playlist.Status.GrafanaID)
+	}
+
+	return ids, nil
+}
+
+// getAllGrafanaObjectIDs returns the playlists the syncer created or found in the status of a
+// Playlist.  Playlists created by hand are never deleted.
+func (s *PlaylistSyncer) getAllGrafanaObjectIDs(ctx context.Context) ([]string, error) {
+	ids, err := s.grafanaClient.GetAllPlaylistIds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.managedIDs.filter(grafana.OrgID(ctx), ids), nil
+}
+
+func (s *PlaylistSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
+	var key string
+	var err error
+	var playlist *v1alpha1.Playlist
+	var ok bool
+
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	if playlist, ok = obj.(*v1alpha1.Playlist); !ok {
+		utilruntime.HandleError(fmt.Errorf("expected playlist in workqueue but got %#v", obj))
+		return nil
+	}
+
+	item := NewWorkQueueItem(key, playlist.DeepCopyObject(), playlist.Status.GrafanaID, playlist.Status.GrafanaOrgID)
+
+	return &item
+}
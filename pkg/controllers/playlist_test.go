@@ -0,0 +1,203 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+)
+
+func newPlaylist(name string, items ...v1alpha1.PlaylistItem) *v1alpha1.Playlist {
+	return &v1alpha1.Playlist{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: newObjectMeta(name),
+		Spec: v1alpha1.PlaylistSpec{
+			Items: items,
+		},
+	}
+}
+
+func newPlaylistController(f *fixture) *Controller {
+	return NewPlaylistController(f.client, f.kubeclient, f.grafanaClient,
+		f.informers.Grafana().V1alpha1().Playlists(),
+		f.informers.Grafana().V1alpha1().Dashboards(),
+		f.informers.Grafana().V1alpha1().Organizations())
+}
+
+func (f *fixture) getPlaylist(name string) *v1alpha1.Playlist {
+	playlist, err := f.client.GrafanaV1alpha1().Playlists(metav1.NamespaceDefault).Get(name, metav1.GetOptions{})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return playlist
+}
+
+// syncedDashboard returns a dashboard object that has been created in grafana with the given uid
+func syncedDashboard(name string, uid string, dashboardJson string) *v1alpha1.Dashboard {
+	dashboard := newDashboard(name, dashboardJson, "")
+	dashboard.Status.GrafanaID = uid
+
+	return dashboard
+}
+
+// postedPlaylist returns the playlist the controller last posted
+func (f *fixture) postedPlaylist() (posted struct {
+	Name     string `json:"name"`
+	Interval string `json:"interval"`
+	Items    []struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"items"`
+}) {
+	calls := f.grafanaClient.CallsTo("PostPlaylist")
+	if len(calls) == 0 {
+		f.t.Fatal("expected a playlist to be posted")
+	}
+
+	if err := json.Unmarshal([]byte(calls[len(calls)-1].Args[0]), &posted); err != nil {
+		f.t.Fatal(err)
+	}
+
+	return posted
+}
+
+func TestCreatesPlaylist(t *testing.T) {
+	playlist := newPlaylist("noc",
+		v1alpha1.PlaylistItem{DashboardName: "overview"},
+		v1alpha1.PlaylistItem{Tag: "noc"})
+
+	f := newFixture(t,
+		syncedDashboard("overview", "overview-uid", `{"title": "overview"}`),
+		syncedDashboard("b-ingress", "ingress-uid", `{"title": "ingress", "tags": ["noc"]}`),
+		syncedDashboard("a-database", "database-uid", `{"title": "database", "tags": ["db", "noc"]}`),
+		syncedDashboard("other", "other-uid", `{"title": "other", "tags": ["db"]}`),
+		newDashboard("unsynced", `{"title": "unsynced", "tags": ["noc"]}`, ""),
+		playlist)
+	c := f.newController(newPlaylistController)
+
+	if err := f.sync(c, newItem(playlist, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	uid := f.getPlaylist("noc").Status.GrafanaID
+	if uid == "" {
+		t.Fatal("expected the playlist status to be updated with its grafana uid")
+	}
+
+	if _, err := f.grafanaClient.GetPlaylist(context.Background(), uid); err != nil {
+		t.Errorf("expected playlist in grafana: %v", err)
+	}
+
+	posted := f.postedPlaylist()
+
+	if posted.Name != "noc" || posted.Interval != defaultPlaylistInterval {
+		t.Errorf("expected the name and interval to be defaulted, got %+v", posted)
+	}
+
+	var uids []string
+	for _, item := range posted.Items {
+		if item.Type != "dashboard_by_uid" {
+			t.Errorf("expected items by uid, got %+v", item)
+		}
+
+		uids = append(uids, item.Value)
+	}
+
+	expected := []string{"overview-uid", "database-uid", "ingress-uid"}
+	if !reflect.DeepEqual(uids, expected) {
+		t.Errorf("expected dashboards %v, got %v", expected, uids)
+	}
+}
+
+func TestPlaylistWaitsForDashboard(t *testing.T) {
+	playlist := newPlaylist("noc", v1alpha1.PlaylistItem{DashboardName: "overview"})
+
+	f := newFixture(t, newDashboard("overview", `{"title": "overview"}`, ""), playlist)
+	c := f.newController(newPlaylistController)
+
+	if err := f.sync(c, newItem(playlist, AddOrUpdate, "", t)); err == nil {
+		t.Error("expected an error for a dashboard that has not been created in grafana yet")
+	}
+
+	if calls := f.grafanaClient.CallsTo("PostPlaylist"); len(calls) != 0 {
+		t.Errorf("expected nothing to be posted, got %v", calls)
+	}
+}
+
+func TestPlaylistItemMustSetOneReference(t *testing.T) {
+	playlist := newPlaylist("noc", v1alpha1.PlaylistItem{DashboardName: "overview", Tag: "noc"})
+
+	f := newFixture(t, syncedDashboard("overview", "overview-uid", `{"title": "overview"}`), playlist)
+	c := f.newController(newPlaylistController)
+
+	if err := f.sync(c, newItem(playlist, AddOrUpdate, "", t)); err == nil {
+		t.Error("expected an error for an item with both a dashboard name and a tag")
+	}
+}
+
+func TestUpdatesPlaylistWhenDashboardUidChanges(t *testing.T) {
+	f := newFixture(t)
+
+	uid, err := f.grafanaClient.PostPlaylist(context.Background(), `{"name": "noc"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	playlist := newPlaylist("noc", v1alpha1.PlaylistItem{DashboardName: "overview"})
+	playlist.Status.GrafanaID = uid
+
+	f = f.withObjects(syncedDashboard("overview", "new-uid", `{"title": "overview"}`), playlist)
+	c := f.newController(newPlaylistController)
+
+	if err := f.sync(c, newItem(playlist, AddOrUpdate, uid, t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if id := f.getPlaylist("noc").Status.GrafanaID; id != uid {
+		t.Errorf("expected the playlist to be updated in place, got %q", id)
+	}
+
+	if posted := f.postedPlaylist(); len(posted.Items) != 1 || posted.Items[0].Value != "new-uid" {
+		t.Errorf("expected the new dashboard uid to be posted, got %+v", posted.Items)
+	}
+}
+
+func TestResyncOnlyDeletesManagedPlaylists(t *testing.T) {
+	playlist := newPlaylist("managed")
+
+	f := newFixture(t, playlist)
+	c := f.newController(newPlaylistController)
+
+	if err := f.sync(c, newItem(playlist, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	managedUid := f.getPlaylist("managed").Status.GrafanaID
+
+	unmanagedUid, err := f.grafanaClient.PostPlaylist(context.Background(), `{"name": "created by hand", "interval": "5m"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the playlist is deleted without its delete being handled
+	if err := f.informers.Grafana().V1alpha1().Playlists().Informer().GetIndexer().Delete(playlist); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.resyncDeletedObjects(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.grafanaClient.GetPlaylist(context.Background(), managedUid); err == nil {
+		t.Errorf("expected managed playlist %s to be deleted", managedUid)
+	}
+
+	if _, err := f.grafanaClient.GetPlaylist(context.Background(), unmanagedUid); err != nil {
+		t.Errorf("expected playlist %s created by hand to be kept: %v", unmanagedUid, err)
+	}
+}
@@ -0,0 +1,221 @@
+package controllers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	rbaclisters "k8s.io/client-go/listers/rbac/v1"
+	"k8s.io/klog"
+
+	listers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/listers/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+)
+
+// RBACPermissions maps the RoleBindings and ClusterRoleBindings that apply to a namespace to
+// permissions on the folders in it.
+type RBACPermissions struct {
+	// Roles maps the names of Roles to View, Edit or Admin.  Bindings to roles that are not listed
+	// are ignored.
+	Roles map[string]string `json:"roles"`
+	// ClusterRoles maps the names of ClusterRoles to View, Edit or Admin.  Bindings to cluster
+	// roles that are not listed are ignored.
+	ClusterRoles map[string]string `json:"clusterRoles"`
+	// Users maps kubernetes user names to grafana logins or emails.  Users that are not listed
+	// are looked up in grafana by their kubernetes name.
+	Users map[string]string `json:"users"`
+	// Groups maps kubernetes groups to the names of Team objects in the folder's namespace.
+	// Groups that are not listed are ignored.
+	Groups map[string]string `json:"groups"`
+
+	// parsed once from Roles and ClusterRoles
+	initOnce          sync.Once
+	initErr           error
+	roleLevels        map[string]int
+	clusterRoleLevels map[string]int
+}
+
+// LoadRBACPermissions reads an RBACPermissions mapping from a YAML or JSON file
+func LoadRBACPermissions(path string) (*RBACPermissions, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var mapping RBACPermissions
+
+	if err := yaml.NewYAMLOrJSONDecoder(file, 4096).Decode(&mapping); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+
+	if err := mapping.parse(); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+
+	return &mapping, nil
+}
+
+// parse parses the permissions of Roles and ClusterRoles the first time it is called
+func (m *RBACPermissions) parse() error {
+	m.initOnce.Do(func() {
+		m.initErr = m.init()
+	})
+
+	return m.initErr
+}
+
+func (m *RBACPermissions) init() error {
+	var err error
+
+	if m.roleLevels, err = parseRoleLevels("role", m.Roles); err != nil {
+		return err
+	}
+
+	m.clusterRoleLevels, err = parseRoleLevels("cluster role", m.ClusterRoles)
+	return err
+}
+
+func parseRoleLevels(kind string, roles map[string]string) (map[string]int, error) {
+	levels := make(map[string]int, len(roles))
+
+	for role, permission := range roles {
+		level, err := grafana.ParsePermission(permission)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %v", kind, role, err)
+		}
+
+		levels[role] = level
+	}
+
+	return levels, nil
+}
+
+// roleLevel returns the permission the role roleRef refers to maps to.  ok is false if the role
+// is not mapped.
+func (m *RBACPermissions) roleLevel(roleRef rbacv1.RoleRef) (level int, ok bool) {
+	if err := m.parse(); err != nil {
+		return 0, false
+	}
+
+	switch roleRef.Kind {
+	case "Role":
+		level, ok = m.roleLevels[roleRef.Name]
+	case "ClusterRole":
+		level, ok = m.clusterRoleLevels[roleRef.Name]
+	}
+
+	return level, ok
+}
+
+// folderPermissions returns the permissions the RBAC bindings in namespace grant.  Subjects that
+// can not be found in grafana or do not have a Team object yet are skipped.  A subject bound to
+// several roles gets the highest permission.
+func (m *RBACPermissions) folderPermissions(roleBindingsLister rbaclisters.RoleBindingLister,
+	clusterRoleBindingsLister rbaclisters.ClusterRoleBindingLister,
+	teamsLister listers.TeamLister,
+	namespace string,
+	orgID string,
+	userIds func() (map[string]string, error)) ([]grafana.Permission, error) {
+
+	if err := m.parse(); err != nil {
+		return nil, err
+	}
+
+	roleBindings, err := roleBindingsLister.RoleBindings(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	clusterRoleBindings, err := clusterRoleBindingsLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	type binding struct {
+		roleRef  rbacv1.RoleRef
+		subjects []rbacv1.Subject
+	}
+
+	var bindings []binding
+	for _, roleBinding := range roleBindings {
+		bindings = append(bindings, binding{roleBinding.RoleRef, roleBinding.Subjects})
+	}
+	for _, clusterRoleBinding := range clusterRoleBindings {
+		bindings = append(bindings, binding{clusterRoleBinding.RoleRef, clusterRoleBinding.Subjects})
+	}
+
+	var users map[string]string
+	levels := make(map[grafana.Permission]int)
+
+	for _, binding := range bindings {
+		level, ok := m.roleLevel(binding.roleRef)
+		if !ok {
+			continue
+		}
+
+		for _, subject := range binding.subjects {
+			var permission grafana.Permission
+
+			switch subject.Kind {
+			case rbacv1.UserKind:
+				// users are only looked up if needed and then only once
+				if users == nil {
+					if users, err = userIds(); err != nil {
+						return nil, err
+					}
+				}
+
+				login, ok := m.Users[subject.Name]
+				if !ok {
+					login = subject.Name
+				}
+
+				userID, ok := users[strings.ToLower(login)]
+				if !ok {
+					klog.V(4).Infof("Skipping rbac subject %s.  No grafana user %s", subject.Name, login)
+					continue
+				}
+
+				permission.UserID = userID
+			case rbacv1.GroupKind:
+				teamName, ok := m.Groups[subject.Name]
+				if !ok {
+					continue
+				}
+
+				teamID, err := resolveTeam(teamsLister, namespace, orgID, teamName)
+				if k8serrors.IsNotFound(err) {
+					klog.V(4).Infof("Skipping rbac subject %s.  No team %s in %s", subject.Name, teamName, namespace)
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+
+				permission.TeamID = teamID
+			default:
+				continue
+			}
+
+			if level > levels[permission] {
+				levels[permission] = level
+			}
+		}
+	}
+
+	permissions := make([]grafana.Permission, 0, len(levels))
+	for permission, level := range levels {
+		permission.Permission = level
+		permissions = append(permissions, permission)
+	}
+
+	grafana.SortPermissions(permissions)
+
+	return permissions, nil
+}
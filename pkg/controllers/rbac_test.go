@@ -0,0 +1,250 @@
+package controllers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+)
+
+func writeTempFile(t *testing.T, contents string) string {
+	file, err := ioutil.TempFile("", "rbac")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(contents); err != nil {
+		t.Fatal(err)
+	}
+
+	return file.Name()
+}
+
+func TestLoadRBACPermissions(t *testing.T) {
+	path := writeTempFile(t, `
+roles:
+  admin: Admin
+clusterRoles:
+  admin: Edit
+  view: View
+users:
+  jane: jane@example.com
+groups:
+  platform: platform-team
+`)
+	defer os.Remove(path)
+
+	mapping, err := LoadRBACPermissions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mapping.roleLevels["admin"] != grafana.PermissionAdmin || mapping.clusterRoleLevels["admin"] != grafana.PermissionEdit || mapping.clusterRoleLevels["view"] != grafana.PermissionView {
+		t.Errorf("unexpected role levels %v and cluster role levels %v", mapping.roleLevels, mapping.clusterRoleLevels)
+	}
+
+	if mapping.Users["jane"] != "jane@example.com" || mapping.Groups["platform"] != "platform-team" {
+		t.Errorf("unexpected mapping %+v", mapping)
+	}
+
+	invalid := writeTempFile(t, `{"clusterRoles": {"admin": "Owner"}}`)
+	defer os.Remove(invalid)
+
+	if _, err := LoadRBACPermissions(invalid); err == nil {
+		t.Error("expected an error for an unknown permission")
+	}
+}
+
+func newRoleBinding(name string, role string, subjects ...rbacv1.Subject) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: newObjectMeta(name),
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: role},
+		Subjects:   subjects,
+	}
+}
+
+// newRBACFolderController returns a folder controller deriving permissions with mapping from the
+// RoleBindings and ClusterRoleBindings in bindings
+func (f *fixture) newRBACFolderController(mapping *RBACPermissions, bindings ...runtime.Object) *Controller {
+	kubeInformers := kubeinformers.NewSharedInformerFactory(f.kubeclient, 0)
+
+	for _, binding := range bindings {
+		var err error
+
+		switch binding.(type) {
+		case *rbacv1.RoleBinding:
+			err = kubeInformers.Rbac().V1().RoleBindings().Informer().GetIndexer().Add(binding)
+		case *rbacv1.ClusterRoleBinding:
+			err = kubeInformers.Rbac().V1().ClusterRoleBindings().Informer().GetIndexer().Add(binding)
+		}
+
+		if err != nil {
+			f.t.Fatal(err)
+		}
+	}
+
+	return f.newController(func(f *fixture) *Controller {
+		return NewFolderController(f.client, f.kubeclient, f.grafanaClient,
+			f.informers.Grafana().V1alpha1().Folders(),
+			f.informers.Grafana().V1alpha1().Organizations(),
+			f.informers.Grafana().V1alpha1().Teams(),
+			mapping,
+			kubeInformers.Rbac().V1())
+	})
+}
+
+func TestFolderPermissionsFromRBAC(t *testing.T) {
+	f := newFixture(t)
+
+	team := f.createTeam("platform-team")
+	janeID := f.grafanaClient.AddUser(context.Background(), "jane", "jane@example.com")
+	bobID := f.grafanaClient.AddUser(context.Background(), "bob", "bob@example.com")
+
+	folder := newFolder("test", `{"title": "test"}`)
+
+	f = f.withObjects(team, folder)
+
+	bindings := []runtime.Object{
+		newRoleBinding("viewers", "view",
+			rbacv1.Subject{Kind: rbacv1.UserKind, Name: "jane"},
+			rbacv1.Subject{Kind: rbacv1.UserKind, Name: "bob"},
+			rbacv1.Subject{Kind: rbacv1.UserKind, Name: "missing"},
+			rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "default"}),
+		newRoleBinding("admins", "admin",
+			rbacv1.Subject{Kind: rbacv1.UserKind, Name: "k8s-jane"},
+			rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "platform"},
+			rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "unmapped"}),
+		newRoleBinding("other", "unmapped-role",
+			rbacv1.Subject{Kind: rbacv1.UserKind, Name: "bob"}),
+	}
+
+	// a namespaced role named like a mapped cluster role is not mapped
+	namespacedAdmin := newRoleBinding("namespaced-admins", "admin",
+		rbacv1.Subject{Kind: rbacv1.UserKind, Name: "bob"})
+	namespacedAdmin.RoleRef.Kind = "Role"
+
+	// cluster role bindings apply to every namespace
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "editors"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "edit"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "bob"}},
+	}
+
+	bindings = append(bindings, namespacedAdmin, clusterRoleBinding)
+
+	mapping := &RBACPermissions{
+		ClusterRoles: map[string]string{"admin": "Admin", "edit": "Edit", "view": "View"},
+		Users:        map[string]string{"k8s-jane": "jane@example.com"},
+		Groups:       map[string]string{"platform": "platform-team"},
+	}
+
+	c := f.newRBACFolderController(mapping, bindings...)
+
+	if err := f.sync(c, newItem(folder, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	permissions, err := f.grafanaClient.GetFolderPermissions(context.Background(), f.getFolder("test").Status.GrafanaID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the default role permissions are replaced
+	expected := []grafana.Permission{
+		{UserID: janeID, Permission: grafana.PermissionAdmin},
+		{UserID: bobID, Permission: grafana.PermissionEdit},
+		{TeamID: team.Status.GrafanaID, Permission: grafana.PermissionAdmin},
+	}
+
+	if !samePermissions(permissions, expected) {
+		t.Errorf("expected permissions %v, got %v", expected, permissions)
+	}
+}
+
+func TestFolderRBACPermissionsKeepSpecPermissions(t *testing.T) {
+	folder := newFolder("test", `{"title": "test"}`)
+	folder.Spec.Permissions = []v1alpha1.Permission{
+		{Role: "Viewer", Permission: "View"},
+	}
+
+	f := newFixture(t, folder)
+
+	c := f.newRBACFolderController(&RBACPermissions{ClusterRoles: map[string]string{"admin": "Admin"}})
+
+	if err := f.sync(c, newItem(folder, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	permissions, err := f.grafanaClient.GetFolderPermissions(context.Background(), f.getFolder("test").Status.GrafanaID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []grafana.Permission{{Role: "Viewer", Permission: grafana.PermissionView}}
+	if !samePermissions(permissions, expected) {
+		t.Errorf("expected permissions %v, got %v", expected, permissions)
+	}
+}
+
+func TestRoleBindingChangeSyncsFoldersInItsNamespace(t *testing.T) {
+	folder := newFolder("test", `{"title": "test"}`)
+
+	other := newFolder("other", `{"title": "other"}`)
+	other.Namespace = "other"
+
+	f := newFixture(t, folder, other)
+
+	kubeInformers := kubeinformers.NewSharedInformerFactory(f.kubeclient, 0)
+	roleBindingInformer := kubeInformers.Rbac().V1().RoleBindings().Informer()
+
+	c := f.newController(func(f *fixture) *Controller {
+		return NewFolderController(f.client, f.kubeclient, f.grafanaClient,
+			f.informers.Grafana().V1alpha1().Folders(),
+			f.informers.Grafana().V1alpha1().Organizations(),
+			f.informers.Grafana().V1alpha1().Teams(),
+			&RBACPermissions{ClusterRoles: map[string]string{"view": "View"}},
+			kubeInformers.Rbac().V1())
+	})
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	kubeInformers.Start(stopCh)
+
+	if !cache.WaitForCacheSync(stopCh, roleBindingInformer.HasSynced) {
+		t.Fatal("role binding informer did not sync")
+	}
+
+	// bindings to roles that are not mapped are ignored
+	if _, err := f.kubeclient.RbacV1().RoleBindings(metav1.NamespaceDefault).Create(newRoleBinding("unmapped", "edit")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.kubeclient.RbacV1().RoleBindings(metav1.NamespaceDefault).Create(newRoleBinding("viewers", "view")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return c.workqueue.Len() > 0, nil
+	})
+	if err != nil {
+		t.Fatal("expected the folder to be enqueued")
+	}
+
+	item, _ := c.workqueue.Get()
+	if key := item.(WorkQueueItem).key; key != "default/test" || c.workqueue.Len() != 0 {
+		t.Errorf("expected only default/test to be enqueued, got %v and %d more", key, c.workqueue.Len())
+	}
+}
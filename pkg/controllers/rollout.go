@@ -0,0 +1,434 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	appsinformers "k8s.io/client-go/informers/apps/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+const (
+	// RolloutAnnotationsLabel opts a Deployment, StatefulSet or DaemonSet in to rollout annotations
+	// when it is set to "true"
+	RolloutAnnotationsLabel = "grafana.com/rollout-annotations"
+
+	rolloutStarted  = "started"
+	rolloutFinished = "finished"
+)
+
+// rollout is the rollout state of a workload
+type rollout struct {
+	kind     string
+	workload metav1.Object
+	// revision is empty until the workload's controller has observed its latest spec
+	revision   string
+	images     []string
+	inProgress bool
+}
+
+// rolloutState is the last annotated rollout of the workload with uid.  state is
+// <revision>/<phase>, or empty if the workload has not been annotated.
+type rolloutState struct {
+	uid   types.UID
+	state string
+}
+
+// RolloutSyncer annotates the rollouts of one kind of workload in grafana.  An annotation is added
+// in the default organization when a new revision starts rolling out and when it has finished.
+// Workloads are never changed.  The last annotated rollout of each workload is kept in memory and
+// read back from grafana's annotations when the controller starts.
+type RolloutSyncer struct {
+	prometheusType string
+	getWorkload    func(name string, namespace string) (runtime.Object, error)
+	rolloutOf      func(runtime.Object) (*rollout, error)
+	grafanaClient  grafana.Interface
+
+	// states are keyed by namespace/name
+	lock   sync.Mutex
+	states map[string]rolloutState
+}
+
+// NewDeploymentRolloutController returns a controller annotating the rollouts of Deployments
+func NewDeploymentRolloutController(
+	kubeclientset kubernetes.Interface,
+	grafanaClient grafana.Interface,
+	deploymentInformer appsinformers.DeploymentInformer) *Controller {
+
+	lister := deploymentInformer.Lister()
+
+	return newRolloutController(deploymentInformer.Informer(), kubeclientset, &RolloutSyncer{
+		prometheusType: prometheus.TypeDeploymentRollout,
+		getWorkload: func(name string, namespace string) (runtime.Object, error) {
+			return lister.Deployments(namespace).Get(name)
+		},
+		rolloutOf:     deploymentRollout,
+		grafanaClient: grafanaClient,
+		states:        make(map[string]rolloutState),
+	})
+}
+
+// NewStatefulSetRolloutController returns a controller annotating the rollouts of StatefulSets
+func NewStatefulSetRolloutController(
+	kubeclientset kubernetes.Interface,
+	grafanaClient grafana.Interface,
+	statefulSetInformer appsinformers.StatefulSetInformer) *Controller {
+
+	lister := statefulSetInformer.Lister()
+
+	return newRolloutController(statefulSetInformer.Informer(), kubeclientset, &RolloutSyncer{
+		prometheusType: prometheus.TypeStatefulSetRollout,
+		getWorkload: func(name string, namespace string) (runtime.Object, error) {
+			return lister.StatefulSets(namespace).Get(name)
+		},
+		rolloutOf:     statefulSetRollout,
+		grafanaClient: grafanaClient,
+		states:        make(map[string]rolloutState),
+	})
+}
+
+// NewDaemonSetRolloutController returns a controller annotating the rollouts of DaemonSets
+func NewDaemonSetRolloutController(
+	kubeclientset kubernetes.Interface,
+	grafanaClient grafana.Interface,
+	daemonSetInformer appsinformers.DaemonSetInformer) *Controller {
+
+	lister := daemonSetInformer.Lister()
+
+	return newRolloutController(daemonSetInformer.Informer(), kubeclientset, &RolloutSyncer{
+		prometheusType: prometheus.TypeDaemonSetRollout,
+		getWorkload: func(name string, namespace string) (runtime.Object, error) {
+			return lister.DaemonSets(namespace).Get(name)
+		},
+		rolloutOf:     daemonSetRollout,
+		grafanaClient: grafanaClient,
+		states:        make(map[string]rolloutState),
+	})
+}
+
+// newRolloutController returns a quiet controller.  Workloads change on every step of a rollout
+// and are not the controller's to report on.
+func newRolloutController(informer cache.SharedIndexInformer, kubeclientset kubernetes.Interface, syncer *RolloutSyncer) *Controller {
+	controller := NewController(informer, kubeclientset, syncer)
+	controller.quiet = true
+
+	return controller
+}
+
+func deploymentRollout(obj runtime.Object) (*rollout, error) {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return nil, fmt.Errorf("expected deployment but got %#v", obj)
+	}
+
+	r := &rollout{
+		kind:     "Deployment",
+		workload: deployment,
+		images:   containerImages(deployment.Spec.Template.Spec),
+	}
+
+	if deployment.Generation > deployment.Status.ObservedGeneration {
+		return r, nil
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	// the checks of kubectl rollout status
+	r.revision = deployment.Annotations["deployment.kubernetes.io/revision"]
+	r.inProgress = deployment.Status.UpdatedReplicas < replicas ||
+		deployment.Status.Replicas > deployment.Status.UpdatedReplicas ||
+		deployment.Status.AvailableReplicas < deployment.Status.UpdatedReplicas
+
+	return r, nil
+}
+
+func statefulSetRollout(obj runtime.Object) (*rollout, error) {
+	statefulSet, ok := obj.(*appsv1.StatefulSet)
+	if !ok {
+		return nil, fmt.Errorf("expected statefulset but got %#v", obj)
+	}
+
+	r := &rollout{
+		kind:     "StatefulSet",
+		workload: statefulSet,
+		images:   containerImages(statefulSet.Spec.Template.Spec),
+	}
+
+	if statefulSet.Generation > statefulSet.Status.ObservedGeneration {
+		return r, nil
+	}
+
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+
+	r.revision = statefulSet.Status.UpdateRevision
+	r.inProgress = statefulSet.Status.UpdatedReplicas < replicas ||
+		statefulSet.Status.ReadyReplicas < replicas ||
+		statefulSet.Status.CurrentRevision != statefulSet.Status.UpdateRevision
+
+	return r, nil
+}
+
+// daemonSetRollout uses the generation as the revision.  DaemonSets do not report theirs.
+func daemonSetRollout(obj runtime.Object) (*rollout, error) {
+	daemonSet, ok := obj.(*appsv1.DaemonSet)
+	if !ok {
+		return nil, fmt.Errorf("expected daemonset but got %#v", obj)
+	}
+
+	r := &rollout{
+		kind:     "DaemonSet",
+		workload: daemonSet,
+		images:   containerImages(daemonSet.Spec.Template.Spec),
+	}
+
+	if daemonSet.Generation > daemonSet.Status.ObservedGeneration {
+		return r, nil
+	}
+
+	r.revision = strconv.FormatInt(daemonSet.Generation, 10)
+	r.inProgress = daemonSet.Status.UpdatedNumberScheduled < daemonSet.Status.DesiredNumberScheduled ||
+		daemonSet.Status.NumberAvailable < daemonSet.Status.DesiredNumberScheduled
+
+	return r, nil
+}
+
+func containerImages(podSpec corev1.PodSpec) []string {
+	var images []string
+
+	for _, container := range podSpec.Containers {
+		images = append(images, container.Image)
+	}
+
+	return images
+}
+
+func (s *RolloutSyncer) getType() string {
+	return s.prometheusType
+}
+
+func (s *RolloutSyncer) getRuntimeObjectByName(name string, namespace string) (runtime.Object, error) {
+	return s.getWorkload(name, namespace)
+}
+
+// deleteObjectById keeps the annotations of deleted workloads.  They are history.  The state of
+// the workload, whose key is the id, is forgotten.
+func (s *RolloutSyncer) deleteObjectById(ctx context.Context, id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.states, id)
+	return nil
+}
+
+// updateObject annotates a rollout once it starts and once it finishes.  Workloads seen for the
+// first time outside of a rollout are only recorded.  A workload that is scaled keeps its revision
+// and is not annotated.
+func (s *RolloutSyncer) updateObject(ctx context.Context, object runtime.Object) error {
+	r, err := s.rolloutOf(object)
+	if err != nil {
+		return err
+	}
+
+	// the status update observing the spec requeues the workload
+	if r.revision == "" {
+		return nil
+	}
+
+	recorded, err := s.recordedState(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	recordedRevision := strings.SplitN(recorded, "/", 2)[0]
+
+	phase := rolloutFinished
+	if r.inProgress {
+		phase = rolloutStarted
+	}
+
+	state := r.revision + "/" + phase
+
+	if recorded == state || (r.inProgress && recordedRevision == r.revision) {
+		return nil
+	}
+
+	if recorded != "" || r.inProgress {
+		annotationJson, err := rolloutAnnotationJson(r, phase, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.grafanaClient.AddAnnotation(ctx, annotationJson); err != nil {
+			return err
+		}
+
+		klog.Infof("Annotated rollout of %s %s/%s revision %s %s", r.kind, r.workload.GetNamespace(), r.workload.GetName(), r.revision, phase)
+	}
+
+	s.setState(r.workload, state)
+
+	return nil
+}
+
+func workloadKey(workload metav1.Object) string {
+	return workload.GetNamespace() + "/" + workload.GetName()
+}
+
+// recordedState returns the last annotated rollout of a workload.  A workload not seen since the
+// controller started is looked up in grafana.  Annotations older than the workload belong to a
+// deleted workload of the same name.
+func (s *RolloutSyncer) recordedState(ctx context.Context, r *rollout) (string, error) {
+	s.lock.Lock()
+	recorded, ok := s.states[workloadKey(r.workload)]
+	s.lock.Unlock()
+
+	if ok && recorded.uid == r.workload.GetUID() {
+		return recorded.state, nil
+	}
+
+	annotations, err := s.grafanaClient.FindAnnotations(ctx, []string{
+		"rollout",
+		"kind:" + r.kind,
+		"namespace:" + r.workload.GetNamespace(),
+		"name:" + r.workload.GetName(),
+	}, 1)
+	if err != nil {
+		return "", err
+	}
+
+	state := ""
+	if len(annotations) > 0 && annotations[0].Time >= epochMillis(r.workload.GetCreationTimestamp().Time) {
+		state = rolloutAnnotationState(annotations[0].Tags)
+	}
+
+	s.setState(r.workload, state)
+
+	return state, nil
+}
+
+func (s *RolloutSyncer) setState(workload metav1.Object, state string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.states[workloadKey(workload)] = rolloutState{
+		uid:   workload.GetUID(),
+		state: state,
+	}
+}
+
+// rolloutAnnotationState returns the <revision>/<phase> state from the tags of a rollout
+// annotation.  It is empty if the tags are not those of a rollout annotation.
+func rolloutAnnotationState(tags []string) string {
+	var revision, phase string
+
+	for _, tag := range tags {
+		switch {
+		case strings.HasPrefix(tag, "revision:"):
+			revision = strings.TrimPrefix(tag, "revision:")
+		case tag == "rollout-"+rolloutStarted:
+			phase = rolloutStarted
+		case tag == "rollout-"+rolloutFinished:
+			phase = rolloutFinished
+		}
+	}
+
+	if revision == "" || phase == "" {
+		return ""
+	}
+
+	return revision + "/" + phase
+}
+
+// rolloutAnnotationJson returns an organization wide annotation for a rollout.  Tags identify the
+// workload, its revision and images so dashboards can select the rollouts they show.
+func rolloutAnnotationJson(r *rollout, phase string, at time.Time) (string, error) {
+	tags := []string{
+		"rollout",
+		"rollout-" + phase,
+		"kind:" + r.kind,
+		"namespace:" + r.workload.GetNamespace(),
+		"name:" + r.workload.GetName(),
+		"revision:" + r.revision,
+	}
+
+	for _, image := range r.images {
+		tags = append(tags, "image:"+image)
+	}
+
+	annotationJson, err := json.Marshal(map[string]interface{}{
+		"text": fmt.Sprintf("%s %s/%s rollout of revision %s %s (%s)", r.kind, r.workload.GetNamespace(), r.workload.GetName(),
+			r.revision, phase, strings.Join(r.images, ", ")),
+		"tags": tags,
+		"time": epochMillis(at),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(annotationJson), nil
+}
+
+// getAllKubernetesObjectIDs returns nothing.  Rollout annotations are never deleted.
+func (s *RolloutSyncer) getAllKubernetesObjectIDs(orgID string) ([]string, error) {
+	return nil, nil
+}
+
+// getAllGrafanaObjectIDs returns nothing.  Rollout annotations are never deleted.
+func (s *RolloutSyncer) getAllGrafanaObjectIDs(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+// createWorkQueueItem skips workloads that have not opted in with RolloutAnnotationsLabel.  The
+// key of the workload is used as its id so its state is forgotten once it is deleted.
+func (s *RolloutSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
+	var key string
+	var err error
+
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	runtimeObject, ok := obj.(runtime.Object)
+	if !ok {
+		// deleted workloads are not annotated
+		return nil
+	}
+
+	workload, err := meta.Accessor(runtimeObject)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	if workload.GetLabels()[RolloutAnnotationsLabel] != "true" {
+		return nil
+	}
+
+	item := NewWorkQueueItem(key, runtimeObject.DeepCopyObject(), key, "")
+
+	return &item
+}
@@ -0,0 +1,400 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+)
+
+func newDeployment(name string, revision string, image string) *appsv1.Deployment {
+	replicas := int32(2)
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: newObjectMeta(name),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: name, Image: image}},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 1,
+			Replicas:           2,
+			UpdatedReplicas:    2,
+			AvailableReplicas:  2,
+		},
+	}
+	deployment.Generation = 1
+	deployment.Labels = map[string]string{RolloutAnnotationsLabel: "true"}
+	deployment.Annotations = map[string]string{"deployment.kubernetes.io/revision": revision}
+
+	return deployment
+}
+
+// newRolloutController builds the rollout controller of obj's kind on an informer filled with the
+// workloads in the fake kubernetes clientset
+func (f *fixture) newRolloutController(obj runtime.Object) (*Controller, kubeinformers.SharedInformerFactory) {
+	kubeInformers := kubeinformers.NewSharedInformerFactory(f.kubeclient, 0)
+
+	var c *Controller
+
+	switch obj.(type) {
+	case *appsv1.Deployment:
+		c = NewDeploymentRolloutController(f.kubeclient, f.grafanaClient, kubeInformers.Apps().V1().Deployments())
+	case *appsv1.StatefulSet:
+		c = NewStatefulSetRolloutController(f.kubeclient, f.grafanaClient, kubeInformers.Apps().V1().StatefulSets())
+	case *appsv1.DaemonSet:
+		c = NewDaemonSetRolloutController(f.kubeclient, f.grafanaClient, kubeInformers.Apps().V1().DaemonSets())
+	}
+
+	c.informerSynced = alwaysReady
+	c.recorder = f.recorder
+
+	return c, kubeInformers
+}
+
+// syncWorkload stores obj in the controller's lister and syncs it
+func (f *fixture) syncWorkload(c *Controller, kubeInformers kubeinformers.SharedInformerFactory, obj runtime.Object) {
+	var err error
+
+	switch obj.(type) {
+	case *appsv1.Deployment:
+		err = kubeInformers.Apps().V1().Deployments().Informer().GetIndexer().Update(obj)
+	case *appsv1.StatefulSet:
+		err = kubeInformers.Apps().V1().StatefulSets().Informer().GetIndexer().Update(obj)
+	case *appsv1.DaemonSet:
+		err = kubeInformers.Apps().V1().DaemonSets().Informer().GetIndexer().Update(obj)
+	}
+
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	if err := f.sync(c, newItem(obj, AddOrUpdate, "", f.t)); err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+// rolloutStateOf returns the state the controller recorded for a workload and whether there is one
+func rolloutStateOf(c *Controller, obj runtime.Object, t *testing.T) (string, bool) {
+	syncer := c.syncer.(*RolloutSyncer)
+
+	syncer.lock.Lock()
+	defer syncer.lock.Unlock()
+
+	recorded, ok := syncer.states[getKey(obj, t)]
+
+	return recorded.state, ok
+}
+
+func (f *fixture) addedAnnotationTags() [][]string {
+	var tags [][]string
+
+	for _, annotationJson := range f.grafanaClient.AddedAnnotations(context.Background()) {
+		var annotation struct {
+			Tags []string `json:"tags"`
+		}
+
+		if err := json.Unmarshal([]byte(annotationJson), &annotation); err != nil {
+			f.t.Fatal(err)
+		}
+
+		tags = append(tags, annotation.Tags)
+	}
+
+	return tags
+}
+
+func TestRecordsFirstSeenDeploymentWithoutAnnotating(t *testing.T) {
+	f := newFixture(t)
+
+	deployment := newDeployment("api", "1", "api:1.0")
+	c, kubeInformers := f.newRolloutController(deployment)
+
+	f.syncWorkload(c, kubeInformers, deployment)
+
+	if state, _ := rolloutStateOf(c, deployment, t); state != "1/finished" {
+		t.Errorf("expected state 1/finished but got %q", state)
+	}
+
+	if added := f.grafanaClient.AddedAnnotations(context.Background()); len(added) != 0 {
+		t.Errorf("expected no annotations but got %v", added)
+	}
+
+	if len(f.recorder.Events) != 0 {
+		t.Errorf("expected the rollout controller not to record events")
+	}
+
+	if actions := f.kubeclient.Actions(); len(actions) != 0 {
+		t.Errorf("expected the deployment not to be changed but got %v", actions)
+	}
+}
+
+func TestAnnotatesDeploymentRollout(t *testing.T) {
+	f := newFixture(t)
+
+	deployment := newDeployment("api", "1", "api:1.0")
+	c, kubeInformers := f.newRolloutController(deployment)
+
+	f.syncWorkload(c, kubeInformers, deployment)
+
+	// a new revision rolls out
+	deployment = deployment.DeepCopy()
+	deployment.Generation = 2
+	deployment.Status.ObservedGeneration = 2
+	deployment.Annotations["deployment.kubernetes.io/revision"] = "2"
+	deployment.Spec.Template.Spec.Containers[0].Image = "api:2.0"
+	deployment.Status.UpdatedReplicas = 1
+	deployment.Status.Replicas = 3
+
+	f.syncWorkload(c, kubeInformers, deployment)
+
+	if state, _ := rolloutStateOf(c, deployment, t); state != "2/started" {
+		t.Errorf("expected state 2/started but got %q", state)
+	}
+
+	// syncing again while the rollout progresses changes nothing
+	deployment = deployment.DeepCopy()
+	deployment.Status.UpdatedReplicas = 2
+	f.syncWorkload(c, kubeInformers, deployment)
+
+	deployment = deployment.DeepCopy()
+	deployment.Status.Replicas = 2
+	f.syncWorkload(c, kubeInformers, deployment)
+
+	if state, _ := rolloutStateOf(c, deployment, t); state != "2/finished" {
+		t.Errorf("expected state 2/finished but got %q", state)
+	}
+
+	expected := [][]string{
+		{"rollout", "rollout-started", "kind:Deployment", "namespace:default", "name:api", "revision:2", "image:api:2.0"},
+		{"rollout", "rollout-finished", "kind:Deployment", "namespace:default", "name:api", "revision:2", "image:api:2.0"},
+	}
+
+	if tags := f.addedAnnotationTags(); !reflect.DeepEqual(tags, expected) {
+		t.Errorf("expected annotations tagged %v but got %v", expected, tags)
+	}
+}
+
+func TestRolloutStateIsReadFromGrafanaAfterRestart(t *testing.T) {
+	f := newFixture(t)
+
+	deployment := newDeployment("api", "2", "api:2.0")
+	deployment.Status.UpdatedReplicas = 1
+
+	c, kubeInformers := f.newRolloutController(deployment)
+	f.syncWorkload(c, kubeInformers, deployment)
+
+	// a restarted controller does not annotate the rollout again and annotates it finishing
+	restarted, kubeInformers := f.newRolloutController(deployment)
+
+	f.syncWorkload(restarted, kubeInformers, deployment)
+
+	deployment = deployment.DeepCopy()
+	deployment.Status.UpdatedReplicas = 2
+	f.syncWorkload(restarted, kubeInformers, deployment)
+
+	expected := [][]string{
+		{"rollout", "rollout-started", "kind:Deployment", "namespace:default", "name:api", "revision:2", "image:api:2.0"},
+		{"rollout", "rollout-finished", "kind:Deployment", "namespace:default", "name:api", "revision:2", "image:api:2.0"},
+	}
+
+	if tags := f.addedAnnotationTags(); !reflect.DeepEqual(tags, expected) {
+		t.Errorf("expected annotations tagged %v but got %v", expected, tags)
+	}
+}
+
+func TestRecreatedDeploymentIsSeenForTheFirstTime(t *testing.T) {
+	f := newFixture(t)
+
+	deployment := newDeployment("api", "1", "api:1.0")
+	deployment.UID = "first"
+	deployment.Status.UpdatedReplicas = 1
+
+	c, kubeInformers := f.newRolloutController(deployment)
+	f.syncWorkload(c, kubeInformers, deployment)
+
+	if err := f.sync(c, newItem(deployment, Delete, getKey(deployment, t), t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if state, ok := rolloutStateOf(c, deployment, t); ok {
+		t.Errorf("expected the state of a deleted deployment to be forgotten but got %q", state)
+	}
+
+	// the annotation of the deleted deployment is older than the new one
+	recreated := newDeployment("api", "1", "api:1.0")
+	recreated.UID = "second"
+	recreated.CreationTimestamp = metav1.NewTime(time.Now().Add(time.Hour))
+
+	restarted, kubeInformers := f.newRolloutController(recreated)
+	f.syncWorkload(restarted, kubeInformers, recreated)
+
+	if state, _ := rolloutStateOf(restarted, recreated, t); state != "1/finished" {
+		t.Errorf("expected state 1/finished but got %q", state)
+	}
+
+	if added := f.grafanaClient.AddedAnnotations(context.Background()); len(added) != 1 {
+		t.Errorf("expected only the rollout of the deleted deployment to be annotated but got %v", added)
+	}
+}
+
+func TestScalingDeploymentIsNotAnnotated(t *testing.T) {
+	f := newFixture(t)
+
+	deployment := newDeployment("api", "1", "api:1.0")
+	c, kubeInformers := f.newRolloutController(deployment)
+
+	f.syncWorkload(c, kubeInformers, deployment)
+
+	replicas := int32(4)
+	deployment = deployment.DeepCopy()
+	deployment.Spec.Replicas = &replicas
+	deployment.Generation = 2
+	deployment.Status.ObservedGeneration = 2
+
+	f.syncWorkload(c, kubeInformers, deployment)
+
+	if added := f.grafanaClient.AddedAnnotations(context.Background()); len(added) != 0 {
+		t.Errorf("expected no annotations but got %v", added)
+	}
+
+	if state, _ := rolloutStateOf(c, deployment, t); state != "1/finished" {
+		t.Errorf("expected state 1/finished but got %q", state)
+	}
+}
+
+func TestWaitsForDeploymentToBeObserved(t *testing.T) {
+	f := newFixture(t)
+
+	deployment := newDeployment("api", "1", "api:1.0")
+	deployment.Generation = 2
+
+	c, kubeInformers := f.newRolloutController(deployment)
+
+	f.syncWorkload(c, kubeInformers, deployment)
+
+	if state, ok := rolloutStateOf(c, deployment, t); ok {
+		t.Errorf("expected no state until the deployment is observed but got %q", state)
+	}
+}
+
+func TestSkipsWorkloadsWithoutLabel(t *testing.T) {
+	f := newFixture(t)
+
+	deployment := newDeployment("api", "1", "api:1.0")
+	deployment.Labels = nil
+
+	c, _ := f.newRolloutController(deployment)
+
+	if item := c.syncer.createWorkQueueItem(deployment); item != nil {
+		t.Errorf("expected no work queue item for an unlabeled deployment but got %+v", item)
+	}
+}
+
+func TestAnnotatesStatefulSetRollout(t *testing.T) {
+	f := newFixture(t)
+
+	replicas := int32(1)
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: newObjectMeta("db"),
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "db", Image: "db:1.0"}},
+				},
+			},
+		},
+		Status: appsv1.StatefulSetStatus{
+			ObservedGeneration: 1,
+			ReadyReplicas:      1,
+			UpdatedReplicas:    1,
+			CurrentRevision:    "db-a",
+			UpdateRevision:     "db-a",
+		},
+	}
+	statefulSet.Generation = 1
+	statefulSet.Labels = map[string]string{RolloutAnnotationsLabel: "true"}
+
+	c, kubeInformers := f.newRolloutController(statefulSet)
+
+	f.syncWorkload(c, kubeInformers, statefulSet)
+
+	statefulSet = statefulSet.DeepCopy()
+	statefulSet.Generation = 2
+	statefulSet.Status.ObservedGeneration = 2
+	statefulSet.Status.UpdateRevision = "db-b"
+	statefulSet.Status.UpdatedReplicas = 0
+
+	f.syncWorkload(c, kubeInformers, statefulSet)
+
+	if state, _ := rolloutStateOf(c, statefulSet, t); state != "db-b/started" {
+		t.Errorf("expected state db-b/started but got %q", state)
+	}
+
+	expected := [][]string{
+		{"rollout", "rollout-started", "kind:StatefulSet", "namespace:default", "name:db", "revision:db-b", "image:db:1.0"},
+	}
+
+	if tags := f.addedAnnotationTags(); !reflect.DeepEqual(tags, expected) {
+		t.Errorf("expected annotations tagged %v but got %v", expected, tags)
+	}
+}
+
+func TestAnnotatesDaemonSetRollout(t *testing.T) {
+	f := newFixture(t)
+
+	// revision 2 finished rolling out before the controller started
+	_, err := f.grafanaClient.AddAnnotation(context.Background(),
+		`{"text": "finished", "tags": ["rollout", "rollout-finished", "kind:DaemonSet", "namespace:default", "name:agent", "revision:2"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: newObjectMeta("agent"),
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "agent", Image: "agent:2.0"}},
+				},
+			},
+		},
+		Status: appsv1.DaemonSetStatus{
+			ObservedGeneration:     3,
+			DesiredNumberScheduled: 3,
+			UpdatedNumberScheduled: 1,
+			NumberAvailable:        3,
+		},
+	}
+	daemonSet.Generation = 3
+	daemonSet.Labels = map[string]string{RolloutAnnotationsLabel: "true"}
+
+	c, kubeInformers := f.newRolloutController(daemonSet)
+
+	f.syncWorkload(c, kubeInformers, daemonSet)
+
+	if state, _ := rolloutStateOf(c, daemonSet, t); state != "3/started" {
+		t.Errorf("expected state 3/started but got %q", state)
+	}
+
+	expected := [][]string{
+		{"rollout", "rollout-finished", "kind:DaemonSet", "namespace:default", "name:agent", "revision:2"},
+		{"rollout", "rollout-started", "kind:DaemonSet", "namespace:default", "name:agent", "revision:3", "image:agent:2.0"},
+	}
+
+	if tags := f.addedAnnotationTags(); !reflect.DeepEqual(tags, expected) {
+		t.Errorf("expected annotations tagged %v but got %v", expected, tags)
+	}
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned/fake"
+	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana/grafanatest"
+)
+
+// runControllers runs every controller against server until the returned func is called
+func runControllers(t *testing.T, server *grafanatest.Server) (*fake.Clientset, func()) {
+	client := fake.NewSimpleClientset()
+	kubeclient := k8sfake.NewSimpleClientset()
+
+	grafanaClient, err := grafana.NewClient(server.URL, grafana.ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	grafanaInformers := informerFactory.Grafana().V1alpha1()
+
+	controllers := []*Controller{
+		NewDashboardController(client, kubeclient, grafanaClient, grafanaInformers.Dashboards(), grafanaInformers.Folders(), grafanaInformers.Organizations(), grafanaInformers.Teams(), grafanaInformers.LibraryPanels()),
+		NewFolderController(client, kubeclient, grafanaClient, grafanaInformers.Folders(), grafanaInformers.Organizations(), grafanaInformers.Teams(), nil, nil),
+		NewDataSourceController(client, kubeclient, grafanaClient, grafanaInformers.DataSources(), grafanaInformers.Organizations()),
+		NewAlertNotificationController(client, kubeclient, grafanaClient, grafanaInformers.AlertNotifications(), grafanaInformers.Organizations()),
+	}
+
+	stopCh := make(chan struct{})
+	informerFactory.Start(stopCh)
+
+	for _, c := range controllers {
+		go c.Run(1, 100*time.Millisecond, stopCh)
+	}
+
+	return client, func() { close(stopCh) }
+}
+
+// eventually fails the test if condition does not become true
+func eventually(t *testing.T, description string, condition func() bool) {
+	err := wait.PollImmediate(10*time.Millisecond, 10*time.Second, func() (bool, error) {
+		return condition(), nil
+	})
+
+	if err != nil {
+		t.Fatalf("timed out waiting for %s", description)
+	}
+}
+
+func TestRunSyncsObjectsToGrafana(t *testing.T) {
+	server := grafanatest.NewServer()
+	defer server.Close()
+
+	client, stop := runControllers(t, server)
+	defer stop()
+
+	grafanaClient := client.GrafanaV1alpha1()
+
+	if _, err := grafanaClient.Folders(metav1.NamespaceDefault).Create(newFolder("folder", `{"title": "folder"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := grafanaClient.Dashboards(metav1.NamespaceDefault).Create(newDashboard("dashboard", `{"title": "dashboard"}`, "folder")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := grafanaClient.DataSources(metav1.NamespaceDefault).Create(newDataSource("datasource", `{"name": "prometheus", "type": "prometheus"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := grafanaClient.AlertNotifications(metav1.NamespaceDefault).Create(newAlertNotification("notification", `{"name": "email", "type": "email"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, "the dashboard to be created in its folder", func() bool {
+		folderUids := server.FolderUids()
+		dashboardUids := server.DashboardUids()
+
+		if len(folderUids) != 1 || len(dashboardUids) != 1 {
+			return false
+		}
+
+		_, folderUid, _ := server.Dashboard(dashboardUids[0])
+		return folderUid == folderUids[0]
+	})
+
+	eventually(t, "the data source and alert notification to be created", func() bool {
+		return reflect.DeepEqual(server.DataSourceNames(), []string{"prometheus"}) &&
+			reflect.DeepEqual(server.AlertNotificationNames(), []string{"email"})
+	})
+
+	if err := grafanaClient.Dashboards(metav1.NamespaceDefault).Delete("dashboard", &metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, "the dashboard to be deleted", func() bool {
+		return len(server.DashboardUids()) == 0
+	})
+
+	if len(server.FolderUids()) != 1 {
+		t.Error("expected the folder to remain")
+	}
+}
+
+func TestRunDeletesObjectsOnlyInGrafana(t *testing.T) {
+	server := grafanatest.NewServer()
+	defer server.Close()
+
+	// created outside of kubernetes
+	orphan, err := grafana.NewClient(server.URL, grafana.ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := orphan.PostDataSource(context.Background(), `{"name": "orphan", "type": "prometheus"}`, grafana.NO_ID); err != nil {
+		t.Fatal(err)
+	}
+
+	_, stop := runControllers(t, server)
+	defer stop()
+
+	eventually(t, "the orphaned data source to be deleted", func() bool {
+		return len(server.DataSourceNames()) == 0
+	})
+}
@@ -0,0 +1,374 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	clientset "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned"
+	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions/grafana/v1alpha1"
+	listers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/listers/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+const (
+	// ServiceAccountTokenKey is the key of the token in the Secret of a ServiceAccount
+	ServiceAccountTokenKey = "token"
+
+	// serviceAccountTokenIDAnnotation and serviceAccountTokenExpirationAnnotation record the token
+	// in a Secret.  They are written with the token so the Secret always knows which token it has.
+	serviceAccountTokenIDAnnotation         = "grafana.com/service-account-token-id"
+	serviceAccountTokenExpirationAnnotation = "grafana.com/service-account-token-expiration"
+
+	defaultServiceAccountRole = "Viewer"
+)
+
+// ServiceAccountSyncer is the controller implementation for ServiceAccount resources
+type ServiceAccountSyncer struct {
+	grafanaServiceAccountsLister listers.ServiceAccountLister
+	grafanaOrganizationsLister   listers.OrganizationLister
+	grafanaClient                grafana.Interface
+	grafanaclientset             clientset.Interface
+	kubeclientset                kubernetes.Interface
+}
+
+// NewServiceAccountController returns a new grafana ServiceAccount controller.  Tokens are checked
+// for rotation whenever a ServiceAccount is synced, so at least every resync period.
+func NewServiceAccountController(
+	grafanaclientset clientset.Interface,
+	kubeclientset kubernetes.Interface,
+	grafanaClient grafana.Interface,
+	grafanaServiceAccountInformer informers.ServiceAccountInformer,
+	grafanaOrganizationInformer informers.OrganizationInformer) *Controller {
+
+	syncer := &ServiceAccountSyncer{
+		grafanaServiceAccountsLister: grafanaServiceAccountInformer.Lister(),
+		grafanaOrganizationsLister:   grafanaOrganizationInformer.Lister(),
+		grafanaClient:                grafanaClient,
+		grafanaclientset:             grafanaclientset,
+		kubeclientset:                kubeclientset,
+	}
+
+	controller := NewController(grafanaServiceAccountInformer.Informer(),
+		kubeclientset,
+		syncer)
+	controller.watchOrganizations(grafanaOrganizationInformer)
+
+	return controller
+}
+
+func (s *ServiceAccountSyncer) getType() string {
+	return prometheus.TypeServiceAccount
+}
+
+func (s *ServiceAccountSyncer) getRuntimeObjectByName(name string, namespace string) (runtime.Object, error) {
+	return s.grafanaServiceAccountsLister.ServiceAccounts(namespace).Get(name)
+}
+
+// deleteObjectById deletes the service account and with it its tokens.  The Secret is deleted by
+// kubernetes because the ServiceAccount owns it.
+func (s *ServiceAccountSyncer) deleteObjectById(ctx context.Context, id string) error {
+	return s.grafanaClient.DeleteServiceAccount(ctx, id)
+}
+
+func (s *ServiceAccountSyncer) updateObject(ctx context.Context, object runtime.Object) error {
+
+	grafanaServiceAccount, ok := object.(*v1alpha1.ServiceAccount)
+	if !ok {
+		return fmt.Errorf("expected service account in but got %#v", object)
+	}
+
+	ttl, err := serviceAccountTokenTTL(grafanaServiceAccount)
+	if err != nil {
+		return err
+	}
+
+	ctx, orgID, grafanaID, err := targetOrganization(ctx,
+		s.grafanaOrganizationsLister,
+		grafanaServiceAccount.Namespace,
+		grafanaServiceAccount.Spec.OrganizationName,
+		grafanaServiceAccount.Status.GrafanaOrgID,
+		grafanaServiceAccount.Status.GrafanaID,
+		s.deleteObjectById)
+	if err != nil {
+		return err
+	}
+
+	serviceAccountJson, err := json.Marshal(map[string]interface{}{
+		"name": defaultString(grafanaServiceAccount.Spec.Name, grafanaServiceAccount.Name),
+		"role": defaultString(grafanaServiceAccount.Spec.Role, defaultServiceAccountRole),
+	})
+	if err != nil {
+		return err
+	}
+
+	id, err := s.grafanaClient.PostServiceAccount(ctx, string(serviceAccountJson), grafanaID)
+
+	if err != nil {
+		return err
+	}
+
+	grafanaServiceAccountCopy := grafanaServiceAccount.DeepCopy()
+	grafanaServiceAccountCopy.Status.GrafanaID = id
+	grafanaServiceAccountCopy.Status.GrafanaOrgID = orgID
+
+	// the id is recorded before a token is created so a failure does not create the service
+	// account again
+	grafanaServiceAccountCopy, err = s.grafanaclientset.GrafanaV1alpha1().ServiceAccounts(grafanaServiceAccount.Namespace).UpdateStatus(grafanaServiceAccountCopy)
+	if err != nil {
+		return err
+	}
+
+	secretName := defaultString(grafanaServiceAccount.Spec.SecretName, grafanaServiceAccount.Name)
+
+	secret, err := s.kubeclientset.CoreV1().Secrets(grafanaServiceAccount.Namespace).Get(secretName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		secret = nil
+	} else if err != nil {
+		return err
+	} else if !metav1.IsControlledBy(secret, grafanaServiceAccount) {
+		return fmt.Errorf("secret %s already exists and is not owned by service account %s", secretName, grafanaServiceAccount.Name)
+	}
+
+	rotate, err := s.tokenNeedsRotation(ctx, id, secret, ttl, time.Now())
+	if err != nil || !rotate {
+		return err
+	}
+
+	return s.rotateToken(ctx, grafanaServiceAccountCopy, secret, secretName, ttl)
+}
+
+// serviceAccountTokenTTL parses the token ttl of a service account.  0 is a token that never
+// expires.
+func serviceAccountTokenTTL(serviceAccount *v1alpha1.ServiceAccount) (time.Duration, error) {
+	if serviceAccount.Spec.TokenTTL == "" {
+		return 0, nil
+	}
+
+	ttl, err := time.ParseDuration(serviceAccount.Spec.TokenTTL)
+	if err != nil {
+		return 0, grafana.WrapInvalid(err, "service account %s has an invalid tokenTTL", serviceAccount.Name)
+	}
+
+	if ttl < time.Second {
+		return 0, grafana.Invalidf("service account %s has a tokenTTL shorter than a second", serviceAccount.Name)
+	}
+
+	return ttl, nil
+}
+
+// tokenNeedsRotation returns true if secret has no token of the service account id, or its token
+// does not match ttl or is in the last third of its life.  The token is looked up in grafana so a
+// revoked token is replaced.
+func (s *ServiceAccountSyncer) tokenNeedsRotation(ctx context.Context, id string, secret *corev1.Secret, ttl time.Duration, now time.Time) (bool, error) {
+	if secret == nil || len(secret.Data[ServiceAccountTokenKey]) == 0 {
+		return true, nil
+	}
+
+	tokenID := secret.Annotations[serviceAccountTokenIDAnnotation]
+	if tokenID == "" {
+		return true, nil
+	}
+
+	expirationAnnotation, expires := secret.Annotations[serviceAccountTokenExpirationAnnotation]
+
+	if expires != (ttl != 0) {
+		return true, nil
+	}
+
+	if expires {
+		expiration, err := time.Parse(time.RFC3339, expirationAnnotation)
+		if err != nil || !now.Before(expiration.Add(-ttl/3)) {
+			return true, nil
+		}
+	}
+
+	tokenIDs, err := s.grafanaClient.GetServiceAccountTokenIds(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return !containsString(tokenIDs, tokenID), nil
+}
+
+// rotateToken creates a token and writes it to the Secret secretName before the previous token is
+// revoked.  A Secret the service account wrote before under another name is deleted.
+func (s *ServiceAccountSyncer) rotateToken(ctx context.Context, serviceAccount *v1alpha1.ServiceAccount, secret *corev1.Secret, secretName string, ttl time.Duration) error {
+	now := time.Now()
+	id := serviceAccount.Status.GrafanaID
+
+	tokenID, key, err := s.grafanaClient.CreateServiceAccountToken(ctx, id, fmt.Sprintf("%s-%d", serviceAccount.Name, now.UnixNano()), int64(ttl/time.Second))
+	if err != nil {
+		return err
+	}
+
+	annotations := map[string]string{
+		serviceAccountTokenIDAnnotation: tokenID,
+	}
+
+	var expiration *metav1.Time
+	if ttl != 0 {
+		expiration = &metav1.Time{Time: now.Add(ttl).Truncate(time.Second)}
+		annotations[serviceAccountTokenExpirationAnnotation] = expiration.UTC().Format(time.RFC3339)
+	}
+
+	var previousTokenID string
+	exists := secret != nil
+
+	if !exists {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: serviceAccount.Namespace,
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(serviceAccount, v1alpha1.SchemeGroupVersion.WithKind("ServiceAccount")),
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+		}
+	} else {
+		previousTokenID = secret.Annotations[serviceAccountTokenIDAnnotation]
+		secret = secret.DeepCopy()
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+
+	delete(secret.Annotations, serviceAccountTokenExpirationAnnotation)
+	for k, v := range annotations {
+		secret.Annotations[k] = v
+	}
+
+	secret.Data = map[string][]byte{
+		ServiceAccountTokenKey: []byte(key),
+	}
+
+	if exists {
+		_, err = s.kubeclientset.CoreV1().Secrets(serviceAccount.Namespace).Update(secret)
+	} else {
+		_, err = s.kubeclientset.CoreV1().Secrets(serviceAccount.Namespace).Create(secret)
+	}
+
+	if err != nil {
+		// the key is lost so the token is revoked
+		if err := s.grafanaClient.DeleteServiceAccountToken(ctx, id, tokenID); err != nil {
+			utilruntime.HandleError(err)
+		}
+
+		return err
+	}
+
+	klog.Infof("Rotated token of service account %s/%s", serviceAccount.Namespace, serviceAccount.Name)
+
+	if previousTokenID != "" {
+		s.revokeToken(ctx, id, previousTokenID)
+	}
+
+	previousSecretName := serviceAccount.Status.SecretName
+	if previousSecretName != "" && previousSecretName != secretName {
+		s.deletePreviousSecret(ctx, serviceAccount, previousSecretName)
+	}
+
+	serviceAccountCopy := serviceAccount.DeepCopy()
+	serviceAccountCopy.Status.TokenID = tokenID
+	serviceAccountCopy.Status.SecretName = secretName
+	serviceAccountCopy.Status.TokenExpiration = expiration
+
+	_, err = s.grafanaclientset.GrafanaV1alpha1().ServiceAccounts(serviceAccount.Namespace).UpdateStatus(serviceAccountCopy)
+
+	return err
+}
+
+// revokeToken revokes a token that has been replaced.  A token that cannot be revoked is left to
+// expire.  The Secret no longer records it so it is not attempted again.
+func (s *ServiceAccountSyncer) revokeToken(ctx context.Context, id string, tokenID string) {
+	if err := s.grafanaClient.DeleteServiceAccountToken(ctx, id, tokenID); err != nil {
+		utilruntime.HandleError(fmt.Errorf("unable to revoke token %s of service account %s: %v", tokenID, id, err))
+	}
+}
+
+// deletePreviousSecret deletes a Secret the service account no longer writes its token to and
+// revokes the token in it
+func (s *ServiceAccountSyncer) deletePreviousSecret(ctx context.Context, serviceAccount *v1alpha1.ServiceAccount, secretName string) {
+	secret, err := s.kubeclientset.CoreV1().Secrets(serviceAccount.Namespace).Get(secretName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return
+	}
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	if !metav1.IsControlledBy(secret, serviceAccount) {
+		return
+	}
+
+	if tokenID := secret.Annotations[serviceAccountTokenIDAnnotation]; tokenID != "" {
+		s.revokeToken(ctx, serviceAccount.Status.GrafanaID, tokenID)
+	}
+
+	if err := s.kubeclientset.CoreV1().Secrets(serviceAccount.Namespace).Delete(secretName, &metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		utilruntime.HandleError(err)
+	}
+}
+
+func (s *ServiceAccountSyncer) getAllKubernetesObjectIDs(orgID string) ([]string, error) {
+	serviceAccounts, err := s.grafanaServiceAccountsLister.List(labels.Everything())
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+
+	for _, serviceAccount := range serviceAccounts {
+		// objects without an id may be in any organization
+		if serviceAccount.Status.GrafanaOrgID != orgID && serviceAccount.Status.GrafanaID != grafana.NO_ID {
+			continue
+		}
+
+		ids = append(ids, serviceAccount.Status.GrafanaID)
+	}
+
+	return ids, nil
+}
+
+func (s *ServiceAccountSyncer) getAllGrafanaObjectIDs(ctx context.Context) ([]string, error) {
+	return s.grafanaClient.GetAllServiceAccountIds(ctx)
+}
+
+func (s *ServiceAccountSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
+	var key string
+	var err error
+	var serviceAccount *v1alpha1.ServiceAccount
+	var ok bool
+
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	if serviceAccount, ok = obj.(*v1alpha1.ServiceAccount); !ok {
+		utilruntime.HandleError(fmt.Errorf("expected service account in workqueue but got %#v", obj))
+		return nil
+	}
+
+	item := NewWorkQueueItem(key, serviceAccount.DeepCopyObject(), serviceAccount.Status.GrafanaID, serviceAccount.Status.GrafanaOrgID)
+
+	return &item
+}
@@ -0,0 +1,323 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+)
+
+func newServiceAccount(name string, tokenTTL string) *v1alpha1.ServiceAccount {
+	serviceAccount := &v1alpha1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: newObjectMeta(name),
+		Spec: v1alpha1.ServiceAccountSpec{
+			TokenTTL: tokenTTL,
+		},
+	}
+	serviceAccount.UID = types.UID("service-account-" + name)
+
+	return serviceAccount
+}
+
+func newServiceAccountController(f *fixture) *Controller {
+	return NewServiceAccountController(f.client, f.kubeclient, f.grafanaClient,
+		f.informers.Grafana().V1alpha1().ServiceAccounts(),
+		f.informers.Grafana().V1alpha1().Organizations())
+}
+
+func (f *fixture) getServiceAccount(name string) *v1alpha1.ServiceAccount {
+	serviceAccount, err := f.client.GrafanaV1alpha1().ServiceAccounts(metav1.NamespaceDefault).Get(name, metav1.GetOptions{})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return serviceAccount
+}
+
+func (f *fixture) getSecret(name string) *corev1.Secret {
+	secret, err := f.kubeclient.CoreV1().Secrets(metav1.NamespaceDefault).Get(name, metav1.GetOptions{})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return secret
+}
+
+// syncServiceAccount syncs the service account as currently stored, like a resync would
+func (f *fixture) syncServiceAccount(c *Controller, name string) error {
+	serviceAccount := f.getServiceAccount(name)
+	f.index(serviceAccount)
+
+	return f.sync(c, newItem(serviceAccount, AddOrUpdate, serviceAccount.Status.GrafanaID, f.t))
+}
+
+func (f *fixture) serviceAccountTokenIds(id string) []string {
+	ids, err := f.grafanaClient.GetServiceAccountTokenIds(context.Background(), id)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return ids
+}
+
+func TestCreatesServiceAccountWithTokenSecret(t *testing.T) {
+	serviceAccount := newServiceAccount("ci", "720h")
+
+	f := newFixture(t, serviceAccount)
+	c := f.newController(newServiceAccountController)
+
+	if err := f.sync(c, newItem(serviceAccount, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	status := f.getServiceAccount("ci").Status
+	if status.GrafanaID == "" || status.TokenID == "" || status.SecretName != "ci" || status.TokenExpiration == nil {
+		t.Fatalf("expected the status to record the service account and its token, got %+v", status)
+	}
+
+	posted := f.grafanaClient.CallsTo("PostServiceAccount")
+	if len(posted) != 1 || posted[0].Args[0] != `{"name":"ci","role":"Viewer"}` {
+		t.Errorf("expected a viewer named ci to be posted, got %+v", posted)
+	}
+
+	created := f.grafanaClient.CallsTo("CreateServiceAccountToken")
+	if len(created) != 1 || created[0].Args[2] != "2592000" {
+		t.Errorf("expected one token living 720h to be created, got %+v", created)
+	}
+
+	secret := f.getSecret("ci")
+
+	if string(secret.Data[ServiceAccountTokenKey]) != "glsa_fake_"+status.TokenID {
+		t.Errorf("expected the secret to hold the token's key, got %q", secret.Data[ServiceAccountTokenKey])
+	}
+
+	if secret.Annotations[serviceAccountTokenIDAnnotation] != status.TokenID {
+		t.Errorf("expected the secret to record token %s, got %v", status.TokenID, secret.Annotations)
+	}
+
+	if !metav1.IsControlledBy(secret, serviceAccount) {
+		t.Errorf("expected the secret to be owned by the service account, got %+v", secret.OwnerReferences)
+	}
+
+	// a resync keeps the token
+	if err := f.syncServiceAccount(c, "ci"); err != nil {
+		t.Fatal(err)
+	}
+
+	if created := f.grafanaClient.CallsTo("CreateServiceAccountToken"); len(created) != 1 {
+		t.Errorf("expected the token to be kept, got %+v", created)
+	}
+}
+
+func TestRotatesServiceAccountTokenBeforeItExpires(t *testing.T) {
+	serviceAccount := newServiceAccount("ci", "24h")
+
+	f := newFixture(t, serviceAccount)
+	c := f.newController(newServiceAccountController)
+
+	if err := f.sync(c, newItem(serviceAccount, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := f.getServiceAccount("ci").Status
+
+	// the token enters the last third of its life
+	secret := f.getSecret("ci")
+	secret.Annotations[serviceAccountTokenExpirationAnnotation] = time.Now().Add(7 * time.Hour).UTC().Format(time.RFC3339)
+
+	if _, err := f.kubeclient.CoreV1().Secrets(metav1.NamespaceDefault).Update(secret); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.syncServiceAccount(c, "ci"); err != nil {
+		t.Fatal(err)
+	}
+
+	status := f.getServiceAccount("ci").Status
+	if status.TokenID == previous.TokenID {
+		t.Fatalf("expected the token to be rotated, got %+v", status)
+	}
+
+	if string(f.getSecret("ci").Data[ServiceAccountTokenKey]) != "glsa_fake_"+status.TokenID {
+		t.Errorf("expected the secret to hold the new token")
+	}
+
+	if ids := f.serviceAccountTokenIds(status.GrafanaID); !reflect.DeepEqual(ids, []string{status.TokenID}) {
+		t.Errorf("expected the previous token to be revoked, got tokens %v", ids)
+	}
+}
+
+func TestReplacesRevokedServiceAccountToken(t *testing.T) {
+	serviceAccount := newServiceAccount("ci", "")
+
+	f := newFixture(t, serviceAccount)
+	c := f.newController(newServiceAccountController)
+
+	if err := f.sync(c, newItem(serviceAccount, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := f.getServiceAccount("ci").Status
+
+	if previous.TokenExpiration != nil {
+		t.Errorf("expected a token that never expires, got %v", previous.TokenExpiration)
+	}
+
+	if err := f.grafanaClient.DeleteServiceAccountToken(context.Background(), previous.GrafanaID, previous.TokenID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.syncServiceAccount(c, "ci"); err != nil {
+		t.Fatal(err)
+	}
+
+	status := f.getServiceAccount("ci").Status
+	if ids := f.serviceAccountTokenIds(status.GrafanaID); status.TokenID == previous.TokenID || !reflect.DeepEqual(ids, []string{status.TokenID}) {
+		t.Errorf("expected the revoked token to be replaced, got status %+v and tokens %v", status, ids)
+	}
+}
+
+func TestMovesServiceAccountTokenToRenamedSecret(t *testing.T) {
+	serviceAccount := newServiceAccount("ci", "")
+
+	f := newFixture(t, serviceAccount)
+	c := f.newController(newServiceAccountController)
+
+	if err := f.sync(c, newItem(serviceAccount, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	renamed := f.getServiceAccount("ci")
+	renamed.Spec.SecretName = "ci-grafana"
+
+	if _, err := f.client.GrafanaV1alpha1().ServiceAccounts(metav1.NamespaceDefault).Update(renamed); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.syncServiceAccount(c, "ci"); err != nil {
+		t.Fatal(err)
+	}
+
+	status := f.getServiceAccount("ci").Status
+	if status.SecretName != "ci-grafana" || string(f.getSecret("ci-grafana").Data[ServiceAccountTokenKey]) != "glsa_fake_"+status.TokenID {
+		t.Errorf("expected the token to be written to ci-grafana, got %+v", status)
+	}
+
+	if _, err := f.kubeclient.CoreV1().Secrets(metav1.NamespaceDefault).Get("ci", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected the previous secret to be deleted")
+	}
+
+	if ids := f.serviceAccountTokenIds(status.GrafanaID); !reflect.DeepEqual(ids, []string{status.TokenID}) {
+		t.Errorf("expected the previous token to be revoked, got tokens %v", ids)
+	}
+}
+
+func TestServiceAccountDoesNotOverwriteForeignSecret(t *testing.T) {
+	serviceAccount := newServiceAccount("ci", "")
+
+	f := newFixture(t, serviceAccount)
+
+	foreign := &corev1.Secret{ObjectMeta: newObjectMeta("ci")}
+	if _, err := f.kubeclient.CoreV1().Secrets(metav1.NamespaceDefault).Create(foreign); err != nil {
+		t.Fatal(err)
+	}
+
+	c := f.newController(newServiceAccountController)
+
+	if err := f.sync(c, newItem(serviceAccount, AddOrUpdate, "", t)); err == nil {
+		t.Fatal("expected an error for a secret the service account does not own")
+	}
+
+	if created := f.grafanaClient.CallsTo("CreateServiceAccountToken"); len(created) != 0 {
+		t.Errorf("expected no token to be created, got %+v", created)
+	}
+}
+
+func TestServiceAccountRejectsInvalidTokenTTL(t *testing.T) {
+	serviceAccount := newServiceAccount("ci", "a month")
+
+	f := newFixture(t, serviceAccount)
+	c := f.newController(newServiceAccountController)
+
+	err := f.sync(c, newItem(serviceAccount, AddOrUpdate, "", t))
+	if err == nil {
+		t.Fatal("expected an error for an invalid tokenTTL")
+	}
+
+	if grafana.IsRetryable(err) {
+		t.Errorf("expected an invalid tokenTTL to not be retried: %v", err)
+	}
+
+	if posted := f.grafanaClient.CallsTo("PostServiceAccount"); len(posted) != 0 {
+		t.Errorf("expected nothing to be posted, got %+v", posted)
+	}
+}
+
+func TestDeletesServiceAccount(t *testing.T) {
+	serviceAccount := newServiceAccount("ci", "")
+
+	f := newFixture(t, serviceAccount)
+	c := f.newController(newServiceAccountController)
+
+	if err := f.sync(c, newItem(serviceAccount, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	id := f.getServiceAccount("ci").Status.GrafanaID
+
+	if err := f.sync(c, newItem(serviceAccount, Delete, id, t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.grafanaClient.GetServiceAccount(context.Background(), id); err == nil {
+		t.Errorf("expected service account %s to be deleted", id)
+	}
+}
+
+func TestResyncOnlyDeletesManagedServiceAccounts(t *testing.T) {
+	serviceAccount := newServiceAccount("ci", "")
+
+	f := newFixture(t, serviceAccount)
+	c := f.newController(newServiceAccountController)
+
+	if err := f.sync(c, newItem(serviceAccount, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	managedID := f.getServiceAccount("ci").Status.GrafanaID
+
+	// e.g. the service account the controller logs in with
+	unmanagedID, err := f.grafanaClient.PostServiceAccount(context.Background(), `{"name": "controller", "role": "Admin"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.grafanaClient.AddServiceAccountToken(context.Background(), unmanagedID, "created by hand"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the service account is deleted without its delete being handled
+	if err := f.informers.Grafana().V1alpha1().ServiceAccounts().Informer().GetIndexer().Delete(serviceAccount); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.resyncDeletedObjects(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.grafanaClient.GetServiceAccount(context.Background(), managedID); err == nil {
+		t.Errorf("expected managed service account %s to be deleted", managedID)
+	}
+
+	if _, err := f.grafanaClient.GetServiceAccount(context.Background(), unmanagedID); err != nil {
+		t.Errorf("expected service account %s created by hand to be kept: %v", unmanagedID, err)
+	}
+}
@@ -1,33 +1,28 @@
 package controllers
 
 import (
-	grafanacontroller "github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
-	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned/fake"
-	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions"
-	grafana "github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana/fake"
-	"reflect"
+	"context"
 	"testing"
-	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/util/diff"
-	core "k8s.io/client-go/testing"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned/fake"
+	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions"
+	grafana "github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana/fake"
 )
 
 var (
-	alwaysReady        = func() bool { return true }
-	noResyncPeriodFunc = func() time.Duration { return 0 }
-)
-
-const (
-	FAKE_UID = "fakeUID"
+	alwaysReady = func() bool { return true }
 )
 
-type controllerFactory func(*fixture) (*Controller, informers.SharedInformerFactory)
+type controllerFactory func(*fixture) *Controller
 
 type fixture struct {
 	t *testing.T
@@ -35,159 +30,133 @@ type fixture struct {
 	client        *fake.Clientset
 	kubeclient    *k8sfake.Clientset
 	grafanaClient *grafana.ClientFake
+	informers     informers.SharedInformerFactory
+	kubeInformers kubeinformers.SharedInformerFactory
+	recorder      *record.FakeRecorder
 
-	// Objects to put in the store.
-	grafanaDashboardLister  []*grafanacontroller.GrafanaDashboard
-	grafanaChannelLister    []*grafanacontroller.GrafanaNotificationChannel
-	grafanaDataSourceLister []*grafanacontroller.GrafanaDataSource
-
-	// Actions expected to happen on the client.
-	kubeactions       []core.Action
-	actions           []core.Action
-	grafanaPostedJson *string
-
-	// Objects from here preloaded into NewSimpleFake.
-	kubeobjects []runtime.Object
-	objects     []runtime.Object
+	// objects preloaded into the clientset and the listers
+	objects []runtime.Object
 }
 
-func newFixture(t *testing.T) *fixture {
+func newFixture(t *testing.T, objects ...runtime.Object) *fixture {
 	f := &fixture{}
 	f.t = t
-	f.objects = []runtime.Object{}
-	f.kubeobjects = []runtime.Object{}
-	f.grafanaPostedJson = nil
+	f.objects = objects
+	f.client = fake.NewSimpleClientset(objects...)
+	f.kubeclient = k8sfake.NewSimpleClientset()
+	f.grafanaClient = grafana.NewGrafanaClientFake()
+	f.informers = informers.NewSharedInformerFactory(f.client, 0)
+	f.kubeInformers = kubeinformers.NewSharedInformerFactory(f.kubeclient, 0)
+	f.recorder = record.NewFakeRecorder(100)
 
 	return f
 }
 
-func (f *fixture) runController(newController controllerFactory, item WorkQueueItem, startInformers bool, expectError bool) {
-	c, i := newController(f)
-	if startInformers {
-		stopCh := make(chan struct{})
-		defer close(stopCh)
-		i.Start(stopCh)
-	}
+// withObjects returns a fixture with objects added.  It is used when objects depend on state
+// created in the fake grafana first.  The fake grafana is kept.
+func (f *fixture) withObjects(objects ...runtime.Object) *fixture {
+	grafanaClient := f.grafanaClient
 
-	err := c.syncer.syncHandler(item)
-	if !expectError && err != nil {
-		f.t.Errorf("error syncing dashboard: %v", err)
-	} else if expectError && err == nil {
-		f.t.Error("expected error syncing dashboard, got nil")
-	}
+	f = newFixture(f.t, append(f.objects, objects...)...)
+	f.grafanaClient = grafanaClient
 
-	actions := filterInformerActions(f.client.Actions())
-	for i, action := range actions {
-		if len(f.actions) < i+1 {
-			f.t.Errorf("%d unexpected actions: %+v", len(actions)-len(f.actions), actions[i:])
-			break
-		}
-
-		expectedAction := f.actions[i]
-		checkAction(expectedAction, action, f.t)
-	}
-
-	if len(f.actions) > len(actions) {
-		f.t.Errorf("%d additional expected actions:%+v", len(f.actions)-len(actions), f.actions[len(actions):])
-	}
+	return f
+}
 
-	k8sActions := filterInformerActions(f.kubeclient.Actions())
-	for i, action := range k8sActions {
-		if len(f.kubeactions) < i+1 {
-			f.t.Errorf("%d unexpected actions: %+v", len(k8sActions)-len(f.kubeactions), k8sActions[i:])
-			break
-		}
+// newController builds a controller and fills its listers with the fixture's objects without
+// starting any informers
+func (f *fixture) newController(newController controllerFactory) *Controller {
+	c := newController(f)
 
-		expectedAction := f.kubeactions[i]
-		checkAction(expectedAction, action, f.t)
-	}
+	c.informerSynced = alwaysReady
+	c.recorder = f.recorder
 
-	if len(f.kubeactions) > len(k8sActions) {
-		f.t.Errorf("%d additional expected actions:%+v", len(f.kubeactions)-len(k8sActions), f.kubeactions[len(k8sActions):])
+	for _, obj := range f.objects {
+		f.index(obj)
 	}
 
-	// test grafana client "actions"
-	if f.grafanaPostedJson != nil &&
-		*f.grafanaPostedJson != *f.grafanaClient.PostedJson {
-
-		f.t.Errorf("Expected grafana posted json %s but found %s", *f.grafanaPostedJson, *f.grafanaClient.PostedJson)
-	}
+	return c
 }
 
-// checkAction verifies that expected and actual actions are equal and both have
-// same attached resources
-func checkAction(expected, actual core.Action, t *testing.T) {
-	if !(expected.Matches(actual.GetVerb(), actual.GetResource().Resource) && actual.GetSubresource() == expected.GetSubresource()) {
-		t.Errorf("Expected\n\t%#v\ngot\n\t%#v", expected, actual)
-		return
-	}
-
-	if reflect.TypeOf(actual) != reflect.TypeOf(expected) {
-		t.Errorf("Action has wrong type. Expected: %t. Got: %t", expected, actual)
-		return
+// index adds or replaces obj in the listers the way the informers would after a change
+func (f *fixture) index(obj runtime.Object) {
+	var err error
+
+	switch obj.(type) {
+	case *v1alpha1.Dashboard:
+		err = f.informers.Grafana().V1alpha1().Dashboards().Informer().GetIndexer().Update(obj)
+	case *v1alpha1.Folder:
+		err = f.informers.Grafana().V1alpha1().Folders().Informer().GetIndexer().Update(obj)
+	case *v1alpha1.DataSource:
+		err = f.informers.Grafana().V1alpha1().DataSources().Informer().GetIndexer().Update(obj)
+	case *v1alpha1.AlertNotification:
+		err = f.informers.Grafana().V1alpha1().AlertNotifications().Informer().GetIndexer().Update(obj)
+	case *v1alpha1.Organization:
+		err = f.informers.Grafana().V1alpha1().Organizations().Informer().GetIndexer().Update(obj)
+	case *v1alpha1.Team:
+		err = f.informers.Grafana().V1alpha1().Teams().Informer().GetIndexer().Update(obj)
+	case *v1alpha1.AlertRuleGroup:
+		err = f.informers.Grafana().V1alpha1().AlertRuleGroups().Informer().GetIndexer().Update(obj)
+	case *v1alpha1.ContactPoint:
+		err = f.informers.Grafana().V1alpha1().ContactPoints().Informer().GetIndexer().Update(obj)
+	case *v1alpha1.MuteTiming:
+		err = f.informers.Grafana().V1alpha1().MuteTimings().Informer().GetIndexer().Update(obj)
+	case *v1alpha1.NotificationPolicy:
+		err = f.informers.Grafana().V1alpha1().NotificationPolicies().Informer().GetIndexer().Update(obj)
+	case *v1alpha1.NotificationRoute:
+		err = f.informers.Grafana().V1alpha1().NotificationRoutes().Informer().GetIndexer().Update(obj)
+	case *v1alpha1.LibraryPanel:
+		err = f.informers.Grafana().V1alpha1().LibraryPanels().Informer().GetIndexer().Update(obj)
+	case *v1alpha1.Playlist:
+		err = f.informers.Grafana().V1alpha1().Playlists().Informer().GetIndexer().Update(obj)
+	case *v1alpha1.Annotation:
+		err = f.informers.Grafana().V1alpha1().Annotations().Informer().GetIndexer().Update(obj)
+	case *v1alpha1.ServiceAccount:
+		err = f.informers.Grafana().V1alpha1().ServiceAccounts().Informer().GetIndexer().Update(obj)
+	case *corev1.Secret:
+		err = f.kubeInformers.Core().V1().Secrets().Informer().GetIndexer().Update(obj)
 	}
 
-	switch a := actual.(type) {
-	case core.CreateAction:
-		e, _ := expected.(core.CreateAction)
-		expObject := e.GetObject()
-		object := a.GetObject()
-
-		if !reflect.DeepEqual(expObject, object) {
-			t.Errorf("Action %s %s has wrong object\nDiff:\n %s",
-				a.GetVerb(), a.GetResource().Resource, diff.ObjectGoPrintDiff(expObject, object))
-		}
-	case core.UpdateAction:
-		e, _ := expected.(core.UpdateAction)
-		expObject := e.GetObject()
-		object := a.GetObject()
-
-		if !reflect.DeepEqual(expObject, object) {
-			t.Errorf("Action %s %s has wrong object\nDiff:\n %s",
-				a.GetVerb(), a.GetResource().Resource, diff.ObjectGoPrintDiff(expObject, object))
-		}
-	case core.PatchAction:
-		e, _ := expected.(core.PatchAction)
-		expPatch := e.GetPatch()
-		patch := a.GetPatch()
-
-		if !reflect.DeepEqual(expPatch, patch) {
-			t.Errorf("Action %s %s has wrong patch\nDiff:\n %s",
-				a.GetVerb(), a.GetResource().Resource, diff.ObjectGoPrintDiff(expPatch, patch))
-		}
+	if err != nil {
+		f.t.Fatal(err)
 	}
 }
 
-// filterInformerActions filters list and watch actions for testing resources.
-// Since list and watch don't change resource state we can filter it to lower
-// noise level in our tests.
-func filterInformerActions(actions []core.Action) []core.Action {
+// sync runs the controller's sync handler for item
+func (f *fixture) sync(c *Controller, item WorkQueueItem) error {
+	return c.syncHandler(context.Background(), item)
+}
 
-	ret := []core.Action{}
-	for _, action := range actions {
+// process runs item through the work queue the way a worker would
+func (f *fixture) process(c *Controller, item WorkQueueItem) {
+	c.workqueue.Add(item)
+	c.processNextWorkItem(context.Background())
+}
 
-		if len(action.GetNamespace()) == 0 &&
-			(action.Matches("list", "grafanadashboards") ||
-				action.Matches("watch", "grafanadashboards") ||
-				action.Matches("list", "grafananotificationchannels") ||
-				action.Matches("watch", "grafananotificationchannels")) {
-			continue
+// expectEvent fails the test unless the next recorded event starts with prefix
+func (f *fixture) expectEvent(prefix string) {
+	select {
+	case event := <-f.recorder.Events:
+		if len(event) < len(prefix) || event[:len(prefix)] != prefix {
+			f.t.Errorf("expected event %q but got %q", prefix, event)
 		}
-		ret = append(ret, action)
+	default:
+		f.t.Errorf("expected event %q but none was recorded", prefix)
 	}
-
-	return actions
 }
 
-func (f *fixture) expectUpdateGrafanaObject(obj runtime.Object, namespace string, resource string) {
-	action := core.NewUpdateAction(schema.GroupVersionResource{Resource: resource}, namespace, obj)
-	// TODO: Until #38113 is merged, we can't use Subresource
-	//action.Subresource = "status"
-	f.actions = append(f.actions, action)
+func newObjectMeta(name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: metav1.NamespaceDefault,
+	}
 }
 
-func (f *fixture) expectGrafanaPost(json string) {
-	f.grafanaPostedJson = &json
+func newItem(obj runtime.Object, itemType WorkQueueItemType, id string, t *testing.T) WorkQueueItem {
+	item := NewWorkQueueItem(getKey(obj, t), obj, id, "")
+	item.itemType = itemType
+
+	return item
 }
 
 func getKey(obj interface{}, t *testing.T) string {
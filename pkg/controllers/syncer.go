@@ -1,19 +1,30 @@
 package controllers
 
 import (
+	"context"
+
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 type Syncer interface {
 	getType() string
 	createWorkQueueItem(obj interface{}) *WorkQueueItem
-	deleteObjectById(id string) error
+	deleteObjectById(ctx context.Context, id string) error
 
 	// support basic sync handling
 	getRuntimeObjectByName(name string, namespace string) (runtime.Object, error)
-	updateObject(object runtime.Object) error
+	updateObject(ctx context.Context, object runtime.Object) error
 
 	// support deleted objects resync
-	getAllKubernetesObjectIDs() ([]string, error)
-	getAllGrafanaObjectIDs() ([]string, error)
+	// getAllKubernetesObjectIDs returns the ids of objects created in the organization orgID
+	getAllKubernetesObjectIDs(orgID string) ([]string, error)
+	getAllGrafanaObjectIDs(ctx context.Context) ([]string, error)
+}
+
+// numericIDSyncer is implemented by syncers whose grafana objects moved from numeric ids to uids.
+// Kubernetes objects synced before the move still hold the numeric id until they are synced
+// again, so a grafana object matching either id is not deleted.
+type numericIDSyncer interface {
+	// getGrafanaObjectNumericIDs maps the ids getAllGrafanaObjectIDs returns to numeric ids
+	getGrafanaObjectNumericIDs(ctx context.Context) (map[string]string, error)
 }
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	clientset "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/clientset/versioned"
+	informers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/informers/externalversions/grafana/v1alpha1"
+	listers "github.com/joe-elliott/kubernetes-grafana-controller/pkg/client/listers/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+// TeamSyncer is the controller implementation for Team resources
+type TeamSyncer struct {
+	grafanaTeamsLister         listers.TeamLister
+	grafanaOrganizationsLister listers.OrganizationLister
+	grafanaClient              grafana.Interface
+	grafanaclientset           clientset.Interface
+
+	// teams are not marked in grafana.  only recorded ones are garbage collected
+	managedIDs *managedIDs
+}
+
+// NewTeamController returns a new grafana Team controller
+func NewTeamController(
+	grafanaclientset clientset.Interface,
+	kubeclientset kubernetes.Interface,
+	grafanaClient grafana.Interface,
+	grafanaTeamInformer informers.TeamInformer,
+	grafanaOrganizationInformer informers.OrganizationInformer) *Controller {
+
+	syncer := &TeamSyncer{
+		grafanaTeamsLister:         grafanaTeamInformer.Lister(),
+		grafanaOrganizationsLister: grafanaOrganizationInformer.Lister(),
+		grafanaClient:              grafanaClient,
+		grafanaclientset:           grafanaclientset,
+		managedIDs:                 newManagedIDs(),
+	}
+
+	controller := NewController(grafanaTeamInformer.Informer(),
+		kubeclientset,
+		syncer)
+	controller.watchOrganizations(grafanaOrganizationInformer)
+
+	return controller
+}
+
+func (s *TeamSyncer) getType() string {
+	return prometheus.TypeTeam
+}
+
+func (s *TeamSyncer) getRuntimeObjectByName(name string, namespace string) (runtime.Object, error) {
+	return s.grafanaTeamsLister.Teams(namespace).Get(name)
+}
+
+func (s *TeamSyncer) deleteObjectById(ctx context.Context, id string) error {
+	if err := s.grafanaClient.DeleteTeam(ctx, id); err != nil {
+		return err
+	}
+
+	s.managedIDs.remove(grafana.OrgID(ctx), id)
+	return nil
+}
+
+func (s *TeamSyncer) updateObject(ctx context.Context, object runtime.Object) error {
+
+	grafanaTeam, ok := object.(*v1alpha1.Team)
+	if !ok {
+		return fmt.Errorf("expected team in but got %#v", object)
+	}
+
+	ctx, orgID, grafanaID, err := targetOrganization(ctx,
+		s.grafanaOrganizationsLister,
+		grafanaTeam.Namespace,
+		grafanaTeam.Spec.OrganizationName,
+		grafanaTeam.Status.GrafanaOrgID,
+		grafanaTeam.Status.GrafanaID,
+		s.deleteObjectById)
+	if err != nil {
+		return err
+	}
+
+	id, err := s.grafanaClient.PostTeam(ctx, grafanaTeam.Spec.JSON, grafanaID)
+
+	if err != nil {
+		return err
+	}
+
+	s.managedIDs.add(orgID, id)
+
+	grafanaTeamCopy := grafanaTeam.DeepCopy()
+	grafanaTeamCopy.Status.GrafanaID = id
+	grafanaTeamCopy.Status.GrafanaOrgID = orgID
+
+	_, err = s.grafanaclientset.GrafanaV1alpha1().Teams(grafanaTeam.Namespace).UpdateStatus(grafanaTeamCopy)
+	if err != nil {
+		return err
+	}
+
+	// members are set once the team's id is recorded so a failure does not create it again
+	return s.grafanaClient.SetTeamMembers(ctx, id, grafanaTeam.Spec.Members)
+}
+
+func (s *TeamSyncer) getAllKubernetesObjectIDs(orgID string) ([]string, error) {
+	teams, err := s.grafanaTeamsLister.List(labels.Everything())
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+
+	for _, team := range teams {
+		// objects without an id may be in any organization
+		if team.Status.GrafanaOrgID != orgID && team.Status.GrafanaID != grafana.NO_ID {
+			continue
+		}
+
+		if team.Status.GrafanaID != grafana.NO_ID {
+			s.managedIDs.add(orgID, team.Status.GrafanaID)
+		}
+
+		ids = append(ids, team.Status.GrafanaID)
+	}
+
+	return ids, nil
+}
+
+// getAllGrafanaObjectIDs returns the teams the syncer created or found in the status of a
+// Team.  Teams created by hand are never deleted.
+func (s *TeamSyncer) getAllGrafanaObjectIDs(ctx context.Context) ([]string, error) {
+	ids, err := s.grafanaClient.GetAllTeamIds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.managedIDs.filter(grafana.OrgID(ctx), ids), nil
+}
+
+func (s *TeamSyncer) createWorkQueueItem(obj interface{}) *WorkQueueItem {
+	var key string
+	var err error
+	var team *v1alpha1.Team
+	var ok bool
+
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	if team, ok = obj.(*v1alpha1.Team); !ok {
+		utilruntime.HandleError(fmt.Errorf("expected team in workqueue but got %#v", obj))
+		return nil
+	}
+
+	item := NewWorkQueueItem(key, team.DeepCopyObject(), team.Status.GrafanaID, team.Status.GrafanaOrgID)
+
+	return &item
+}
@@ -0,0 +1,277 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+)
+
+func newTeam(name string, teamJson string, members ...string) *v1alpha1.Team {
+	return &v1alpha1.Team{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: newObjectMeta(name),
+		Spec: v1alpha1.TeamSpec{
+			JSON:    teamJson,
+			Members: members,
+		},
+	}
+}
+
+func newTeamController(f *fixture) *Controller {
+	return NewTeamController(f.client, f.kubeclient, f.grafanaClient,
+		f.informers.Grafana().V1alpha1().Teams(),
+		f.informers.Grafana().V1alpha1().Organizations())
+}
+
+func (f *fixture) getTeam(name string) *v1alpha1.Team {
+	team, err := f.client.GrafanaV1alpha1().Teams(metav1.NamespaceDefault).Get(name, metav1.GetOptions{})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return team
+}
+
+// createTeam creates a team in the fake grafana and returns a Team object recording its id
+func (f *fixture) createTeam(name string) *v1alpha1.Team {
+	team := newTeam(name, `{"name": "`+name+`"}`)
+
+	id, err := f.grafanaClient.PostTeam(context.Background(), team.Spec.JSON, "")
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	team.Status.GrafanaID = id
+
+	return team
+}
+
+func TestCreatesTeamWithMembers(t *testing.T) {
+	team := newTeam("test", `{"name": "test", "email": "test@example.com"}`, "alice", "bob@example.com")
+
+	f := newFixture(t, team)
+	c := f.newController(newTeamController)
+
+	if err := f.sync(c, newItem(team, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	id := f.getTeam("test").Status.GrafanaID
+	if id == "" {
+		t.Fatal("expected the team status to be updated with its grafana id")
+	}
+
+	if _, err := f.grafanaClient.GetTeam(context.Background(), id); err != nil {
+		t.Errorf("expected team in grafana: %v", err)
+	}
+
+	members := f.grafanaClient.TeamMembers(context.Background(), id)
+	if len(members) != 2 || members[0] != "alice" || members[1] != "bob@example.com" {
+		t.Errorf("expected team members to be set, got %v", members)
+	}
+}
+
+func TestTeamIdRecordedWhenMembersFail(t *testing.T) {
+	team := newTeam("test", `{"name": "test"}`, "unknown")
+
+	f := newFixture(t, team)
+	f.grafanaClient.FailNext("SetTeamMembers", &grafana.APIError{StatusCode: 500, Retryable: true})
+	c := f.newController(newTeamController)
+
+	if err := f.sync(c, newItem(team, AddOrUpdate, "", t)); err == nil {
+		t.Fatal("expected setting members to fail")
+	}
+
+	if f.getTeam("test").Status.GrafanaID == "" {
+		t.Error("expected the team id to be recorded so the team is not created again")
+	}
+}
+
+func TestFolderGrantsTeamPermissions(t *testing.T) {
+	f := newFixture(t)
+
+	team := f.createTeam("editors")
+
+	folder := newFolder("test", `{"title": "test"}`)
+	folder.Spec.Permissions = []v1alpha1.Permission{
+		{TeamName: "editors", Permission: "Edit"},
+	}
+
+	f = f.withObjects(team, folder)
+	c := f.newController(newFolderController)
+
+	if err := f.sync(c, newItem(folder, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	permissions, err := f.grafanaClient.GetFolderPermissions(context.Background(), f.getFolder("test").Status.GrafanaID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the default role permissions are kept
+	expected := []grafana.Permission{
+		{Role: "Viewer", Permission: grafana.PermissionView},
+		{Role: "Editor", Permission: grafana.PermissionEdit},
+		{TeamID: team.Status.GrafanaID, Permission: grafana.PermissionEdit},
+	}
+
+	if !samePermissions(permissions, expected) {
+		t.Errorf("expected permissions %v, got %v", expected, permissions)
+	}
+}
+
+func TestFolderRevokesRemovedTeamPermissions(t *testing.T) {
+	f := newFixture(t)
+
+	team := f.createTeam("editors")
+
+	uid, _, err := f.grafanaClient.PostFolder(context.Background(), `{"title": "test"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a team the folder did not grant is left alone
+	err = f.grafanaClient.SetFolderPermissions(context.Background(), uid, []grafana.Permission{
+		{TeamID: team.Status.GrafanaID, Permission: grafana.PermissionAdmin},
+		{TeamID: "1000", Permission: grafana.PermissionView},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the team was granted by an earlier sync and has since been removed from the spec
+	folder := newFolder("test", `{"title": "test"}`)
+	folder.Status.GrafanaID = uid
+	folder.Status.GrantedPermissions = []v1alpha1.GrantedPermission{{TeamID: team.Status.GrafanaID}}
+
+	f = f.withObjects(team, folder)
+	c := f.newController(newFolderController)
+
+	if err := f.sync(c, newItem(folder, AddOrUpdate, uid, t)); err != nil {
+		t.Fatal(err)
+	}
+
+	permissions, err := f.grafanaClient.GetFolderPermissions(context.Background(), uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []grafana.Permission{{TeamID: "1000", Permission: grafana.PermissionView}}
+	if !samePermissions(permissions, expected) {
+		t.Errorf("expected permissions %v, got %v", expected, permissions)
+	}
+
+	if granted := f.getFolder("test").Status.GrantedPermissions; len(granted) != 0 {
+		t.Errorf("expected no granted permissions to be recorded, got %v", granted)
+	}
+}
+
+func TestFolderSkipsUnchangedPermissions(t *testing.T) {
+	folder := newFolder("test", `{"title": "test"}`)
+
+	f := newFixture(t, folder)
+	c := f.newController(newFolderController)
+
+	if err := f.sync(c, newItem(folder, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := f.grafanaClient.CallsTo("GetFolderPermissions"); len(calls) != 0 {
+		t.Errorf("expected permissions to be left alone without teams, got %v", calls)
+	}
+}
+
+func TestDashboardWaitsForTeam(t *testing.T) {
+	team := newTeam("editors", `{"name": "editors"}`)
+
+	dashboard := newDashboard("test", `{"title": "test"}`, "")
+	dashboard.Spec.Permissions = []v1alpha1.Permission{
+		{TeamName: "editors", Permission: "View"},
+	}
+
+	f := newFixture(t, team, dashboard)
+	c := f.newController(newDashboardController)
+
+	if err := f.sync(c, newItem(dashboard, AddOrUpdate, "", t)); err == nil {
+		t.Fatal("expected an error granting permissions to a team not created yet")
+	}
+
+	if calls := f.grafanaClient.CallsTo("SetDashboardPermissions"); len(calls) != 0 {
+		t.Errorf("expected no permissions to be set, got %v", calls)
+	}
+}
+
+func TestDashboardGrantsTeamPermissions(t *testing.T) {
+	f := newFixture(t)
+
+	team := f.createTeam("admins")
+
+	dashboard := newDashboard("test", `{"title": "test"}`, "")
+	dashboard.Spec.Permissions = []v1alpha1.Permission{
+		{TeamName: "admins", Permission: "Admin"},
+	}
+
+	f = f.withObjects(team, dashboard)
+	c := f.newController(newDashboardController)
+
+	if err := f.sync(c, newItem(dashboard, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	permissions, err := f.grafanaClient.GetDashboardPermissions(context.Background(), f.getDashboard("test").Status.GrafanaID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, permission := range permissions {
+		if permission.TeamID == team.Status.GrafanaID && permission.Permission == grafana.PermissionAdmin {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("expected team %s to be granted admin, got %v", team.Status.GrafanaID, permissions)
+	}
+}
+
+func TestResyncOnlyDeletesManagedTeams(t *testing.T) {
+	team := newTeam("managed", `{"name": "managed"}`)
+
+	f := newFixture(t, team)
+	c := f.newController(newTeamController)
+
+	if err := f.sync(c, newItem(team, AddOrUpdate, "", t)); err != nil {
+		t.Fatal(err)
+	}
+
+	managedID := f.getTeam("managed").Status.GrafanaID
+
+	// e.g. synced from ldap
+	unmanagedID, err := f.grafanaClient.PostTeam(context.Background(), `{"name": "created by hand"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the team is deleted without its delete being handled
+	if err := f.informers.Grafana().V1alpha1().Teams().Informer().GetIndexer().Delete(team); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.resyncDeletedObjects(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.grafanaClient.GetTeam(context.Background(), managedID); err == nil {
+		t.Errorf("expected managed team %s to be deleted", managedID)
+	}
+
+	if _, err := f.grafanaClient.GetTeam(context.Background(), unmanagedID); err != nil {
+		t.Errorf("expected team %s created by hand to be kept: %v", unmanagedID, err)
+	}
+}
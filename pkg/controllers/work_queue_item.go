@@ -16,14 +16,16 @@ type WorkQueueItem struct {
 	key            string
 	originalObject runtime.Object
 	id             string
+	orgID          string
 }
 
-func NewWorkQueueItem(key string, originalObject runtime.Object, id string) WorkQueueItem {
+func NewWorkQueueItem(key string, originalObject runtime.Object, id string, orgID string) WorkQueueItem {
 	return WorkQueueItem{
 		itemType:       None,
 		key:            key,
 		originalObject: originalObject,
 		id:             id,
+		orgID:          orgID,
 	}
 }
 
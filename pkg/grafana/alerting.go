@@ -0,0 +1,521 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+// alertRuleGroup is a unified alerting rule group as the provisioning API reads and writes it
+type alertRuleGroup struct {
+	Title     string                   `json:"title"`
+	FolderUID string                   `json:"folderUid"`
+	Interval  int64                    `json:"interval"`
+	Rules     []map[string]interface{} `json:"rules"`
+}
+
+// AlertRuleGroupId returns the id of the rule group title in the folder with uid folderUid
+func AlertRuleGroupId(folderUid string, title string) string {
+	return folderUid + "/" + title
+}
+
+// parseAlertRuleGroupId splits an id returned by AlertRuleGroupId.  Folder uids can not contain
+// a slash, titles can.
+func parseAlertRuleGroupId(id string) (string, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid alert rule group id %q.  expected folderUid/title", id)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+func alertRuleGroupPath(folderUid string, title string) string {
+	return fmt.Sprintf("/api/v1/provisioning/folder/%s/rule-groups/%s", url.PathEscape(folderUid), url.PathEscape(title))
+}
+
+// PostAlertRuleGroup creates or updates a rule group in the folder with uid folderUid and returns
+// its id.  alertRuleGroupJson holds the group's title, its interval in seconds and its rules.
+// Rules are matched to the rules already in the group by title.  Rules in the group that are not
+// in alertRuleGroupJson are deleted.
+func (client *Client) PostAlertRuleGroup(ctx context.Context, folderUid string, alertRuleGroupJson string) (string, error) {
+	if err := client.requireAlertingProvisioning(ctx, "alert rule groups"); err != nil {
+		return "", err
+	}
+
+	var desired alertRuleGroup
+
+	if err := json.Unmarshal([]byte(alertRuleGroupJson), &desired); err != nil {
+		return "", err
+	}
+
+	if desired.Title == "" {
+		return "", Invalidf("alert rule group has no title")
+	}
+
+	current, err := client.getAlertRuleGroup(ctx, folderUid, desired.Title)
+	if err != nil {
+		return "", err
+	}
+
+	existing := make(map[string]string, len(current.Rules))
+	for _, rule := range current.Rules {
+		title, _ := getField(rule, "title")
+		uid, _ := getField(rule, "uid")
+
+		existing[title] = uid
+	}
+
+	rules := make([]map[string]interface{}, 0, len(desired.Rules))
+
+	for _, rule := range desired.Rules {
+		title, err := getField(rule, "title")
+		if err != nil {
+			return "", err
+		}
+
+		rule["folderUID"] = folderUid
+		rule["ruleGroup"] = desired.Title
+
+		var response map[string]interface{}
+
+		if uid, ok := existing[title]; ok {
+			delete(existing, title)
+			rule["uid"] = uid
+
+			body, err := json.Marshal(rule)
+			if err != nil {
+				return "", err
+			}
+
+			response, err = client.putGrafanaObject(ctx, string(body), "/api/v1/provisioning/alert-rules/"+uid, prometheus.TypeAlertRuleGroup)
+			if err != nil {
+				return "", err
+			}
+		} else {
+			delete(rule, "uid")
+
+			body, err := json.Marshal(rule)
+			if err != nil {
+				return "", err
+			}
+
+			response, err = client.postGrafanaObject(ctx, string(body), "/api/v1/provisioning/alert-rules", prometheus.TypeAlertRuleGroup)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		rules = append(rules, response)
+	}
+
+	for _, uid := range existing {
+		if err := client.deleteGrafanaObject(ctx, "/api/v1/provisioning/alert-rules/"+uid, prometheus.TypeAlertRuleGroup); err != nil {
+			return "", err
+		}
+	}
+
+	// a group without rules does not exist in grafana so there is no interval to set
+	if len(rules) > 0 && current.Interval != desired.Interval {
+		group := alertRuleGroup{
+			Title:     desired.Title,
+			FolderUID: folderUid,
+			Interval:  desired.Interval,
+			Rules:     rules,
+		}
+
+		body, err := json.Marshal(group)
+		if err != nil {
+			return "", err
+		}
+
+		_, err = client.putGrafanaObject(ctx, string(body), alertRuleGroupPath(folderUid, desired.Title), prometheus.TypeAlertRuleGroup)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return AlertRuleGroupId(folderUid, desired.Title), nil
+}
+
+// DeleteAlertRuleGroup deletes every rule in the group with the given id
+func (client *Client) DeleteAlertRuleGroup(ctx context.Context, id string) error {
+	folderUid, title, err := parseAlertRuleGroupId(id)
+	if err != nil {
+		return err
+	}
+
+	group, err := client.getAlertRuleGroup(ctx, folderUid, title)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range group.Rules {
+		uid, err := getField(rule, "uid")
+		if err != nil {
+			return err
+		}
+
+		if err := client.deleteGrafanaObject(ctx, "/api/v1/provisioning/alert-rules/"+uid, prometheus.TypeAlertRuleGroup); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetAlertRuleGroup returns the rule group with the given id
+func (client *Client) GetAlertRuleGroup(ctx context.Context, id string) (*Object, error) {
+	folderUid, title, err := parseAlertRuleGroupId(id)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := client.getGrafanaObject(ctx, alertRuleGroupPath(folderUid, title), prometheus.TypeAlertRuleGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	// the provisioning api does not return versions or timestamps for groups
+	return newObject(body, []byte("{}"))
+}
+
+// GetAllAlertRuleGroupIds returns the id of every rule group whose rules were all created through
+// the provisioning api.  Groups holding a rule created in the UI or from provisioning files are
+// not returned so they are left alone.  Nothing is returned if grafana is too old to list rules
+// through the provisioning api.
+func (client *Client) GetAllAlertRuleGroupIds(ctx context.Context) ([]string, error) {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !capabilities.AlertingProvisioning {
+		return nil, nil
+	}
+
+	rules, err := client.getGrafanaObjects(ctx, "/api/v1/provisioning/alert-rules", prometheus.TypeAlertRuleGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	managed := make(map[string]bool)
+
+	for _, rule := range rules {
+		folderUid, err := getField(rule, "folderUID")
+		if err != nil {
+			return nil, err
+		}
+
+		title, err := getField(rule, "ruleGroup")
+		if err != nil {
+			return nil, err
+		}
+
+		id := AlertRuleGroupId(folderUid, title)
+		if _, seen := managed[id]; !seen {
+			ids = append(ids, id)
+			managed[id] = true
+		}
+
+		if provenance, _ := rule["provenance"].(string); provenance != "api" {
+			managed[id] = false
+		}
+	}
+
+	var managedIds []string
+
+	for _, id := range ids {
+		if managed[id] {
+			managedIds = append(managedIds, id)
+		}
+	}
+
+	return managedIds, nil
+}
+
+// getAlertRuleGroup returns a rule group.  A group that does not exist is returned without rules.
+func (client *Client) getAlertRuleGroup(ctx context.Context, folderUid string, title string) (*alertRuleGroup, error) {
+	var group alertRuleGroup
+
+	body, err := client.getGrafanaObject(ctx, alertRuleGroupPath(folderUid, title), prometheus.TypeAlertRuleGroup)
+	if IsNotFound(err) {
+		return &alertRuleGroup{Title: title, FolderUID: folderUid}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(body, &group); err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+// PostContactPoint creates or updates a contact point and returns its uid.  A contact point that
+// no longer exists is created again with the same uid.
+func (client *Client) PostContactPoint(ctx context.Context, contactPointJson string, id string) (string, error) {
+	if err := client.requireAlertingProvisioning(ctx, "contact points"); err != nil {
+		return "", err
+	}
+
+	contactPointJson, err := sanitizeObject(contactPointJson, false)
+	if err != nil {
+		return "", err
+	}
+
+	if id == NO_ID {
+		return client.postContactPoint(ctx, contactPointJson)
+	}
+
+	contactPointJson, err = setUid(contactPointJson, id)
+	if err != nil {
+		return "", err
+	}
+
+	// a put only returns a message so the uid is kept
+	_, err = client.putGrafanaObject(ctx, contactPointJson, "/api/v1/provisioning/contact-points/"+id, prometheus.TypeContactPoint)
+
+	if err != nil {
+		runtime.HandleError(err)
+		prometheus.GrafanaWastedPutTotal.WithLabelValues(prometheus.TypeContactPoint).Inc()
+
+		return client.postContactPoint(ctx, contactPointJson)
+	}
+
+	return id, nil
+}
+
+func (client *Client) postContactPoint(ctx context.Context, contactPointJson string) (string, error) {
+	response, err := client.postGrafanaObject(ctx, contactPointJson, "/api/v1/provisioning/contact-points", prometheus.TypeContactPoint)
+	if err != nil {
+		return "", err
+	}
+
+	return getField(response, "uid")
+}
+
+func (client *Client) DeleteContactPoint(ctx context.Context, id string) error {
+	return client.deleteGrafanaObject(ctx, "/api/v1/provisioning/contact-points/"+id, prometheus.TypeContactPoint)
+}
+
+// GetContactPoint returns the contact point with the given uid.  Secure settings are redacted by
+// grafana.
+func (client *Client) GetContactPoint(ctx context.Context, id string) (*Object, error) {
+	contactPoints, err := client.getGrafanaObjects(ctx, "/api/v1/provisioning/contact-points", prometheus.TypeContactPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, contactPoint := range contactPoints {
+		if uid, _ := getField(contactPoint, "uid"); uid != id {
+			continue
+		}
+
+		body, err := json.Marshal(contactPoint)
+		if err != nil {
+			return nil, err
+		}
+
+		// the provisioning api does not return versions or timestamps for contact points
+		return newObject(body, []byte("{}"))
+	}
+
+	return nil, &APIError{
+		StatusCode: http.StatusNotFound,
+		Status:     fmt.Sprintf("%d %s", http.StatusNotFound, http.StatusText(http.StatusNotFound)),
+		Message:    "contact point not found",
+		Method:     http.MethodGet,
+		Endpoint:   "/api/v1/provisioning/contact-points",
+	}
+}
+
+// GetAllContactPointIds returns the uid of every contact point created through the provisioning
+// api.  Contact points created in the UI or from provisioning files, like grafana's default email
+// contact point, are not returned so they are left alone.  Nothing is returned if grafana is too
+// old for the provisioning api.
+func (client *Client) GetAllContactPointIds(ctx context.Context) ([]string, error) {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !capabilities.AlertingProvisioning {
+		return nil, nil
+	}
+
+	contactPoints, err := client.getGrafanaObjects(ctx, "/api/v1/provisioning/contact-points", prometheus.TypeContactPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+
+	for _, contactPoint := range contactPoints {
+		if provenance, _ := contactPoint["provenance"].(string); provenance != "api" {
+			continue
+		}
+
+		id, err := getField(contactPoint, "uid")
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// setUid sets the uid of an object.  Unlike setId a numeric uid is kept as a string.
+func setUid(obj string, uid string) (string, error) {
+	var jsonObject map[string]interface{}
+
+	if err := json.Unmarshal([]byte(obj), &jsonObject); err != nil {
+		return "", err
+	}
+
+	jsonObject["uid"] = uid
+
+	bytes, err := json.Marshal(jsonObject)
+	if err != nil {
+		return "", err
+	}
+
+	return string(bytes), nil
+}
+
+// NotificationPolicyId is the id of an organization's policy tree.  There is one per organization.
+const NotificationPolicyId = "policy"
+
+// PostMuteTiming creates or updates a mute timing and returns its name.  A renamed mute timing is
+// created under its new name.  The old one is left for the caller to delete once it is no longer
+// referenced by the policy tree.
+func (client *Client) PostMuteTiming(ctx context.Context, muteTimingJson string, id string) (string, error) {
+	if err := client.requireAlertingProvisioning(ctx, "mute timings"); err != nil {
+		return "", err
+	}
+
+	muteTimingJson, err := sanitizeObject(muteTimingJson, false)
+	if err != nil {
+		return "", err
+	}
+
+	var muteTiming struct {
+		Name string `json:"name"`
+	}
+
+	if err := json.Unmarshal([]byte(muteTimingJson), &muteTiming); err != nil {
+		return "", err
+	}
+
+	if muteTiming.Name == "" {
+		return "", Invalidf("mute timing has no name")
+	}
+
+	if id == muteTiming.Name {
+		// a put only returns the mute timing so the name is kept
+		_, err = client.putGrafanaObject(ctx, muteTimingJson, "/api/v1/provisioning/mute-timings/"+url.PathEscape(id), prometheus.TypeMuteTiming)
+
+		if err == nil {
+			return id, nil
+		}
+
+		runtime.HandleError(err)
+		prometheus.GrafanaWastedPutTotal.WithLabelValues(prometheus.TypeMuteTiming).Inc()
+	}
+
+	_, err = client.postGrafanaObject(ctx, muteTimingJson, "/api/v1/provisioning/mute-timings", prometheus.TypeMuteTiming)
+	if err != nil {
+		return "", err
+	}
+
+	return muteTiming.Name, nil
+}
+
+func (client *Client) DeleteMuteTiming(ctx context.Context, id string) error {
+	return client.deleteGrafanaObject(ctx, "/api/v1/provisioning/mute-timings/"+url.PathEscape(id), prometheus.TypeMuteTiming)
+}
+
+// GetMuteTiming returns the mute timing with the given name
+func (client *Client) GetMuteTiming(ctx context.Context, id string) (*Object, error) {
+	body, err := client.getGrafanaObject(ctx, "/api/v1/provisioning/mute-timings/"+url.PathEscape(id), prometheus.TypeMuteTiming)
+	if err != nil {
+		return nil, err
+	}
+
+	// the provisioning api does not return versions or timestamps for mute timings
+	return newObject(body, []byte("{}"))
+}
+
+// GetAllMuteTimingIds returns the name of every mute timing created through the provisioning
+// api.  Like contact points, others are left alone.  Nothing is returned if grafana is too old for
+// the provisioning api.
+func (client *Client) GetAllMuteTimingIds(ctx context.Context) ([]string, error) {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !capabilities.AlertingProvisioning {
+		return nil, nil
+	}
+
+	muteTimings, err := client.getGrafanaObjects(ctx, "/api/v1/provisioning/mute-timings", prometheus.TypeMuteTiming)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+
+	for _, muteTiming := range muteTimings {
+		if provenance, _ := muteTiming["provenance"].(string); provenance != "api" {
+			continue
+		}
+
+		id, err := getField(muteTiming, "name")
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// SetNotificationPolicy replaces the policy tree of the organization ctx acts on
+func (client *Client) SetNotificationPolicy(ctx context.Context, policyJson string) error {
+	if err := client.requireAlertingProvisioning(ctx, "notification policies"); err != nil {
+		return err
+	}
+
+	_, err := client.putGrafanaObject(ctx, policyJson, "/api/v1/provisioning/policies", prometheus.TypeNotificationPolicy)
+
+	return err
+}
+
+// ResetNotificationPolicy restores grafana's default policy tree in the organization ctx acts on
+func (client *Client) ResetNotificationPolicy(ctx context.Context) error {
+	return client.deleteGrafanaObject(ctx, "/api/v1/provisioning/policies", prometheus.TypeNotificationPolicy)
+}
+
+// GetNotificationPolicy returns the policy tree of the organization ctx acts on
+func (client *Client) GetNotificationPolicy(ctx context.Context) (*Object, error) {
+	body, err := client.getGrafanaObject(ctx, "/api/v1/provisioning/policies", prometheus.TypeNotificationPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	// the provisioning api does not return versions or timestamps for the policy tree
+	return newObject(body, []byte("{}"))
+}
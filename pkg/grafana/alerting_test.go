@@ -0,0 +1,336 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func newAlertingServer(version string, changes *[]string, lock *sync.Mutex) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/health":
+			w.Write([]byte(`{"version": "` + version + `"}`))
+		case "GET /api/v1/provisioning/folder/folder-uid/rule-groups/cpu alerts":
+			w.Write([]byte(`{"title": "cpu alerts", "folderUid": "folder-uid", "interval": 60, "rules": [
+				{"uid": "a", "title": "HighCPU", "folderUID": "folder-uid", "ruleGroup": "cpu alerts"},
+				{"uid": "b", "title": "Stale", "folderUID": "folder-uid", "ruleGroup": "cpu alerts"}
+			]}`))
+		case "GET /api/v1/provisioning/alert-rules":
+			w.Write([]byte(`[
+				{"uid": "a", "folderUID": "folder-uid", "ruleGroup": "cpu alerts", "provenance": "api"},
+				{"uid": "b", "folderUID": "folder-uid", "ruleGroup": "cpu alerts", "provenance": "api"},
+				{"uid": "c", "folderUID": "other", "ruleGroup": "disk", "provenance": "api"},
+				{"uid": "d", "folderUID": "other", "ruleGroup": "from the ui"},
+				{"uid": "e", "folderUID": "other", "ruleGroup": "mixed", "provenance": "api"},
+				{"uid": "f", "folderUID": "other", "ruleGroup": "mixed", "provenance": "file"}
+			]`))
+		case "GET /api/v1/provisioning/folder/folder-uid/rule-groups/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "rule group not found"}`))
+		default:
+			body, _ := ioutil.ReadAll(r.Body)
+
+			var rule map[string]interface{}
+			json.Unmarshal(body, &rule)
+
+			lock.Lock()
+			*changes = append(*changes, r.Method+" "+r.URL.Path)
+			lock.Unlock()
+
+			if rule == nil {
+				rule = map[string]interface{}{}
+			}
+			if _, ok := rule["uid"]; !ok {
+				rule["uid"] = "new"
+			}
+
+			response, _ := json.Marshal(rule)
+			w.Write(response)
+		}
+	}))
+}
+
+func TestPostAlertRuleGroup(t *testing.T) {
+	var lock sync.Mutex
+	var changes []string
+
+	server := newAlertingServer("10.0.0", &changes, &lock)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := client.PostAlertRuleGroup(context.Background(), "folder-uid", `{
+		"title": "cpu alerts",
+		"interval": 120,
+		"rules": [{"title": "HighCPU"}, {"title": "LowCPU"}]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != "folder-uid/cpu alerts" {
+		t.Errorf("expected id folder-uid/cpu alerts, got %s", id)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	sort.Strings(changes)
+	expected := []string{
+		"DELETE /api/v1/provisioning/alert-rules/b",
+		"POST /api/v1/provisioning/alert-rules",
+		"PUT /api/v1/provisioning/alert-rules/a",
+		"PUT /api/v1/provisioning/folder/folder-uid/rule-groups/cpu alerts",
+	}
+
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %q, got %q", expected, changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected, changes)
+			break
+		}
+	}
+}
+
+func TestDeleteAlertRuleGroup(t *testing.T) {
+	var lock sync.Mutex
+	var changes []string
+
+	server := newAlertingServer("10.0.0", &changes, &lock)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.DeleteAlertRuleGroup(context.Background(), "folder-uid/cpu alerts"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.DeleteAlertRuleGroup(context.Background(), "folder-uid/missing"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.DeleteAlertRuleGroup(context.Background(), "no-title"); err == nil {
+		t.Error("expected an error for an invalid id")
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	sort.Strings(changes)
+	if len(changes) != 2 || changes[0] != "DELETE /api/v1/provisioning/alert-rules/a" || changes[1] != "DELETE /api/v1/provisioning/alert-rules/b" {
+		t.Errorf("expected both rules to be deleted, got %q", changes)
+	}
+}
+
+func TestGetAllAlertRuleGroupIds(t *testing.T) {
+	var lock sync.Mutex
+	var changes []string
+
+	server := newAlertingServer("10.0.0", &changes, &lock)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := client.GetAllAlertRuleGroupIds(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 2 || ids[0] != "folder-uid/cpu alerts" || ids[1] != "other/disk" {
+		t.Errorf("expected one id per group created through the api, got %q", ids)
+	}
+}
+
+func TestAlertRuleGroupPathEscapesFolderUid(t *testing.T) {
+	expected := "/api/v1/provisioning/folder/folder%2Fuid/rule-groups/cpu%2Falerts"
+	if path := alertRuleGroupPath("folder/uid", "cpu/alerts"); path != expected {
+		t.Errorf("expected %s, got %s", expected, path)
+	}
+}
+
+func TestAlertRuleGroupsUnsupported(t *testing.T) {
+	var lock sync.Mutex
+	var changes []string
+
+	server := newAlertingServer("9.1.0", &changes, &lock)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.PostAlertRuleGroup(context.Background(), "folder-uid", `{"title": "cpu alerts"}`)
+	if _, ok := err.(*UnsupportedError); !ok {
+		t.Fatalf("expected an UnsupportedError, got %v", err)
+	}
+
+	if IsRetryable(err) {
+		t.Error("expected an unsupported grafana not to be retried")
+	}
+
+	ids, err := client.GetAllAlertRuleGroupIds(context.Background())
+	if err != nil || len(ids) != 0 {
+		t.Errorf("expected no ids from an unsupported grafana, got %q %v", ids, err)
+	}
+}
+
+func TestPostContactPointRecreatesWithSameUid(t *testing.T) {
+	var lock sync.Mutex
+	var changes []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		body, _ := ioutil.ReadAll(r.Body)
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/health":
+			w.Write([]byte(`{"version": "10.0.0"}`))
+			return
+		case "PUT /api/v1/provisioning/contact-points/deleted":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "contact point not found"}`))
+		default:
+			w.WriteHeader(http.StatusAccepted)
+			w.Write(body)
+		}
+
+		lock.Lock()
+		changes = append(changes, r.Method+" "+r.URL.Path+" "+string(body))
+		lock.Unlock()
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, err := client.PostContactPoint(context.Background(), `{"name": "slack", "type": "slack", "uid": "ignored"}`, "deleted")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if uid != "deleted" {
+		t.Errorf("expected the contact point to keep its uid, got %s", uid)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	expected := `POST /api/v1/provisioning/contact-points {"name":"slack","type":"slack","uid":"deleted"}`
+	if len(changes) != 2 || changes[1] != expected {
+		t.Errorf("expected a put followed by %q, got %q", expected, changes)
+	}
+}
+
+func TestGetAllContactPointIdsSkipsUnprovisioned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/health":
+			w.Write([]byte(`{"version": "10.0.0"}`))
+		case "/api/v1/provisioning/contact-points":
+			w.Write([]byte(`[
+				{"uid": "default", "name": "email receiver", "type": "email"},
+				{"uid": "from-file", "name": "file", "type": "email", "provenance": "file"},
+				{"uid": "managed", "name": "slack", "type": "slack", "provenance": "api"}
+			]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := client.GetAllContactPointIds(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 1 || ids[0] != "managed" {
+		t.Errorf("expected only contact points created through the api, got %q", ids)
+	}
+
+	if _, err := client.GetContactPoint(context.Background(), "missing"); !IsNotFound(err) {
+		t.Errorf("expected a missing contact point to be not found, got %v", err)
+	}
+}
+
+func TestPostMuteTimingCreatesMissingMuteTiming(t *testing.T) {
+	var lock sync.Mutex
+	var changes []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		body, _ := ioutil.ReadAll(r.Body)
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/health":
+			w.Write([]byte(`{"version": "10.0.0"}`))
+			return
+		case "PUT /api/v1/provisioning/mute-timings/weekends":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "mute timing not found"}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		}
+
+		lock.Lock()
+		changes = append(changes, r.Method+" "+r.URL.Path)
+		lock.Unlock()
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name, err := client.PostMuteTiming(context.Background(), `{"name": "weekends", "time_intervals": []}`, "weekends")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name != "weekends" {
+		t.Errorf("expected the mute timing to keep its name, got %s", name)
+	}
+
+	if _, err := client.PostMuteTiming(context.Background(), `{"time_intervals": []}`, ""); err == nil {
+		t.Error("expected an error for a mute timing without a name")
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if len(changes) != 2 || changes[1] != "POST /api/v1/provisioning/mute-timings" {
+		t.Errorf("expected a put followed by a post, got %q", changes)
+	}
+}
@@ -0,0 +1,236 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+const (
+	// annotationManagedBy marks the annotations PostAnnotation creates in their data.  Only those
+	// are listed by GetAllAnnotationIds so annotations made in the UI or by alerting are never
+	// deleted.
+	annotationManagedBy = "kubernetes-grafana-controller"
+
+	// annotationLimit is the most annotations grafana is asked to list.  The annotations api
+	// cannot be paged.
+	annotationLimit = 10000
+)
+
+// PostAnnotation creates or updates an annotation and returns its numeric id.  annotationJson has
+// the text, tags, time, timeEnd and panelId of the annotation.  It is placed on the dashboard with
+// uid dashboardUid, or is an organization wide annotation if dashboardUid is empty.  grafana
+// cannot move an annotation to another dashboard or panel so the caller deletes and recreates it
+// instead.
+func (client *Client) PostAnnotation(ctx context.Context, annotationJson string, dashboardUid string, id string) (string, error) {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var annotation map[string]interface{}
+
+	if err := json.Unmarshal([]byte(annotationJson), &annotation); err != nil {
+		return "", err
+	}
+
+	delete(annotation, "id")
+	delete(annotation, "dashboardId")
+	delete(annotation, "dashboardUID")
+
+	data, _ := annotation["data"].(map[string]interface{})
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+
+	data["managedBy"] = annotationManagedBy
+	annotation["data"] = data
+
+	if dashboardUid != "" {
+		if capabilities.AnnotationDashboardUID {
+			annotation["dashboardUID"] = dashboardUid
+		} else {
+			dashboardId, err := client.dashboardId(ctx, dashboardUid)
+			if err != nil {
+				return "", err
+			}
+
+			annotation["dashboardId"] = dashboardId
+		}
+	}
+
+	body, err := json.Marshal(annotation)
+	if err != nil {
+		return "", err
+	}
+
+	if id == NO_ID {
+		return client.postAnnotation(ctx, string(body))
+	}
+
+	// a put only returns a message so the id is kept
+	_, err = client.putGrafanaObject(ctx, string(body), "/api/annotations/"+url.PathEscape(id), prometheus.TypeAnnotation)
+
+	if IsNotFound(err) {
+		runtime.HandleError(err)
+		prometheus.GrafanaWastedPutTotal.WithLabelValues(prometheus.TypeAnnotation).Inc()
+
+		return client.postAnnotation(ctx, string(body))
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// AddAnnotation creates an organization wide annotation and returns its numeric id.  Unlike
+// PostAnnotation the annotation is not marked so it is never listed or deleted by the controller.
+// It is for recording events that are kept as history.
+func (client *Client) AddAnnotation(ctx context.Context, annotationJson string) (string, error) {
+	annotationJson, err := sanitizeObject(annotationJson, false)
+	if err != nil {
+		return "", err
+	}
+
+	return client.postAnnotation(ctx, annotationJson)
+}
+
+// Annotation is an annotation returned by FindAnnotations.  time is in epoch milliseconds.
+type Annotation struct {
+	Time int64    `json:"time"`
+	Text string   `json:"text"`
+	Tags []string `json:"tags"`
+}
+
+// FindAnnotations returns the newest limit annotations that have every tag in tags, newest first.
+// Annotations added with AddAnnotation are found as well.
+func (client *Client) FindAnnotations(ctx context.Context, tags []string, limit int) ([]Annotation, error) {
+	query := url.Values{}
+	query.Set("type", "annotation")
+	query.Set("limit", strconv.Itoa(limit))
+
+	for _, tag := range tags {
+		query.Add("tags", tag)
+	}
+
+	body, err := client.getGrafanaObject(ctx, "/api/annotations?"+query.Encode(), prometheus.TypeAnnotation)
+	if err != nil {
+		return nil, err
+	}
+
+	var annotations []Annotation
+
+	if err := json.Unmarshal(body, &annotations); err != nil {
+		return nil, err
+	}
+
+	return annotations, nil
+}
+
+func (client *Client) postAnnotation(ctx context.Context, annotationJson string) (string, error) {
+	response, err := client.postGrafanaObject(ctx, annotationJson, "/api/annotations", prometheus.TypeAnnotation)
+	if err != nil {
+		return "", err
+	}
+
+	return annotationId(response)
+}
+
+func (client *Client) DeleteAnnotation(ctx context.Context, id string) error {
+	return client.deleteGrafanaObject(ctx, "/api/annotations/"+url.PathEscape(id), prometheus.TypeAnnotation)
+}
+
+// GetAnnotation returns the annotation with the given id.  Only annotations created by
+// PostAnnotation are found.
+func (client *Client) GetAnnotation(ctx context.Context, id string) (*Object, error) {
+	annotations, err := client.getManagedAnnotations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, annotation := range annotations {
+		if annotationID, _ := annotationId(annotation); annotationID != id {
+			continue
+		}
+
+		body, err := json.Marshal(annotation)
+		if err != nil {
+			return nil, err
+		}
+
+		// annotations have no versions
+		return newObject(body, []byte("{}"))
+	}
+
+	return nil, &APIError{
+		StatusCode: http.StatusNotFound,
+		Status:     fmt.Sprintf("%d %s", http.StatusNotFound, http.StatusText(http.StatusNotFound)),
+		Message:    "annotation not found",
+		Method:     http.MethodGet,
+		Endpoint:   "/api/annotations",
+	}
+}
+
+// GetAllAnnotationIds returns the id of every annotation created by PostAnnotation
+func (client *Client) GetAllAnnotationIds(ctx context.Context) ([]string, error) {
+	annotations, err := client.getManagedAnnotations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+
+	for _, annotation := range annotations {
+		id, err := annotationId(annotation)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// getManagedAnnotations lists the annotations created by PostAnnotation.  Alert annotations are
+// left out by grafana.
+func (client *Client) getManagedAnnotations(ctx context.Context) ([]map[string]interface{}, error) {
+	path := fmt.Sprintf("/api/annotations?type=annotation&limit=%d", annotationLimit)
+
+	annotations, err := client.getGrafanaObjects(ctx, path, prometheus.TypeAnnotation)
+	if err != nil {
+		return nil, err
+	}
+
+	var managed []map[string]interface{}
+
+	for _, annotation := range annotations {
+		data, _ := annotation["data"].(map[string]interface{})
+
+		if managedBy, _ := data["managedBy"].(string); managedBy == annotationManagedBy {
+			managed = append(managed, annotation)
+		}
+	}
+
+	return managed, nil
+}
+
+// annotationId formats the numeric id of an annotation.  json numbers are decoded as floats which
+// getField would print in exponent form once ids grow large.
+func annotationId(annotation map[string]interface{}) (string, error) {
+	id, ok := annotation["id"].(float64)
+	if !ok {
+		return "", fmt.Errorf("annotation has no numeric id")
+	}
+
+	return strconv.FormatInt(int64(id), 10), nil
+}
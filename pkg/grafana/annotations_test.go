@@ -0,0 +1,173 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// newAnnotationServer lists one annotation created by the controller and one made in the UI, and
+// records the body of every change
+func newAnnotationServer(version string, changes map[string]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/health":
+			w.Write([]byte(`{"version": "` + version + `"}`))
+		case "GET /api/dashboards/uid/dashboard-uid":
+			w.Write([]byte(`{"dashboard": {"id": 7, "uid": "dashboard-uid"}}`))
+		case "GET /api/annotations":
+			w.Write([]byte(`[
+				{"id": 1234567, "text": "managed", "data": {"managedBy": "kubernetes-grafana-controller"}},
+				{"id": 2, "text": "made in the ui", "data": {}}
+			]`))
+		default:
+			body, _ := ioutil.ReadAll(r.Body)
+
+			var annotation map[string]interface{}
+			json.Unmarshal(body, &annotation)
+
+			changes[r.Method+" "+r.URL.Path] = annotation
+
+			w.Write([]byte(`{"message": "Annotation added", "id": 1234568}`))
+		}
+	}))
+}
+
+func TestPostAnnotation(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected map[string]interface{}
+	}{
+		{"9.0.0", map[string]interface{}{"dashboardUID": "dashboard-uid"}},
+		{"8.5.0", map[string]interface{}{"dashboardId": float64(7)}},
+	}
+
+	for _, test := range tests {
+		changes := make(map[string]map[string]interface{})
+
+		server := newAnnotationServer(test.version, changes)
+
+		client, err := NewClient(server.URL, ClientOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		id, err := client.PostAnnotation(context.Background(), `{"text": "upgrade", "panelId": 2}`, "dashboard-uid", NO_ID)
+		server.Close()
+
+		if err != nil {
+			t.Errorf("%s: %v", test.version, err)
+			continue
+		}
+
+		if id != "1234568" {
+			t.Errorf("%s: expected id 1234568, got %s", test.version, id)
+		}
+
+		posted, ok := changes["POST /api/annotations"]
+		if !ok {
+			t.Fatalf("%s: expected a post, got %v", test.version, changes)
+		}
+
+		for field, value := range test.expected {
+			if !reflect.DeepEqual(posted[field], value) {
+				t.Errorf("%s: expected %s %v, got %v", test.version, field, value, posted[field])
+			}
+		}
+
+		if data, _ := posted["data"].(map[string]interface{}); data["managedBy"] != annotationManagedBy {
+			t.Errorf("%s: expected the annotation to be marked, got %v", test.version, posted["data"])
+		}
+	}
+}
+
+func TestGetAllAnnotationIdsOnlyListsManagedAnnotations(t *testing.T) {
+	server := newAnnotationServer("9.0.0", make(map[string]map[string]interface{}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := client.GetAllAnnotationIds(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(ids, []string{"1234567"}) {
+		t.Errorf("expected only the managed annotation, got %v", ids)
+	}
+
+	if _, err := client.GetAnnotation(context.Background(), "2"); !IsNotFound(err) {
+		t.Errorf("expected annotations made in the ui to not be found, got %v", err)
+	}
+}
+
+func TestAddAnnotationIsNotManaged(t *testing.T) {
+	changes := make(map[string]map[string]interface{})
+
+	server := newAnnotationServer("9.0.0", changes)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := client.AddAnnotation(context.Background(), `{"text": "rollout", "tags": ["rollout"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != "1234568" {
+		t.Errorf("expected id 1234568, got %s", id)
+	}
+
+	posted, ok := changes["POST /api/annotations"]
+	if !ok {
+		t.Fatalf("expected a post, got %v", changes)
+	}
+
+	if _, ok := posted["data"]; ok {
+		t.Errorf("expected the annotation not to be marked, got %v", posted)
+	}
+}
+
+func TestFindAnnotations(t *testing.T) {
+	var query url.Values
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		query = r.URL.Query()
+		w.Write([]byte(`[{"id": 3, "time": 1500000000000, "text": "rollout", "tags": ["rollout", "name:api"]}]`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	annotations, err := client.FindAnnotations(context.Background(), []string{"rollout", "name:api"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Annotation{{Time: 1500000000000, Text: "rollout", Tags: []string{"rollout", "name:api"}}}
+	if !reflect.DeepEqual(annotations, expected) {
+		t.Errorf("expected %+v, got %+v", expected, annotations)
+	}
+
+	if !reflect.DeepEqual(query["tags"], []string{"rollout", "name:api"}) || query.Get("limit") != "1" || query.Get("type") != "annotation" {
+		t.Errorf("unexpected query %v", query)
+	}
+}
@@ -0,0 +1,53 @@
+package grafana
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// Keys read from a credentials secret.  A token is either a Grafana API key or a service
+// account token.  If token is present it takes precedence over username and password.
+const (
+	CredentialsTokenKey    = "token"
+	CredentialsUsernameKey = "username"
+	CredentialsPasswordKey = "password"
+)
+
+// Credentials builds the Authorization header sent to Grafana from a SecretSource.  The
+// source is consulted on every request so rotated credentials are picked up without a restart.
+type Credentials struct {
+	source SecretSource
+}
+
+func NewCredentials(source SecretSource) *Credentials {
+	return &Credentials{
+		source: source,
+	}
+}
+
+// Authorization returns the value of the Authorization header.  A nil Credentials returns
+// an empty string which indicates no header should be sent.
+func (c *Credentials) Authorization() (string, error) {
+	if c == nil || c.source == nil {
+		return "", nil
+	}
+
+	data, err := c.source.Data()
+	if err != nil {
+		return "", err
+	}
+
+	if token := strings.TrimSpace(string(data[CredentialsTokenKey])); token != "" {
+		return "Bearer " + token, nil
+	}
+
+	username := strings.TrimSpace(string(data[CredentialsUsernameKey]))
+	password := strings.TrimRight(string(data[CredentialsPasswordKey]), "\r\n")
+
+	if username != "" && password != "" {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)), nil
+	}
+
+	return "", errors.New("grafana credentials must contain either a token or a username and password")
+}
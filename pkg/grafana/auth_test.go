@@ -0,0 +1,187 @@
+package grafana
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+type staticSecretSource map[string][]byte
+
+func (s staticSecretSource) Data() (map[string][]byte, error) {
+	return s, nil
+}
+
+func TestCredentialsAuthorization(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          map[string][]byte
+		authorization string
+		expectError   bool
+	}{
+		{
+			name:          "token",
+			data:          map[string][]byte{CredentialsTokenKey: []byte("abc123\n")},
+			authorization: "Bearer abc123",
+		},
+		{
+			name:          "basic",
+			data:          map[string][]byte{CredentialsUsernameKey: []byte("admin"), CredentialsPasswordKey: []byte("admin")},
+			authorization: "Basic YWRtaW46YWRtaW4=",
+		},
+		{
+			name:          "token preferred",
+			data:          map[string][]byte{CredentialsTokenKey: []byte("abc123"), CredentialsUsernameKey: []byte("admin"), CredentialsPasswordKey: []byte("admin")},
+			authorization: "Bearer abc123",
+		},
+		{
+			name:        "missing password",
+			data:        map[string][]byte{CredentialsUsernameKey: []byte("admin")},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		authorization, err := NewCredentials(staticSecretSource(tt.data)).Authorization()
+
+		if tt.expectError != (err != nil) {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+
+		if authorization != tt.authorization {
+			t.Errorf("%s: expected %q but got %q", tt.name, tt.authorization, authorization)
+		}
+	}
+}
+
+func TestNilCredentials(t *testing.T) {
+	var credentials *Credentials
+
+	authorization, err := credentials.Authorization()
+
+	if err != nil || authorization != "" {
+		t.Errorf("expected no authorization but got %q, %v", authorization, err)
+	}
+}
+
+func TestDirectorySecretSourcePicksUpRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grafana-credentials")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	credentials := NewCredentials(NewDirectorySecretSource(dir))
+
+	for _, token := range []string{"first", "second"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, CredentialsTokenKey), []byte(token), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		authorization, err := credentials.Authorization()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if authorization != "Bearer "+token {
+			t.Errorf("expected token %s but got %s", token, authorization)
+		}
+	}
+}
+
+func newCredentialsSecret(data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "grafana-credentials", Namespace: "monitoring"},
+		Data:       data,
+	}
+}
+
+// newSyncedKubernetesSecretSource returns a source for monitoring/grafana-credentials whose
+// informer has synced
+func newSyncedKubernetesSecretSource(t *testing.T, kubeclient *fake.Clientset, stopCh chan struct{}) *KubernetesSecretSource {
+	source := NewKubernetesSecretSource(kubeclient, "monitoring", "grafana-credentials", stopCh)
+
+	if !cache.WaitForCacheSync(stopCh, source.secretsSynced) {
+		t.Fatal("secret informer did not sync")
+	}
+
+	return source
+}
+
+func TestKubernetesSecretSourcePicksUpRotation(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	kubeclient := fake.NewSimpleClientset(newCredentialsSecret(map[string][]byte{CredentialsTokenKey: []byte("first")}))
+	credentials := NewCredentials(newSyncedKubernetesSecretSource(t, kubeclient, stopCh))
+
+	authorization, err := credentials.Authorization()
+	if err != nil || authorization != "Bearer first" {
+		t.Fatalf("expected token first but got %q, %v", authorization, err)
+	}
+
+	rotated := newCredentialsSecret(map[string][]byte{CredentialsTokenKey: []byte("second")})
+	if _, err := kubeclient.CoreV1().Secrets("monitoring").Update(rotated); err != nil {
+		t.Fatal(err)
+	}
+
+	err = wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		authorization, err = credentials.Authorization()
+		return authorization == "Bearer second", err
+	})
+
+	if err != nil {
+		t.Errorf("expected the rotated token but got %q, %v", authorization, err)
+	}
+}
+
+func TestKubernetesSecretSourceErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret *corev1.Secret
+	}{
+		{
+			name: "missing secret",
+		},
+		{
+			name:   "missing key",
+			secret: newCredentialsSecret(map[string][]byte{CredentialsUsernameKey: []byte("admin")}),
+		},
+	}
+
+	for _, tt := range tests {
+		stopCh := make(chan struct{})
+
+		kubeclient := fake.NewSimpleClientset()
+		if tt.secret != nil {
+			kubeclient = fake.NewSimpleClientset(tt.secret)
+		}
+
+		authorization, err := NewCredentials(newSyncedKubernetesSecretSource(t, kubeclient, stopCh)).Authorization()
+		close(stopCh)
+
+		if err == nil || authorization != "" {
+			t.Errorf("%s: expected an error but got %q", tt.name, authorization)
+		}
+	}
+}
+
+func TestKubernetesSecretSourceNotSynced(t *testing.T) {
+	source := &KubernetesSecretSource{
+		namespace:     "monitoring",
+		name:          "grafana-credentials",
+		secretsSynced: func() bool { return false },
+	}
+
+	if _, err := source.Data(); err == nil {
+		t.Error("expected an error before the secret is synced")
+	}
+}
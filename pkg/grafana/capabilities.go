@@ -0,0 +1,232 @@
+package grafana
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+
+	"k8s.io/klog"
+)
+
+// Capabilities records the grafana version and the API features the client relies on.  Each
+// call uses them to pick between the numeric id routes of older grafanas and the uid based
+// routes that replace them.
+type Capabilities struct {
+	Version string
+	Major   int
+	Minor   int
+
+	// DataSourceUIDRoutes is set when data sources can be updated and deleted by uid.
+	// Data sources are then identified by uid instead of numeric id.
+	DataSourceUIDRoutes bool
+	// AlertNotificationUIDRoutes is set when alert notifications can be updated and deleted
+	// by uid.  Alert notifications are then identified by uid instead of numeric id.
+	AlertNotificationUIDRoutes bool
+	// DashboardFolderUID is set when dashboards can be placed in a folder by folderUid.
+	DashboardFolderUID bool
+	// DashboardPermissionsUID is set when dashboard permissions can be managed by uid.  Older
+	// grafanas need the dashboard's numeric id.
+	DashboardPermissionsUID bool
+	// AlertingProvisioning is set when unified alerting can be managed through the provisioning
+	// API, /api/v1/provisioning.
+	AlertingProvisioning bool
+	// LibraryPanels is set when panels can be shared between dashboards through
+	// /api/library-elements.
+	LibraryPanels bool
+	// PlaylistUIDRoutes is set when playlists are identified by uid and can hold dashboards by
+	// uid.  Playlists are not supported on older grafanas.
+	PlaylistUIDRoutes bool
+	// AnnotationDashboardUID is set when annotations can be placed on a dashboard by
+	// dashboardUID.  Older grafanas need the dashboard's numeric id.
+	AnnotationDashboardUID bool
+	// ServiceAccounts is set when service accounts and their tokens can be managed through
+	// /api/serviceaccounts.
+	ServiceAccounts bool
+}
+
+var versionRegexp = regexp.MustCompile(`^v?(\d+)\.(\d+)`)
+
+// newCapabilities derives capabilities from a grafana version string.  An unrecognized version
+// is treated as the oldest supported grafana.
+func newCapabilities(version string) *Capabilities {
+	capabilities := &Capabilities{
+		Version: version,
+	}
+
+	matches := versionRegexp.FindStringSubmatch(version)
+	if matches == nil {
+		return capabilities
+	}
+
+	capabilities.Major, _ = strconv.Atoi(matches[1])
+	capabilities.Minor, _ = strconv.Atoi(matches[2])
+
+	capabilities.AlertNotificationUIDRoutes = capabilities.atLeast(7, 0)
+	capabilities.DataSourceUIDRoutes = capabilities.atLeast(9, 0)
+	capabilities.DashboardFolderUID = capabilities.atLeast(9, 0)
+	capabilities.DashboardPermissionsUID = capabilities.atLeast(9, 0)
+	capabilities.AlertingProvisioning = capabilities.atLeast(9, 5)
+	capabilities.LibraryPanels = capabilities.atLeast(8, 0)
+	capabilities.PlaylistUIDRoutes = capabilities.atLeast(9, 1)
+	capabilities.AnnotationDashboardUID = capabilities.atLeast(9, 0)
+	capabilities.ServiceAccounts = capabilities.atLeast(9, 1)
+
+	return capabilities
+}
+
+func (c *Capabilities) atLeast(major int, minor int) bool {
+	return c.Major > major || (c.Major == major && c.Minor >= minor)
+}
+
+// Capabilities returns the capabilities of the grafana server.  They are detected on first use
+// and cached.  A failed detection is not cached so it is attempted again on the next call.
+func (client *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	client.capabilitiesLock.Lock()
+	defer client.capabilitiesLock.Unlock()
+
+	if client.capabilities != nil {
+		return client.capabilities, nil
+	}
+
+	version, err := client.detectVersion(ctx)
+	if err != nil {
+		return nil, Wrapf(err, "error detecting grafana version")
+	}
+
+	client.capabilities = newCapabilities(version)
+
+	return client.capabilities, nil
+}
+
+// detectVersion reads the version from /api/health.  Grafanas that do not report it there are
+// asked for /api/frontend/settings instead.  Only if neither endpoint exists is the version
+// reported as unknown.  Any other failure, like a rejected login, is returned so it is not cached
+// and detection is attempted again.
+func (client *Client) detectVersion(ctx context.Context) (string, error) {
+	var health struct {
+		Version string `json:"version"`
+	}
+
+	err := client.getJSON(ctx, "/api/health", &health)
+	if err != nil && !IsNotFound(err) {
+		return "", err
+	}
+
+	if err == nil && health.Version != "" {
+		return health.Version, nil
+	}
+
+	var settings struct {
+		BuildInfo struct {
+			Version string `json:"version"`
+		} `json:"buildInfo"`
+	}
+
+	err = client.getJSON(ctx, "/api/frontend/settings", &settings)
+	if err != nil && !IsNotFound(err) {
+		return "", err
+	}
+
+	if err != nil {
+		klog.Warningf("Unable to determine grafana version, assuming the oldest supported: %v", err)
+	}
+
+	return settings.BuildInfo.Version, nil
+}
+
+func (client *Client) getJSON(ctx context.Context, path string, v interface{}) error {
+	resp, err := client.request(ctx, http.MethodGet, path, "", prometheus.TypeHealth, true)
+	if err != nil {
+		return err
+	}
+
+	if !responseIsSuccess(resp) {
+		return newAPIError(resp, http.MethodGet, path)
+	}
+
+	return resp.ToJSON(v)
+}
+
+// requireAlertingProvisioning returns an UnsupportedError if grafana is too old to manage unified
+// alerting through the provisioning API
+func (client *Client) requireAlertingProvisioning(ctx context.Context, feature string) error {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !capabilities.AlertingProvisioning {
+		return &UnsupportedError{
+			Feature:  feature,
+			Version:  capabilities.Version,
+			Required: "9.5",
+		}
+	}
+
+	return nil
+}
+
+// requireLibraryPanels returns an UnsupportedError if grafana is too old for library panels
+func (client *Client) requireLibraryPanels(ctx context.Context) error {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !capabilities.LibraryPanels {
+		return &UnsupportedError{
+			Feature:  "library panels",
+			Version:  capabilities.Version,
+			Required: "8.0",
+		}
+	}
+
+	return nil
+}
+
+// requirePlaylists returns an UnsupportedError if grafana is too old for playlists by uid
+func (client *Client) requirePlaylists(ctx context.Context) error {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !capabilities.PlaylistUIDRoutes {
+		return &UnsupportedError{
+			Feature:  "playlists",
+			Version:  capabilities.Version,
+			Required: "9.1",
+		}
+	}
+
+	return nil
+}
+
+// isNumericId reports whether id is a numeric grafana id.  Objects synced before uid routes
+// were available still carry one in their status and keep using the numeric routes until the
+// next successful post replaces it with a uid.
+func isNumericId(id string) bool {
+	_, err := strconv.ParseUint(id, 10, 64)
+	return err == nil
+}
+
+// requireServiceAccounts returns an UnsupportedError if grafana is too old for service accounts
+func (client *Client) requireServiceAccounts(ctx context.Context) error {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !capabilities.ServiceAccounts {
+		return &UnsupportedError{
+			Feature:  "service accounts",
+			Version:  capabilities.Version,
+			Required: "9.1",
+		}
+	}
+
+	return nil
+}
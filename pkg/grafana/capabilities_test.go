@@ -0,0 +1,261 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestNewCapabilities(t *testing.T) {
+	tests := []struct {
+		version                    string
+		major, minor               int
+		alertNotificationUIDRoutes bool
+		dataSourceUIDRoutes        bool
+		alertingProvisioning       bool
+		libraryPanels              bool
+		playlistUIDRoutes          bool
+	}{
+		{"", 0, 0, false, false, false, false, false},
+		{"unknown", 0, 0, false, false, false, false, false},
+		{"5.4.3", 5, 4, false, false, false, false, false},
+		{"7.5.17", 7, 5, true, false, false, false, false},
+		{"8.0.0", 8, 0, true, false, false, true, false},
+		{"v9.0.0", 9, 0, true, true, false, true, false},
+		{"9.1.0", 9, 1, true, true, false, true, true},
+		{"9.5.1", 9, 5, true, true, true, true, true},
+		{"10.2.0-pre", 10, 2, true, true, true, true, true},
+	}
+
+	for _, test := range tests {
+		capabilities := newCapabilities(test.version)
+
+		if capabilities.Major != test.major || capabilities.Minor != test.minor {
+			t.Errorf("%q: expected %d.%d, got %d.%d", test.version, test.major, test.minor, capabilities.Major, capabilities.Minor)
+		}
+
+		if capabilities.AlertNotificationUIDRoutes != test.alertNotificationUIDRoutes {
+			t.Errorf("%q: expected AlertNotificationUIDRoutes %v", test.version, test.alertNotificationUIDRoutes)
+		}
+
+		if capabilities.DataSourceUIDRoutes != test.dataSourceUIDRoutes ||
+			capabilities.DashboardFolderUID != test.dataSourceUIDRoutes ||
+			capabilities.AnnotationDashboardUID != test.dataSourceUIDRoutes {
+			t.Errorf("%q: expected DataSourceUIDRoutes, DashboardFolderUID and AnnotationDashboardUID %v", test.version, test.dataSourceUIDRoutes)
+		}
+
+		if capabilities.AlertingProvisioning != test.alertingProvisioning {
+			t.Errorf("%q: expected AlertingProvisioning %v", test.version, test.alertingProvisioning)
+		}
+
+		if capabilities.LibraryPanels != test.libraryPanels {
+			t.Errorf("%q: expected LibraryPanels %v", test.version, test.libraryPanels)
+		}
+
+		if capabilities.PlaylistUIDRoutes != test.playlistUIDRoutes ||
+			capabilities.ServiceAccounts != test.playlistUIDRoutes {
+			t.Errorf("%q: expected PlaylistUIDRoutes and ServiceAccounts %v", test.version, test.playlistUIDRoutes)
+		}
+	}
+}
+
+// recordingServer serves the given version from /api/health and records every other request
+type recordingServer struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	requests []string
+	bodies   []string
+}
+
+func newRecordingServer(version string, response string) *recordingServer {
+	s := &recordingServer{}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/api/health" {
+			json.NewEncoder(w).Encode(map[string]string{"database": "ok", "version": version})
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+
+		s.lock.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.bodies = append(s.bodies, string(body))
+		s.lock.Unlock()
+
+		w.Write([]byte(response))
+	}))
+
+	return s
+}
+
+func TestDataSourceRoutes(t *testing.T) {
+	tests := []struct {
+		version  string
+		id       string
+		expected string
+	}{
+		{"8.5.0", "3", "PUT /api/datasources/3"},
+		{"9.1.0", "abc", "PUT /api/datasources/uid/abc"},
+		// numeric ids from before an upgrade keep working until replaced by a uid
+		{"9.1.0", "3", "PUT /api/datasources/3"},
+	}
+
+	for _, test := range tests {
+		server := newRecordingServer(test.version, `{"id": 3, "datasource": {"id": 3, "uid": "abc"}}`)
+
+		client, err := NewClient(server.URL, ClientOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		id, err := client.PostDataSource(context.Background(), `{"name": "test"}`, test.id)
+		server.Close()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(server.requests) != 1 || server.requests[0] != test.expected {
+			t.Errorf("%s: expected %q, got %v", test.version, test.expected, server.requests)
+		}
+
+		expectedId := "3"
+		if newCapabilities(test.version).DataSourceUIDRoutes {
+			expectedId = "abc"
+		}
+
+		if id != expectedId {
+			t.Errorf("%s: expected id %q, got %q", test.version, expectedId, id)
+		}
+	}
+}
+
+func TestDashboardFolderUid(t *testing.T) {
+	tests := []struct {
+		version  string
+		field    string
+		expected interface{}
+	}{
+		{"8.5.0", "folderId", float64(7)},
+		{"9.1.0", "folderUid", "folder"},
+	}
+
+	for _, test := range tests {
+		server := newRecordingServer(test.version, `{"uid": "dashboard"}`)
+
+		client, err := NewClient(server.URL, ClientOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = client.PostDashboardWithFolder(context.Background(), `{"title": "test"}`, "7", "folder", "dashboard")
+		server.Close()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var body map[string]interface{}
+		if err := json.Unmarshal([]byte(server.bodies[0]), &body); err != nil {
+			t.Fatal(err)
+		}
+
+		if body[test.field] != test.expected {
+			t.Errorf("%s: expected %s %v, got %v", test.version, test.field, test.expected, body)
+		}
+	}
+}
+
+func TestCapabilitiesFrontendSettingsFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/health":
+			w.Write([]byte(`{"database": "ok"}`))
+		case "/api/frontend/settings":
+			w.Write([]byte(`{"buildInfo": {"version": "6.7.4"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	capabilities, err := client.Capabilities(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if capabilities.Version != "6.7.4" {
+		t.Errorf("expected version 6.7.4, got %q", capabilities.Version)
+	}
+}
+
+func TestCapabilitiesNotCachedWhenVersionRefused(t *testing.T) {
+	authorized := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case !authorized:
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "invalid API key"}`))
+		case r.URL.Path == "/api/health":
+			w.Write([]byte(`{"version": "10.4.0"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Capabilities(context.Background()); err == nil {
+		t.Fatal("expected a rejected login to fail version detection")
+	}
+
+	authorized = true
+
+	capabilities, err := client.Capabilities(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if capabilities.Version != "10.4.0" {
+		t.Errorf("expected version 10.4.0 once grafana accepts the login, got %q", capabilities.Version)
+	}
+}
+
+func TestCapabilitiesAssumeOldestWithoutVersionEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	capabilities, err := client.Capabilities(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if capabilities.Version != "" || capabilities.DataSourceUIDRoutes {
+		t.Errorf("expected the oldest grafana to be assumed, got %+v", capabilities)
+	}
+}
@@ -0,0 +1,162 @@
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/imroc/req"
+)
+
+// APIError is returned when grafana responds with an unsuccessful status code.
+type APIError struct {
+	StatusCode int
+	Status     string
+	// Message is the message grafana returned or the raw response body if it had none
+	Message  string
+	Method   string
+	Endpoint string
+	// Retryable indicates the same request may succeed later
+	Retryable bool
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("grafana %s %s failed: %s", e.Method, e.Endpoint, e.Status)
+	}
+
+	return fmt.Sprintf("grafana %s %s failed: %s: %s", e.Method, e.Endpoint, e.Status, e.Message)
+}
+
+func newAPIError(resp *req.Resp, method string, endpoint string) *APIError {
+	response := resp.Response()
+
+	return &APIError{
+		StatusCode: response.StatusCode,
+		Status:     response.Status,
+		Message:    responseMessage(resp),
+		Method:     method,
+		Endpoint:   endpoint,
+		Retryable:  isRetryableStatus(response.StatusCode),
+	}
+}
+
+// UnsupportedError is returned when the grafana server is too old for a feature.  Retrying will
+// not help until grafana is upgraded.
+type UnsupportedError struct {
+	Feature  string
+	Version  string
+	Required string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s requires grafana %s or newer.  found %q", e.Feature, e.Required, e.Version)
+}
+
+// InvalidError is returned when the spec of an object can never be synced as written.  Retrying
+// will not help until the spec is changed.
+type InvalidError struct {
+	Message string
+	// Cause is the error that made the spec invalid, like a json syntax error, if any
+	Cause error
+}
+
+func (e *InvalidError) Error() string {
+	if e.Cause == nil {
+		return e.Message
+	}
+
+	return e.Message + ": " + e.Cause.Error()
+}
+
+// Invalidf returns an InvalidError with a formatted message
+func Invalidf(format string, args ...interface{}) error {
+	return &InvalidError{Message: fmt.Sprintf(format, args...)}
+}
+
+// WrapInvalid returns an InvalidError caused by err with a formatted message
+func WrapInvalid(err error, format string, args ...interface{}) error {
+	return &InvalidError{Message: fmt.Sprintf(format, args...), Cause: err}
+}
+
+// wrappedError adds context to an error without hiding it from IsRetryable and IsNotFound
+type wrappedError struct {
+	message string
+	cause   error
+}
+
+func (e *wrappedError) Error() string {
+	return e.message + ": " + e.cause.Error()
+}
+
+// Wrapf adds a formatted message to err.  The result is retryable if err is.
+func Wrapf(err error, format string, args ...interface{}) error {
+	return &wrappedError{message: fmt.Sprintf(format, args...), cause: err}
+}
+
+// cause returns the error wrapped by Wrapf
+func cause(err error) error {
+	for {
+		wrapped, ok := err.(*wrappedError)
+		if !ok {
+			return err
+		}
+
+		err = wrapped.cause
+	}
+}
+
+// responseMessage extracts grafana's error message from a response.  Grafana usually returns
+// {"message": "..."} but proxies in front of it may return anything.
+func responseMessage(resp *req.Resp) string {
+	body, err := resp.ToBytes()
+	if err != nil {
+		return ""
+	}
+
+	var errorBody struct {
+		Message string `json:"message"`
+	}
+
+	if err := json.Unmarshal(body, &errorBody); err == nil && errorBody.Message != "" {
+		return errorBody.Message
+	}
+
+	return string(body)
+}
+
+// isRetryableStatus returns false for client errors that will fail the same way every time
+func isRetryableStatus(statusCode int) bool {
+	switch {
+	case statusCode == http.StatusRequestTimeout,
+		statusCode == http.StatusTooManyRequests:
+		return true
+	case statusCode >= 400 && statusCode < 500:
+		return false
+	}
+
+	return true
+}
+
+// IsRetryable reports whether a failed call may succeed if it is retried.  Grafana errors are
+// classified by status code.  Invalid specs and objects that are not valid json will never
+// succeed.  Anything else, like a connection failure, is assumed to be transient.
+func IsRetryable(err error) bool {
+	switch e := cause(err).(type) {
+	case *APIError:
+		return e.Retryable
+	case *UnsupportedError, *InvalidError:
+		return false
+	case *json.SyntaxError, *json.UnmarshalTypeError:
+		return false
+	}
+
+	return true
+}
+
+// IsNotFound reports whether err is grafana reporting that an object does not exist.
+func IsNotFound(err error) bool {
+	apiError, ok := cause(err).(*APIError)
+
+	return ok && apiError.StatusCode == http.StatusNotFound
+}
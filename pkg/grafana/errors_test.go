@@ -0,0 +1,87 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/api/health" {
+			w.Write([]byte(`{"version": "10.4.0"}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message": "Dashboard title cannot be empty"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.PostDashboard(context.Background(), "{}", NO_ID)
+
+	apiError, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("expected *APIError but got %#v", err)
+	}
+
+	if apiError.StatusCode != http.StatusBadRequest ||
+		apiError.Message != "Dashboard title cannot be empty" ||
+		apiError.Method != http.MethodPost ||
+		apiError.Endpoint != "/api/dashboards/db" {
+
+		t.Errorf("unexpected api error %#v", apiError)
+	}
+
+	if IsRetryable(err) {
+		t.Error("expected a 400 to not be retryable")
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err       error
+		retryable bool
+	}{
+		{&APIError{StatusCode: 400, Retryable: isRetryableStatus(400)}, false},
+		{&APIError{StatusCode: 404, Retryable: isRetryableStatus(404)}, false},
+		{&APIError{StatusCode: 408, Retryable: isRetryableStatus(408)}, true},
+		{&APIError{StatusCode: 429, Retryable: isRetryableStatus(429)}, true},
+		{&APIError{StatusCode: 500, Retryable: isRetryableStatus(500)}, true},
+		{&APIError{StatusCode: 503, Retryable: isRetryableStatus(503)}, true},
+		{errors.New("connection refused"), true},
+		{Invalidf("interval %s must be a positive number of seconds", "1ms"), false},
+		{WrapInvalid(&json.SyntaxError{}, "query A"), false},
+		{Wrapf(Invalidf("unknown role"), "notification route default/test"), false},
+		{Wrapf(&APIError{StatusCode: 500, Retryable: true}, "notification route default/test"), true},
+	}
+
+	for _, tt := range tests {
+		if IsRetryable(tt.err) != tt.retryable {
+			t.Errorf("expected IsRetryable(%v) to be %t", tt.err, tt.retryable)
+		}
+	}
+
+	_, err := sanitizeObject("{ not json", false)
+	if IsRetryable(err) {
+		t.Errorf("expected invalid json to not be retryable: %v", err)
+	}
+}
+
+func TestIsNotFoundSeesWrappedErrors(t *testing.T) {
+	err := Wrapf(&APIError{StatusCode: http.StatusNotFound}, "contact point default/test")
+
+	if !IsNotFound(err) {
+		t.Errorf("expected a wrapped 404 to be not found: %v", err)
+	}
+}
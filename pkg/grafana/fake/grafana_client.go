@@ -1,81 +1,1793 @@
 package grafana
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+)
+
+// Call is a single call made against a ClientFake
+type Call struct {
+	Method string
+	Args   []string
+	Err    error
+}
+
+type fakeObject struct {
+	id        string
+	uid       string
+	folderUid string
+	model     map[string]interface{}
+	version   int
+	created   time.Time
+	updated   time.Time
+}
+
+// fakeOrg holds the objects of one organization
+type fakeOrg struct {
+	dashboards         map[string]*fakeObject
+	folders            map[string]*fakeObject
+	dataSources        map[string]*fakeObject
+	alertNotifications map[string]*fakeObject
+	teams              map[string]*fakeObject
+	alertRuleGroups    map[string]*fakeObject
+	contactPoints      map[string]*fakeObject
+	muteTimings        map[string]*fakeObject
+	libraryPanels      map[string]*fakeObject
+	playlists          map[string]*fakeObject
+	annotations        map[string]*fakeObject
+	serviceAccounts    map[string]*fakeObject
+
+	teamMembers          map[string][]string
+	userIds              map[string]string
+	folderPermissions    map[string][]grafana.Permission
+	dashboardPermissions map[string][]grafana.Permission
+
+	// policy is the policy tree.  nil is grafana's default tree
+	policy map[string]interface{}
+
+	// addedAnnotations are the json of annotations added with AddAnnotation.  Like grafana's
+	// unmarked annotations they are never listed.
+	addedAnnotations []string
+
+	// serviceAccountTokens are the tokens of each service account by token id
+	serviceAccountTokens map[string]map[string]*fakeToken
+}
+
+// fakeToken is a service account token
+type fakeToken struct {
+	name          string
+	key           string
+	secondsToLive int64
+}
+
+// ClientFake is an in memory grafana.  It stores dashboards, folders, library panels and playlists
+// by uid, data sources, alert notifications, annotations, organizations, teams and service
+// accounts by numeric id
+// the way an older grafana does, contact points by uid, mute timings by name and alert rule groups by
+// folderUid/title.  Objects are
+// kept per organization as selected by grafana.WithOrgID.  Errors can be injected per method and
+// every call is recorded.
 type ClientFake struct {
-	address string
-	fakeID  string
+	lock sync.Mutex
 
-	PostedJson *string
+	nextId        int
+	orgs          map[string]*fakeOrg
+	organizations map[string]*fakeObject
+
+	nextFaults   map[string][]error
+	alwaysFaults map[string]error
+	calls        []Call
 }
 
-func NewGrafanaClientFake(address string, fakeID string) *ClientFake {
+var _ grafana.Interface = &ClientFake{}
+
+func NewGrafanaClientFake() *ClientFake {
 
 	client := &ClientFake{
-		address:    address,
-		fakeID:     fakeID,
-		PostedJson: nil,
+		nextId:        1,
+		orgs:          make(map[string]*fakeOrg),
+		organizations: make(map[string]*fakeObject),
+		nextFaults:    make(map[string][]error),
+		alwaysFaults:  make(map[string]error),
 	}
 
 	return client
 }
 
-func (client *ClientFake) PostDashboard(json string, uid string) (string, error) {
-	client.PostedJson = &json
+// FailNext makes the next calls to method return errs in order.  method is the name of a
+// grafana.Interface method, e.g. "PostDashboard".
+func (client *ClientFake) FailNext(method string, errs ...error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
 
-	return client.fakeID, nil
+	client.nextFaults[method] = append(client.nextFaults[method], errs...)
 }
 
-func (client *ClientFake) PostDashboardWithFolder(json string, folderId string, uid string) (string, error) {
-	client.PostedJson = &json
+// FailAlways makes every call to method return err.  A nil err clears the fault.
+func (client *ClientFake) FailAlways(method string, err error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
 
-	return client.fakeID, nil
+	if err == nil {
+		delete(client.alwaysFaults, method)
+		return
+	}
+
+	client.alwaysFaults[method] = err
 }
 
-func (client *ClientFake) DeleteDashboard(id string) error {
-	return nil
+// Calls returns every call made so far in order
+func (client *ClientFake) Calls() []Call {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	return append([]Call(nil), client.calls...)
 }
 
-func (client *ClientFake) GetAllDashboardIds() ([]string, error) {
-	return nil, nil
+// CallsTo returns the calls made to method so far in order
+func (client *ClientFake) CallsTo(method string) []Call {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	var calls []Call
+	for _, call := range client.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
 }
 
-func (client *ClientFake) PostAlertNotification(json string, id string) (string, error) {
-	client.PostedJson = &json
+// DashboardFolderUid returns the uid of the folder a dashboard was posted to in the organization
+// selected by ctx.  Dashboards in the general folder return "".
+func (client *ClientFake) DashboardFolderUid(ctx context.Context, uid string) (string, bool) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	dashboard, ok := client.org(ctx).dashboards[uid]
+	if !ok {
+		return "", false
+	}
 
-	return client.fakeID, nil
+	return dashboard.folderUid, true
 }
 
-func (client *ClientFake) DeleteAlertNotification(id string) error {
-	return nil
+func (client *ClientFake) PostDashboard(ctx context.Context, json string, uid string) (string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	postedUid, err := client.postDashboard(ctx, "PostDashboard", json, "0", "", uid)
+	client.record("PostDashboard", err, json, uid)
+
+	return postedUid, err
 }
 
-func (client *ClientFake) PostDataSource(json string, id string) (string, error) {
-	client.PostedJson = &json
+func (client *ClientFake) PostDashboardWithFolder(ctx context.Context, json string, folderId string, folderUid string, uid string) (string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
 
-	return client.fakeID, nil
+	postedUid, err := client.postDashboard(ctx, "PostDashboardWithFolder", json, folderId, folderUid, uid)
+	client.record("PostDashboardWithFolder", err, json, folderId, folderUid, uid)
+
+	return postedUid, err
 }
 
-func (client *ClientFake) DeleteDataSource(id string) error {
-	return nil
+func (client *ClientFake) postDashboard(ctx context.Context, method string, json string, folderId string, folderUid string, uid string) (string, error) {
+	if err := client.fault(ctx, method); err != nil {
+		return "", err
+	}
+
+	model, err := parseModel(json)
+	if err != nil {
+		return "", err
+	}
+
+	org := client.org(ctx)
+
+	folderUid, err = folderUidOf(org, folderId, folderUid, "/api/dashboards/db")
+	if err != nil {
+		return "", err
+	}
+
+	if uid == grafana.NO_ID {
+		uid = stringField(model, "uid")
+	}
+
+	dashboard, ok := org.dashboards[uid]
+	if !ok {
+		dashboard = client.newObject()
+		if uid != grafana.NO_ID {
+			dashboard.uid = uid
+		}
+
+		org.dashboards[dashboard.uid] = dashboard
+	}
+
+	dashboard.folderUid = folderUid
+	client.update(dashboard, model)
+
+	return dashboard.uid, nil
+}
+
+func (client *ClientFake) DeleteDashboard(ctx context.Context, id string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "DeleteDashboard")
+	if err == nil {
+		delete(client.org(ctx).dashboards, id)
+	}
+	client.record("DeleteDashboard", err, id)
+
+	return err
 }
 
-func (client *ClientFake) GetAllDatasourceIds() ([]string, error) {
-	return nil, nil
+func (client *ClientFake) GetDashboard(ctx context.Context, id string) (*grafana.Object, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	object, err := client.get(ctx, "GetDashboard", client.org(ctx).dashboards, id, "/api/dashboards/uid/")
+	client.record("GetDashboard", err, id)
+
+	return object, err
 }
 
-func (client *ClientFake) GetAllAlertNotificationIds() ([]string, error) {
-	return nil, nil
+func (client *ClientFake) GetAllDashboardIds(ctx context.Context) ([]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids, err := client.getAllIds(ctx, "GetAllDashboardIds", client.org(ctx).dashboards)
+	client.record("GetAllDashboardIds", err)
+
+	return ids, err
 }
 
-func (client *ClientFake) PostFolder(json string, id string) (string, string, error) {
-	client.PostedJson = &json
+func (client *ClientFake) PostAlertNotification(ctx context.Context, json string, id string) (string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
 
-	return client.fakeID, 0, nil
+	postedId, err := client.postById(ctx, "PostAlertNotification", client.org(ctx).alertNotifications, json, id, "/api/alert-notifications")
+	client.record("PostAlertNotification", err, json, id)
+
+	return postedId, err
 }
 
-func (client *ClientFake) DeleteFolder(id string) error {
-	return nil
+func (client *ClientFake) DeleteAlertNotification(ctx context.Context, id string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "DeleteAlertNotification")
+	if err == nil {
+		delete(client.org(ctx).alertNotifications, id)
+	}
+	client.record("DeleteAlertNotification", err, id)
+
+	return err
+}
+
+func (client *ClientFake) GetAlertNotification(ctx context.Context, id string) (*grafana.Object, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	object, err := client.get(ctx, "GetAlertNotification", client.org(ctx).alertNotifications, id, "/api/alert-notifications/")
+	client.record("GetAlertNotification", err, id)
+
+	return object, err
+}
+
+func (client *ClientFake) GetAllAlertNotificationIds(ctx context.Context) ([]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids, err := client.getAllIds(ctx, "GetAllAlertNotificationIds", client.org(ctx).alertNotifications)
+	client.record("GetAllAlertNotificationIds", err)
+
+	return ids, err
+}
+
+// GetAlertNotificationNumericIds maps every id to itself because the fake keeps alert notifications by numeric id
+func (client *ClientFake) GetAlertNotificationNumericIds(ctx context.Context) (map[string]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids, err := client.getAllIds(ctx, "GetAlertNotificationNumericIds", client.org(ctx).alertNotifications)
+	client.record("GetAlertNotificationNumericIds", err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	numericIds := make(map[string]string, len(ids))
+	for _, id := range ids {
+		numericIds[id] = id
+	}
+
+	return numericIds, nil
+}
+
+func (client *ClientFake) PostDataSource(ctx context.Context, json string, id string) (string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	postedId, err := client.postById(ctx, "PostDataSource", client.org(ctx).dataSources, json, id, "/api/datasources")
+	client.record("PostDataSource", err, json, id)
+
+	return postedId, err
+}
+
+func (client *ClientFake) DeleteDataSource(ctx context.Context, id string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "DeleteDataSource")
+	if err == nil {
+		delete(client.org(ctx).dataSources, id)
+	}
+	client.record("DeleteDataSource", err, id)
+
+	return err
+}
+
+func (client *ClientFake) GetDataSource(ctx context.Context, id string) (*grafana.Object, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	object, err := client.get(ctx, "GetDataSource", client.org(ctx).dataSources, id, "/api/datasources/")
+	client.record("GetDataSource", err, id)
+
+	return object, err
+}
+
+func (client *ClientFake) GetAllDataSourceIds(ctx context.Context) ([]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids, err := client.getAllIds(ctx, "GetAllDataSourceIds", client.org(ctx).dataSources)
+	client.record("GetAllDataSourceIds", err)
+
+	return ids, err
+}
+
+// GetDataSourceNumericIds maps every id to itself because the fake keeps data sources by numeric id
+func (client *ClientFake) GetDataSourceNumericIds(ctx context.Context) (map[string]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids, err := client.getAllIds(ctx, "GetDataSourceNumericIds", client.org(ctx).dataSources)
+	client.record("GetDataSourceNumericIds", err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	numericIds := make(map[string]string, len(ids))
+	for _, id := range ids {
+		numericIds[id] = id
+	}
+
+	return numericIds, nil
+}
+
+func (client *ClientFake) PostFolder(ctx context.Context, json string, id string) (string, string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	folder, err := client.postFolder(ctx, json, id)
+	client.record("PostFolder", err, json, id)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return folder.uid, folder.id, nil
+}
+
+func (client *ClientFake) postFolder(ctx context.Context, json string, uid string) (*fakeObject, error) {
+	if err := client.fault(ctx, "PostFolder"); err != nil {
+		return nil, err
+	}
+
+	model, err := parseModel(json)
+	if err != nil {
+		return nil, err
+	}
+
+	org := client.org(ctx)
+
+	folder, ok := org.folders[uid]
+	if !ok {
+		folder = client.newObject()
+		if modelUid := stringField(model, "uid"); modelUid != "" {
+			folder.uid = modelUid
+		}
+
+		if _, exists := org.folders[folder.uid]; exists {
+			return nil, newAPIError(http.StatusConflict, http.MethodPost, "/api/folders", "a folder with the same uid already exists")
+		}
+
+		org.folders[folder.uid] = folder
+	}
+
+	client.update(folder, model)
+
+	return folder, nil
+}
+
+func (client *ClientFake) DeleteFolder(ctx context.Context, id string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "DeleteFolder")
+	if err == nil {
+		org := client.org(ctx)
+		delete(org.folders, id)
+
+		// grafana deletes the dashboards and alert rules in a folder with it
+		for uid, dashboard := range org.dashboards {
+			if dashboard.folderUid == id {
+				delete(org.dashboards, uid)
+			}
+		}
+		for groupId, group := range org.alertRuleGroups {
+			if group.folderUid == id {
+				delete(org.alertRuleGroups, groupId)
+			}
+		}
+	}
+	client.record("DeleteFolder", err, id)
+
+	return err
+}
+
+func (client *ClientFake) GetFolder(ctx context.Context, id string) (*grafana.Object, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	object, err := client.get(ctx, "GetFolder", client.org(ctx).folders, id, "/api/folders/")
+	client.record("GetFolder", err, id)
+
+	return object, err
+}
+
+func (client *ClientFake) GetAllFolderIds(ctx context.Context) ([]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids, err := client.getAllIds(ctx, "GetAllFolderIds", client.org(ctx).folders)
+	client.record("GetAllFolderIds", err)
+
+	return ids, err
+}
+
+func (client *ClientFake) PostOrganization(ctx context.Context, json string, id string) (string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	postedId, err := client.postById(ctx, "PostOrganization", client.organizations, json, id, "/api/orgs")
+	client.record("PostOrganization", err, json, id)
+
+	return postedId, err
+}
+
+func (client *ClientFake) DeleteOrganization(ctx context.Context, id string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "DeleteOrganization")
+	if err == nil {
+		delete(client.organizations, id)
+		delete(client.orgs, id)
+	}
+	client.record("DeleteOrganization", err, id)
+
+	return err
+}
+
+func (client *ClientFake) GetOrganization(ctx context.Context, id string) (*grafana.Object, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	object, err := client.get(ctx, "GetOrganization", client.organizations, id, "/api/orgs/")
+	client.record("GetOrganization", err, id)
+
+	return object, err
+}
+
+func (client *ClientFake) GetAllOrganizationIds(ctx context.Context) ([]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids, err := client.getAllIds(ctx, "GetAllOrganizationIds", client.organizations)
+	client.record("GetAllOrganizationIds", err)
+
+	return ids, err
+}
+
+func (client *ClientFake) PostTeam(ctx context.Context, json string, id string) (string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	postedId, err := client.postById(ctx, "PostTeam", client.org(ctx).teams, json, id, "/api/teams")
+	client.record("PostTeam", err, json, id)
+
+	return postedId, err
+}
+
+func (client *ClientFake) DeleteTeam(ctx context.Context, id string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "DeleteTeam")
+	if err == nil {
+		org := client.org(ctx)
+
+		// like grafana, deleting a team removes it from every acl
+		delete(org.teams, id)
+		delete(org.teamMembers, id)
+		removeTeamPermissions(org.folderPermissions, id)
+		removeTeamPermissions(org.dashboardPermissions, id)
+	}
+	client.record("DeleteTeam", err, id)
+
+	return err
+}
+
+func (client *ClientFake) GetTeam(ctx context.Context, id string) (*grafana.Object, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	object, err := client.get(ctx, "GetTeam", client.org(ctx).teams, id, "/api/teams/")
+	client.record("GetTeam", err, id)
+
+	return object, err
+}
+
+func (client *ClientFake) GetAllTeamIds(ctx context.Context) ([]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids, err := client.getAllIds(ctx, "GetAllTeamIds", client.org(ctx).teams)
+	client.record("GetAllTeamIds", err)
+
+	return ids, err
+}
+
+// SetTeamMembers stores members as given.  The fake has no users so any login or email is
+// accepted.
+func (client *ClientFake) SetTeamMembers(ctx context.Context, id string, members []string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "SetTeamMembers")
+	if err == nil {
+		org := client.org(ctx)
+
+		if _, ok := org.teams[id]; !ok {
+			err = newAPIError(http.StatusNotFound, http.MethodGet, "/api/teams/"+id+"/members", "team not found")
+		} else {
+			org.teamMembers[id] = append([]string(nil), members...)
+		}
+	}
+	client.record("SetTeamMembers", err, append([]string{id}, members...)...)
+
+	return err
+}
+
+// TeamMembers returns the members last set on a team in the organization selected by ctx
+func (client *ClientFake) TeamMembers(ctx context.Context, id string) []string {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	return append([]string(nil), client.org(ctx).teamMembers[id]...)
+}
+
+// AddUser adds a user to the organization selected by ctx and returns its id
+func (client *ClientFake) AddUser(ctx context.Context, login string, email string) string {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	id := strconv.Itoa(client.nextId)
+	client.nextId++
+
+	org := client.org(ctx)
+	org.userIds[strings.ToLower(login)] = id
+	org.userIds[strings.ToLower(email)] = id
+
+	return id
+}
+
+func (client *ClientFake) GetOrgUserIds(ctx context.Context) (map[string]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "GetOrgUserIds")
+	client.record("GetOrgUserIds", err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	userIds := make(map[string]string)
+	for key, id := range client.org(ctx).userIds {
+		userIds[key] = id
+	}
+
+	return userIds, nil
+}
+
+func (client *ClientFake) GetFolderPermissions(ctx context.Context, uid string) ([]grafana.Permission, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	org := client.org(ctx)
+
+	permissions, err := client.getPermissions(ctx, "GetFolderPermissions", org.folders, org.folderPermissions, uid, "/api/folders/")
+	client.record("GetFolderPermissions", err, uid)
+
+	return permissions, err
 }
 
-func (client *ClientFake) GetAllFolderIds() ([]string, error) {
-	return nil, nil
+func (client *ClientFake) SetFolderPermissions(ctx context.Context, uid string, permissions []grafana.Permission) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	org := client.org(ctx)
+
+	err := client.setPermissions(ctx, "SetFolderPermissions", org.folders, org.folderPermissions, uid, permissions, "/api/folders/")
+	client.record("SetFolderPermissions", err, uid)
+
+	return err
+}
+
+func (client *ClientFake) GetDashboardPermissions(ctx context.Context, uid string) ([]grafana.Permission, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	org := client.org(ctx)
+
+	permissions, err := client.getPermissions(ctx, "GetDashboardPermissions", org.dashboards, org.dashboardPermissions, uid, "/api/dashboards/uid/")
+	client.record("GetDashboardPermissions", err, uid)
+
+	return permissions, err
+}
+
+func (client *ClientFake) SetDashboardPermissions(ctx context.Context, uid string, permissions []grafana.Permission) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	org := client.org(ctx)
+
+	err := client.setPermissions(ctx, "SetDashboardPermissions", org.dashboards, org.dashboardPermissions, uid, permissions, "/api/dashboards/uid/")
+	client.record("SetDashboardPermissions", err, uid)
+
+	return err
+}
+
+// PostAlertRuleGroup stores a rule group in an existing folder.  Groups without rules are not
+// kept, like in grafana.
+func (client *ClientFake) PostAlertRuleGroup(ctx context.Context, folderUid string, json string) (string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	id, err := client.postAlertRuleGroup(ctx, folderUid, json)
+	client.record("PostAlertRuleGroup", err, folderUid, json)
+
+	return id, err
+}
+
+func (client *ClientFake) postAlertRuleGroup(ctx context.Context, folderUid string, json string) (string, error) {
+	if err := client.fault(ctx, "PostAlertRuleGroup"); err != nil {
+		return "", err
+	}
+
+	model, err := parseModel(json)
+	if err != nil {
+		return "", err
+	}
+
+	title := stringField(model, "title")
+	if title == "" {
+		return "", fmt.Errorf("alert rule group has no title")
+	}
+
+	org := client.org(ctx)
+
+	if _, ok := org.folders[folderUid]; !ok {
+		return "", newAPIError(http.StatusNotFound, http.MethodPost, "/api/v1/provisioning/alert-rules", "folder not found")
+	}
+
+	id := grafana.AlertRuleGroupId(folderUid, title)
+
+	if rules, _ := model["rules"].([]interface{}); len(rules) == 0 {
+		delete(org.alertRuleGroups, id)
+		return id, nil
+	}
+
+	group, ok := org.alertRuleGroups[id]
+	if !ok {
+		group = client.newObject()
+		group.folderUid = folderUid
+		org.alertRuleGroups[id] = group
+	}
+
+	client.update(group, model)
+
+	return id, nil
+}
+
+func (client *ClientFake) DeleteAlertRuleGroup(ctx context.Context, id string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "DeleteAlertRuleGroup")
+	if err == nil {
+		delete(client.org(ctx).alertRuleGroups, id)
+	}
+	client.record("DeleteAlertRuleGroup", err, id)
+
+	return err
+}
+
+func (client *ClientFake) GetAlertRuleGroup(ctx context.Context, id string) (*grafana.Object, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	object, err := client.get(ctx, "GetAlertRuleGroup", client.org(ctx).alertRuleGroups, id, "/api/v1/provisioning/folder/")
+	client.record("GetAlertRuleGroup", err, id)
+
+	return object, err
+}
+
+func (client *ClientFake) GetAllAlertRuleGroupIds(ctx context.Context) ([]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids, err := client.getAllIds(ctx, "GetAllAlertRuleGroupIds", client.org(ctx).alertRuleGroups)
+	client.record("GetAllAlertRuleGroupIds", err)
+
+	return ids, err
+}
+
+// PostContactPoint stores a contact point by uid.  Posting an unknown uid creates the contact
+// point with that uid, like a put falling back to a post in the real client.
+func (client *ClientFake) PostContactPoint(ctx context.Context, json string, uid string) (string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	postedUid, err := client.postContactPoint(ctx, json, uid)
+	client.record("PostContactPoint", err, json, uid)
+
+	return postedUid, err
+}
+
+func (client *ClientFake) postContactPoint(ctx context.Context, json string, uid string) (string, error) {
+	if err := client.fault(ctx, "PostContactPoint"); err != nil {
+		return "", err
+	}
+
+	model, err := parseModel(json)
+	if err != nil {
+		return "", err
+	}
+
+	if stringField(model, "type") == "" {
+		return "", newAPIError(http.StatusBadRequest, http.MethodPost, "/api/v1/provisioning/contact-points", "contact point has no type")
+	}
+
+	org := client.org(ctx)
+
+	contactPoint, ok := org.contactPoints[uid]
+	if !ok {
+		contactPoint = client.newObject()
+		if uid != "" {
+			contactPoint.uid = uid
+		}
+
+		org.contactPoints[contactPoint.uid] = contactPoint
+	}
+
+	client.update(contactPoint, model)
+
+	return contactPoint.uid, nil
+}
+
+func (client *ClientFake) DeleteContactPoint(ctx context.Context, id string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "DeleteContactPoint")
+	if err == nil {
+		delete(client.org(ctx).contactPoints, id)
+	}
+	client.record("DeleteContactPoint", err, id)
+
+	return err
+}
+
+func (client *ClientFake) GetContactPoint(ctx context.Context, id string) (*grafana.Object, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	object, err := client.get(ctx, "GetContactPoint", client.org(ctx).contactPoints, id, "/api/v1/provisioning/contact-points/")
+	client.record("GetContactPoint", err, id)
+
+	return object, err
+}
+
+func (client *ClientFake) GetAllContactPointIds(ctx context.Context) ([]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids, err := client.getAllIds(ctx, "GetAllContactPointIds", client.org(ctx).contactPoints)
+	client.record("GetAllContactPointIds", err)
+
+	return ids, err
+}
+
+// PostMuteTiming stores a mute timing by name.  A renamed mute timing is stored under its new name
+// and the old one is kept, like in the real client.
+func (client *ClientFake) PostMuteTiming(ctx context.Context, json string, id string) (string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	name, err := client.postMuteTiming(ctx, json, id)
+	client.record("PostMuteTiming", err, json, id)
+
+	return name, err
+}
+
+func (client *ClientFake) postMuteTiming(ctx context.Context, json string, id string) (string, error) {
+	if err := client.fault(ctx, "PostMuteTiming"); err != nil {
+		return "", err
+	}
+
+	model, err := parseModel(json)
+	if err != nil {
+		return "", err
+	}
+
+	name := stringField(model, "name")
+	if name == "" {
+		return "", fmt.Errorf("mute timing has no name")
+	}
+
+	org := client.org(ctx)
+
+	muteTiming, ok := org.muteTimings[name]
+	if !ok {
+		muteTiming = client.newObject()
+		muteTiming.uid = name
+		org.muteTimings[name] = muteTiming
+	} else if id != name {
+		return "", newAPIError(http.StatusConflict, http.MethodPost, "/api/v1/provisioning/mute-timings", "a mute timing with the same name already exists")
+	}
+
+	client.update(muteTiming, model)
+
+	return name, nil
+}
+
+// DeleteMuteTiming deletes a mute timing.  Like grafana, mute timings used by the policy tree can
+// not be deleted.
+func (client *ClientFake) DeleteMuteTiming(ctx context.Context, id string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "DeleteMuteTiming")
+	if err == nil {
+		org := client.org(ctx)
+
+		if policyReferences(org.policy, "mute_time_intervals", id) {
+			err = newAPIError(http.StatusConflict, http.MethodDelete, "/api/v1/provisioning/mute-timings/"+id, "mute timing is used by a notification policy")
+		} else {
+			delete(org.muteTimings, id)
+		}
+	}
+	client.record("DeleteMuteTiming", err, id)
+
+	return err
+}
+
+func (client *ClientFake) GetMuteTiming(ctx context.Context, id string) (*grafana.Object, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	object, err := client.get(ctx, "GetMuteTiming", client.org(ctx).muteTimings, id, "/api/v1/provisioning/mute-timings/")
+	client.record("GetMuteTiming", err, id)
+
+	return object, err
+}
+
+func (client *ClientFake) GetAllMuteTimingIds(ctx context.Context) ([]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids, err := client.getAllIds(ctx, "GetAllMuteTimingIds", client.org(ctx).muteTimings)
+	client.record("GetAllMuteTimingIds", err)
+
+	return ids, err
+}
+
+// SetNotificationPolicy replaces the policy tree.  Like grafana, every receiver must be the name of
+// a contact point and every mute timing must exist.
+func (client *ClientFake) SetNotificationPolicy(ctx context.Context, json string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.setNotificationPolicy(ctx, json)
+	client.record("SetNotificationPolicy", err, json)
+
+	return err
+}
+
+func (client *ClientFake) setNotificationPolicy(ctx context.Context, json string) error {
+	if err := client.fault(ctx, "SetNotificationPolicy"); err != nil {
+		return err
+	}
+
+	policy, err := parseModel(json)
+	if err != nil {
+		return err
+	}
+
+	org := client.org(ctx)
+
+	receivers := make(map[string]bool)
+	for _, contactPoint := range org.contactPoints {
+		receivers[stringField(contactPoint.model, "name")] = true
+	}
+
+	if err := validateRoute(policy, receivers, org.muteTimings); err != nil {
+		return err
+	}
+
+	if stringField(policy, "receiver") == "" {
+		return newAPIError(http.StatusBadRequest, http.MethodPut, "/api/v1/provisioning/policies", "the root route must have a receiver")
+	}
+
+	policy["provenance"] = "api"
+	org.policy = policy
+
+	return nil
+}
+
+func (client *ClientFake) ResetNotificationPolicy(ctx context.Context) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "ResetNotificationPolicy")
+	if err == nil {
+		client.org(ctx).policy = nil
+	}
+	client.record("ResetNotificationPolicy", err)
+
+	return err
+}
+
+// GetNotificationPolicy returns the policy tree.  grafana's default tree only has a receiver.
+func (client *ClientFake) GetNotificationPolicy(ctx context.Context) (*grafana.Object, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "GetNotificationPolicy")
+	client.record("GetNotificationPolicy", err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	policy := client.org(ctx).policy
+	if policy == nil {
+		policy = map[string]interface{}{"receiver": "grafana-default-email"}
+	}
+
+	policyJSON, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &grafana.Object{JSON: string(policyJSON)}, nil
+}
+
+// validateRoute checks the receivers and mute timings of route and its children
+func validateRoute(route map[string]interface{}, receivers map[string]bool, muteTimings map[string]*fakeObject) error {
+	if receiver := stringField(route, "receiver"); receiver != "" && !receivers[receiver] {
+		return newAPIError(http.StatusBadRequest, http.MethodPut, "/api/v1/provisioning/policies", "unknown receiver "+receiver)
+	}
+
+	intervals, _ := route["mute_time_intervals"].([]interface{})
+	for _, interval := range intervals {
+		if name, _ := interval.(string); muteTimings[name] == nil {
+			return newAPIError(http.StatusBadRequest, http.MethodPut, "/api/v1/provisioning/policies", fmt.Sprintf("unknown mute timing %v", interval))
+		}
+	}
+
+	routes, _ := route["routes"].([]interface{})
+	for _, child := range routes {
+		childRoute, ok := child.(map[string]interface{})
+		if !ok {
+			return newAPIError(http.StatusBadRequest, http.MethodPut, "/api/v1/provisioning/policies", "routes must be objects")
+		}
+
+		if err := validateRoute(childRoute, receivers, muteTimings); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// policyReferences reports whether a route or one of its children lists value in field
+func policyReferences(route map[string]interface{}, field string, value string) bool {
+	values, _ := route[field].([]interface{})
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	routes, _ := route["routes"].([]interface{})
+	for _, child := range routes {
+		if childRoute, ok := child.(map[string]interface{}); ok && policyReferences(childRoute, field, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PostLibraryPanel stores a library panel by uid.  Like grafana, a panel posted with an unknown
+// uid is created with that uid.
+func (client *ClientFake) PostLibraryPanel(ctx context.Context, json string, folderId string, folderUid string, uid string) (string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	postedUid, err := client.postLibraryPanel(ctx, json, folderId, folderUid, uid)
+	client.record("PostLibraryPanel", err, json, folderId, folderUid, uid)
+
+	return postedUid, err
+}
+
+func (client *ClientFake) postLibraryPanel(ctx context.Context, json string, folderId string, folderUid string, uid string) (string, error) {
+	if err := client.fault(ctx, "PostLibraryPanel"); err != nil {
+		return "", err
+	}
+
+	model, err := parseModel(json)
+	if err != nil {
+		return "", err
+	}
+
+	if stringField(model, "name") == "" {
+		return "", newAPIError(http.StatusBadRequest, http.MethodPost, "/api/library-elements", "library panel has no name")
+	}
+
+	org := client.org(ctx)
+
+	folderUid, err = folderUidOf(org, folderId, folderUid, "/api/library-elements")
+	if err != nil {
+		return "", err
+	}
+
+	libraryPanel, ok := org.libraryPanels[uid]
+	if !ok {
+		libraryPanel = client.newObject()
+		if uid != grafana.NO_ID {
+			libraryPanel.uid = uid
+		}
+
+		org.libraryPanels[libraryPanel.uid] = libraryPanel
+	}
+
+	libraryPanel.folderUid = folderUid
+	client.update(libraryPanel, model)
+
+	return libraryPanel.uid, nil
+}
+
+func (client *ClientFake) DeleteLibraryPanel(ctx context.Context, id string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "DeleteLibraryPanel")
+	if err == nil {
+		delete(client.org(ctx).libraryPanels, id)
+	}
+	client.record("DeleteLibraryPanel", err, id)
+
+	return err
+}
+
+func (client *ClientFake) GetLibraryPanel(ctx context.Context, id string) (*grafana.Object, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	object, err := client.get(ctx, "GetLibraryPanel", client.org(ctx).libraryPanels, id, "/api/library-elements/")
+	client.record("GetLibraryPanel", err, id)
+
+	return object, err
+}
+
+func (client *ClientFake) GetAllLibraryPanelIds(ctx context.Context) ([]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids, err := client.getAllIds(ctx, "GetAllLibraryPanelIds", client.org(ctx).libraryPanels)
+	client.record("GetAllLibraryPanelIds", err)
+
+	return ids, err
+}
+
+// PostPlaylist stores a playlist by uid.  Like grafana, a playlist posted with an unknown uid is
+// created with that uid.
+func (client *ClientFake) PostPlaylist(ctx context.Context, json string, uid string) (string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	postedUid, err := client.postPlaylist(ctx, json, uid)
+	client.record("PostPlaylist", err, json, uid)
+
+	return postedUid, err
+}
+
+func (client *ClientFake) postPlaylist(ctx context.Context, json string, uid string) (string, error) {
+	if err := client.fault(ctx, "PostPlaylist"); err != nil {
+		return "", err
+	}
+
+	model, err := parseModel(json)
+	if err != nil {
+		return "", err
+	}
+
+	if stringField(model, "name") == "" {
+		return "", newAPIError(http.StatusBadRequest, http.MethodPost, "/api/playlists", "playlist has no name")
+	}
+
+	org := client.org(ctx)
+
+	playlist, ok := org.playlists[uid]
+	if !ok {
+		playlist = client.newObject()
+		if uid != grafana.NO_ID {
+			playlist.uid = uid
+		}
+
+		org.playlists[playlist.uid] = playlist
+	}
+
+	client.update(playlist, model)
+
+	return playlist.uid, nil
+}
+
+func (client *ClientFake) DeletePlaylist(ctx context.Context, id string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "DeletePlaylist")
+	if err == nil {
+		delete(client.org(ctx).playlists, id)
+	}
+	client.record("DeletePlaylist", err, id)
+
+	return err
+}
+
+func (client *ClientFake) GetPlaylist(ctx context.Context, id string) (*grafana.Object, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	object, err := client.get(ctx, "GetPlaylist", client.org(ctx).playlists, id, "/api/playlists/")
+	client.record("GetPlaylist", err, id)
+
+	return object, err
+}
+
+func (client *ClientFake) GetAllPlaylistIds(ctx context.Context) ([]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids, err := client.getAllIds(ctx, "GetAllPlaylistIds", client.org(ctx).playlists)
+	client.record("GetAllPlaylistIds", err)
+
+	return ids, err
+}
+
+// PostAnnotation stores an annotation by numeric id.  The dashboard it is placed on is kept as
+// dashboardUID in its json.  Like the grafana client, an annotation posted with an unknown id is
+// created with a new id.
+func (client *ClientFake) PostAnnotation(ctx context.Context, json string, dashboardUid string, id string) (string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	postedId, err := client.postAnnotation(ctx, json, dashboardUid, id)
+	client.record("PostAnnotation", err, json, dashboardUid, id)
+
+	return postedId, err
+}
+
+func (client *ClientFake) postAnnotation(ctx context.Context, json string, dashboardUid string, id string) (string, error) {
+	if err := client.fault(ctx, "PostAnnotation"); err != nil {
+		return "", err
+	}
+
+	model, err := parseModel(json)
+	if err != nil {
+		return "", err
+	}
+
+	org := client.org(ctx)
+
+	if dashboardUid != "" {
+		if _, ok := org.dashboards[dashboardUid]; !ok {
+			return "", newAPIError(http.StatusNotFound, http.MethodPost, "/api/annotations", "dashboard not found")
+		}
+
+		model["dashboardUID"] = dashboardUid
+	}
+
+	annotation, ok := org.annotations[id]
+	if !ok {
+		annotation = client.newObject()
+		org.annotations[annotation.id] = annotation
+	}
+
+	client.update(annotation, model)
+
+	return annotation.id, nil
+}
+
+func (client *ClientFake) DeleteAnnotation(ctx context.Context, id string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "DeleteAnnotation")
+	if err == nil {
+		delete(client.org(ctx).annotations, id)
+	}
+	client.record("DeleteAnnotation", err, id)
+
+	return err
+}
+
+func (client *ClientFake) GetAnnotation(ctx context.Context, id string) (*grafana.Object, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	object, err := client.get(ctx, "GetAnnotation", client.org(ctx).annotations, id, "/api/annotations/")
+	client.record("GetAnnotation", err, id)
+
+	return object, err
+}
+
+func (client *ClientFake) GetAllAnnotationIds(ctx context.Context) ([]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids, err := client.getAllIds(ctx, "GetAllAnnotationIds", client.org(ctx).annotations)
+	client.record("GetAllAnnotationIds", err)
+
+	return ids, err
+}
+
+func (client *ClientFake) AddAnnotation(ctx context.Context, json string) (string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	id, err := client.addAnnotation(ctx, json)
+	client.record("AddAnnotation", err, json)
+
+	return id, err
+}
+
+func (client *ClientFake) addAnnotation(ctx context.Context, json string) (string, error) {
+	if err := client.fault(ctx, "AddAnnotation"); err != nil {
+		return "", err
+	}
+
+	if _, err := parseModel(json); err != nil {
+		return "", err
+	}
+
+	org := client.org(ctx)
+	org.addedAnnotations = append(org.addedAnnotations, json)
+
+	return client.newObject().id, nil
+}
+
+// FindAnnotations searches the annotations added with AddAnnotation
+func (client *ClientFake) FindAnnotations(ctx context.Context, tags []string, limit int) ([]grafana.Annotation, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	annotations, err := client.findAnnotations(ctx, tags, limit)
+	client.record("FindAnnotations", err, strings.Join(tags, ","), strconv.Itoa(limit))
+
+	return annotations, err
+}
+
+func (client *ClientFake) findAnnotations(ctx context.Context, tags []string, limit int) ([]grafana.Annotation, error) {
+	if err := client.fault(ctx, "FindAnnotations"); err != nil {
+		return nil, err
+	}
+
+	var found []grafana.Annotation
+
+	for _, annotationJson := range client.org(ctx).addedAnnotations {
+		var annotation grafana.Annotation
+
+		if err := json.Unmarshal([]byte(annotationJson), &annotation); err != nil {
+			return nil, err
+		}
+
+		if hasTags(annotation.Tags, tags) {
+			found = append(found, annotation)
+		}
+	}
+
+	// newest first like grafana
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Time > found[j].Time })
+
+	if len(found) > limit {
+		found = found[:limit]
+	}
+
+	return found, nil
+}
+
+// hasTags returns true if every tag in wanted is in tags
+func hasTags(tags []string, wanted []string) bool {
+	for _, tag := range wanted {
+		found := false
+
+		for _, candidate := range tags {
+			if candidate == tag {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AddedAnnotations returns the json of every annotation added with AddAnnotation in order
+func (client *ClientFake) AddedAnnotations(ctx context.Context) []string {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	return append([]string(nil), client.org(ctx).addedAnnotations...)
+}
+
+func (client *ClientFake) PostServiceAccount(ctx context.Context, json string, id string) (string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	postedId, err := client.postById(ctx, "PostServiceAccount", client.org(ctx).serviceAccounts, json, id, "/api/serviceaccounts")
+	client.record("PostServiceAccount", err, json, id)
+
+	return postedId, err
+}
+
+func (client *ClientFake) DeleteServiceAccount(ctx context.Context, id string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "DeleteServiceAccount")
+	if err == nil {
+		org := client.org(ctx)
+
+		// like grafana, deleting a service account revokes its tokens
+		delete(org.serviceAccounts, id)
+		delete(org.serviceAccountTokens, id)
+	}
+	client.record("DeleteServiceAccount", err, id)
+
+	return err
+}
+
+func (client *ClientFake) GetServiceAccount(ctx context.Context, id string) (*grafana.Object, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	object, err := client.get(ctx, "GetServiceAccount", client.org(ctx).serviceAccounts, id, "/api/serviceaccounts/")
+	client.record("GetServiceAccount", err, id)
+
+	return object, err
+}
+
+func (client *ClientFake) GetAllServiceAccountIds(ctx context.Context) ([]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids, err := client.getAllIds(ctx, "GetAllServiceAccountIds", client.org(ctx).serviceAccounts)
+	client.record("GetAllServiceAccountIds", err)
+
+	// like grafana, only service accounts holding a token CreateServiceAccountToken created
+	var managed []string
+
+	for _, id := range ids {
+		for _, token := range client.org(ctx).serviceAccountTokens[id] {
+			if strings.HasPrefix(token.name, grafana.ServiceAccountTokenPrefix) {
+				managed = append(managed, id)
+				break
+			}
+		}
+	}
+
+	return managed, err
+}
+
+// CreateServiceAccountToken adds a token whose key is derived from its id
+func (client *ClientFake) CreateServiceAccountToken(ctx context.Context, id string, name string, secondsToLive int64) (string, string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	tokenId, key, err := client.createServiceAccountToken(ctx, id, grafana.ServiceAccountTokenPrefix+name, secondsToLive)
+	client.record("CreateServiceAccountToken", err, id, name, strconv.FormatInt(secondsToLive, 10))
+
+	return tokenId, key, err
+}
+
+// AddServiceAccountToken adds a token named name to the service account with the given id, like a
+// token created by hand, and returns the token's id
+func (client *ClientFake) AddServiceAccountToken(ctx context.Context, id string, name string) (string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	tokenId, _, err := client.createServiceAccountToken(ctx, id, name, 0)
+
+	return tokenId, err
+}
+
+func (client *ClientFake) createServiceAccountToken(ctx context.Context, id string, name string, secondsToLive int64) (string, string, error) {
+	if err := client.fault(ctx, "CreateServiceAccountToken"); err != nil {
+		return "", "", err
+	}
+
+	org := client.org(ctx)
+
+	if _, ok := org.serviceAccounts[id]; !ok {
+		return "", "", newAPIError(http.StatusNotFound, http.MethodPost, "/api/serviceaccounts/"+id+"/tokens", "service account not found")
+	}
+
+	tokens, ok := org.serviceAccountTokens[id]
+	if !ok {
+		tokens = make(map[string]*fakeToken)
+		org.serviceAccountTokens[id] = tokens
+	}
+
+	for _, token := range tokens {
+		if token.name == name {
+			return "", "", newAPIError(http.StatusConflict, http.MethodPost, "/api/serviceaccounts/"+id+"/tokens", "a token with the same name already exists")
+		}
+	}
+
+	tokenId := client.newObject().id
+	tokens[tokenId] = &fakeToken{
+		name:          name,
+		key:           "glsa_fake_" + tokenId,
+		secondsToLive: secondsToLive,
+	}
+
+	return tokenId, tokens[tokenId].key, nil
+}
+
+func (client *ClientFake) DeleteServiceAccountToken(ctx context.Context, id string, tokenId string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	err := client.fault(ctx, "DeleteServiceAccountToken")
+	if err == nil {
+		delete(client.org(ctx).serviceAccountTokens[id], tokenId)
+	}
+	client.record("DeleteServiceAccountToken", err, id, tokenId)
+
+	return err
+}
+
+func (client *ClientFake) GetServiceAccountTokenIds(ctx context.Context, id string) ([]string, error) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids, err := client.getServiceAccountTokenIds(ctx, id)
+	client.record("GetServiceAccountTokenIds", err, id)
+
+	return ids, err
+}
+
+func (client *ClientFake) getServiceAccountTokenIds(ctx context.Context, id string) ([]string, error) {
+	if err := client.fault(ctx, "GetServiceAccountTokenIds"); err != nil {
+		return nil, err
+	}
+
+	org := client.org(ctx)
+
+	if _, ok := org.serviceAccounts[id]; !ok {
+		return nil, newAPIError(http.StatusNotFound, http.MethodGet, "/api/serviceaccounts/"+id+"/tokens", "service account not found")
+	}
+
+	var ids []string
+	for tokenId := range org.serviceAccountTokens[id] {
+		ids = append(ids, tokenId)
+	}
+
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+//
+// shared.  callers must hold the lock
+//
+
+// folderUidOf returns the uid of the folder an object is placed in by numeric id or uid.  The
+// General folder is "".
+func folderUidOf(org *fakeOrg, folderId string, folderUid string, endpoint string) (string, error) {
+	if folderUid == "" && folderId != "0" && folderId != "" {
+		for _, folder := range org.folders {
+			if folder.id == folderId {
+				return folder.uid, nil
+			}
+		}
+
+		return "", newAPIError(http.StatusBadRequest, http.MethodPost, endpoint, "Folder not found")
+	}
+
+	if _, ok := org.folders[folderUid]; folderUid != "" && !ok {
+		return "", newAPIError(http.StatusBadRequest, http.MethodPost, endpoint, "Folder not found")
+	}
+
+	return folderUid, nil
+}
+
+// org returns the objects of the organization selected by ctx
+func (client *ClientFake) org(ctx context.Context) *fakeOrg {
+	orgID := grafana.OrgID(ctx)
+
+	org, ok := client.orgs[orgID]
+	if !ok {
+		org = &fakeOrg{
+			dashboards:         make(map[string]*fakeObject),
+			folders:            make(map[string]*fakeObject),
+			dataSources:        make(map[string]*fakeObject),
+			alertNotifications: make(map[string]*fakeObject),
+			teams:              make(map[string]*fakeObject),
+			alertRuleGroups:    make(map[string]*fakeObject),
+			contactPoints:      make(map[string]*fakeObject),
+			muteTimings:        make(map[string]*fakeObject),
+			libraryPanels:      make(map[string]*fakeObject),
+			playlists:          make(map[string]*fakeObject),
+			annotations:        make(map[string]*fakeObject),
+			serviceAccounts:    make(map[string]*fakeObject),
+
+			teamMembers:          make(map[string][]string),
+			userIds:              make(map[string]string),
+			folderPermissions:    make(map[string][]grafana.Permission),
+			dashboardPermissions: make(map[string][]grafana.Permission),
+			serviceAccountTokens: make(map[string]map[string]*fakeToken),
+		}
+
+		client.orgs[orgID] = org
+	}
+
+	return org
+}
+
+// fault returns the error injected for method if there is one
+func (client *ClientFake) fault(ctx context.Context, method string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if errs := client.nextFaults[method]; len(errs) > 0 {
+		client.nextFaults[method] = errs[1:]
+		return errs[0]
+	}
+
+	return client.alwaysFaults[method]
+}
+
+func (client *ClientFake) record(method string, err error, args ...string) {
+	client.calls = append(client.calls, Call{
+		Method: method,
+		Args:   args,
+		Err:    err,
+	})
+}
+
+// newObject returns an object with a new numeric id and a uid derived from it
+func (client *ClientFake) newObject() *fakeObject {
+	id := strconv.Itoa(client.nextId)
+	client.nextId++
+
+	return &fakeObject{
+		id:      id,
+		uid:     "uid-" + id,
+		created: time.Now(),
+	}
+}
+
+func (client *ClientFake) update(object *fakeObject, model map[string]interface{}) {
+	object.version++
+	object.updated = time.Now()
+	object.model = model
+}
+
+// postById creates or updates an object stored by numeric id.  Like grafana, names must be
+// unique and posting an unknown id creates a new object.
+func (client *ClientFake) postById(ctx context.Context, method string, objects map[string]*fakeObject, json string, id string, endpoint string) (string, error) {
+	if err := client.fault(ctx, method); err != nil {
+		return "", err
+	}
+
+	model, err := parseModel(json)
+	if err != nil {
+		return "", err
+	}
+
+	name := stringField(model, "name")
+	for _, existing := range objects {
+		if existing.id != id && name != "" && stringField(existing.model, "name") == name {
+			return "", newAPIError(http.StatusConflict, http.MethodPost, endpoint, "an object with the same name already exists")
+		}
+	}
+
+	object, ok := objects[id]
+	if !ok {
+		object = client.newObject()
+		objects[object.id] = object
+	}
+
+	client.update(object, model)
+
+	return object.id, nil
+}
+
+func (client *ClientFake) get(ctx context.Context, method string, objects map[string]*fakeObject, id string, endpoint string) (*grafana.Object, error) {
+	if err := client.fault(ctx, method); err != nil {
+		return nil, err
+	}
+
+	object, ok := objects[id]
+	if !ok {
+		return nil, newAPIError(http.StatusNotFound, http.MethodGet, endpoint+id, "not found")
+	}
+
+	model := make(map[string]interface{}, len(object.model)+3)
+	for k, v := range object.model {
+		model[k] = v
+	}
+	model["id"], _ = strconv.Atoi(object.id)
+	model["uid"] = object.uid
+	model["version"] = object.version
+
+	modelJSON, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+
+	return &grafana.Object{
+		JSON:    string(modelJSON),
+		Version: object.version,
+		Created: object.created,
+		Updated: object.updated,
+	}, nil
+}
+
+func (client *ClientFake) getAllIds(ctx context.Context, method string, objects map[string]*fakeObject) ([]string, error) {
+	if err := client.fault(ctx, method); err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for id := range objects {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// defaultPermissions is the acl grafana gives new folders and dashboards
+func defaultPermissions() []grafana.Permission {
+	return []grafana.Permission{
+		{Role: "Viewer", Permission: grafana.PermissionView},
+		{Role: "Editor", Permission: grafana.PermissionEdit},
+	}
+}
+
+func (client *ClientFake) getPermissions(ctx context.Context, method string, objects map[string]*fakeObject, acls map[string][]grafana.Permission, uid string, endpoint string) ([]grafana.Permission, error) {
+	if err := client.fault(ctx, method); err != nil {
+		return nil, err
+	}
+
+	if _, ok := objects[uid]; !ok {
+		return nil, newAPIError(http.StatusNotFound, http.MethodGet, endpoint+uid+"/permissions", "not found")
+	}
+
+	acl, ok := acls[uid]
+	if !ok {
+		acl = defaultPermissions()
+	}
+
+	return append([]grafana.Permission(nil), acl...), nil
+}
+
+func (client *ClientFake) setPermissions(ctx context.Context, method string, objects map[string]*fakeObject, acls map[string][]grafana.Permission, uid string, permissions []grafana.Permission, endpoint string) error {
+	if err := client.fault(ctx, method); err != nil {
+		return err
+	}
+
+	if _, ok := objects[uid]; !ok {
+		return newAPIError(http.StatusNotFound, http.MethodPost, endpoint+uid+"/permissions", "not found")
+	}
+
+	acls[uid] = append([]grafana.Permission(nil), permissions...)
+
+	return nil
+}
+
+func removeTeamPermissions(acls map[string][]grafana.Permission, teamId string) {
+	for uid, acl := range acls {
+		var kept []grafana.Permission
+
+		for _, permission := range acl {
+			if permission.TeamID != teamId {
+				kept = append(kept, permission)
+			}
+		}
+
+		acls[uid] = kept
+	}
+}
+
+func parseModel(modelJSON string) (map[string]interface{}, error) {
+	var model map[string]interface{}
+
+	if err := json.Unmarshal([]byte(modelJSON), &model); err != nil {
+		return nil, err
+	}
+
+	// like the real client, ids and versions in the posted json are ignored
+	delete(model, "id")
+	delete(model, "version")
+
+	return model, nil
+}
+
+func stringField(model map[string]interface{}, field string) string {
+	value, ok := model[field].(string)
+	if !ok {
+		return ""
+	}
+
+	return value
+}
+
+func newAPIError(statusCode int, method string, endpoint string, message string) *grafana.APIError {
+	return &grafana.APIError{
+		StatusCode: statusCode,
+		Status:     fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		Message:    message,
+		Method:     method,
+		Endpoint:   endpoint,
+		Retryable:  statusCode >= 500 || statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests,
+	}
 }
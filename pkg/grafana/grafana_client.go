@@ -1,63 +1,217 @@
 package grafana
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
 
 	"github.com/imroc/req"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog"
 )
 
 const NO_ID = ""
 
-type Interface interface {
-	PostDashboard(string, string) (string, error)
-	PostDashboardWithFolder(string, string, string) (string, error)
-	DeleteDashboard(string) error
-	GetAllDashboardIds() ([]string, error)
-
-	PostAlertNotification(string, string) (string, error)
-	DeleteAlertNotification(string) error
-	GetAllAlertNotificationIds() ([]string, error)
+// pageSize is the number of objects requested per page from endpoints that support paging.
+// Grafana's search endpoint returns at most 1000 results when no limit is given.
+const pageSize = 1000
 
-	PostDataSource(string, string) (string, error)
-	DeleteDataSource(string) error
-	GetAllDataSourceIds() ([]string, error)
+type Interface interface {
+	PostDashboard(context.Context, string, string) (string, error)
+	PostDashboardWithFolder(context.Context, string, string, string, string) (string, error)
+	DeleteDashboard(context.Context, string) error
+	GetDashboard(context.Context, string) (*Object, error)
+	GetAllDashboardIds(context.Context) ([]string, error)
+
+	PostAlertNotification(context.Context, string, string) (string, error)
+	DeleteAlertNotification(context.Context, string) error
+	GetAlertNotification(context.Context, string) (*Object, error)
+	GetAllAlertNotificationIds(context.Context) ([]string, error)
+	GetAlertNotificationNumericIds(context.Context) (map[string]string, error)
+
+	PostDataSource(context.Context, string, string) (string, error)
+	DeleteDataSource(context.Context, string) error
+	GetDataSource(context.Context, string) (*Object, error)
+	GetAllDataSourceIds(context.Context) ([]string, error)
+	GetDataSourceNumericIds(context.Context) (map[string]string, error)
+
+	PostFolder(context.Context, string, string) (string, string, error)
+	DeleteFolder(context.Context, string) error
+	GetFolder(context.Context, string) (*Object, error)
+	GetAllFolderIds(context.Context) ([]string, error)
+
+	PostOrganization(context.Context, string, string) (string, error)
+	DeleteOrganization(context.Context, string) error
+	GetOrganization(context.Context, string) (*Object, error)
+	GetAllOrganizationIds(context.Context) ([]string, error)
+
+	PostTeam(context.Context, string, string) (string, error)
+	DeleteTeam(context.Context, string) error
+	GetTeam(context.Context, string) (*Object, error)
+	GetAllTeamIds(context.Context) ([]string, error)
+	SetTeamMembers(context.Context, string, []string) error
+
+	PostAlertRuleGroup(context.Context, string, string) (string, error)
+	DeleteAlertRuleGroup(context.Context, string) error
+	GetAlertRuleGroup(context.Context, string) (*Object, error)
+	GetAllAlertRuleGroupIds(context.Context) ([]string, error)
+
+	PostContactPoint(context.Context, string, string) (string, error)
+	DeleteContactPoint(context.Context, string) error
+	GetContactPoint(context.Context, string) (*Object, error)
+	GetAllContactPointIds(context.Context) ([]string, error)
+
+	PostMuteTiming(context.Context, string, string) (string, error)
+	DeleteMuteTiming(context.Context, string) error
+	GetMuteTiming(context.Context, string) (*Object, error)
+	GetAllMuteTimingIds(context.Context) ([]string, error)
+
+	SetNotificationPolicy(context.Context, string) error
+	ResetNotificationPolicy(context.Context) error
+	GetNotificationPolicy(context.Context) (*Object, error)
+
+	PostLibraryPanel(context.Context, string, string, string, string) (string, error)
+	DeleteLibraryPanel(context.Context, string) error
+	GetLibraryPanel(context.Context, string) (*Object, error)
+	GetAllLibraryPanelIds(context.Context) ([]string, error)
+
+	PostPlaylist(context.Context, string, string) (string, error)
+	DeletePlaylist(context.Context, string) error
+	GetPlaylist(context.Context, string) (*Object, error)
+	GetAllPlaylistIds(context.Context) ([]string, error)
+
+	PostAnnotation(context.Context, string, string, string) (string, error)
+	DeleteAnnotation(context.Context, string) error
+	GetAnnotation(context.Context, string) (*Object, error)
+	GetAllAnnotationIds(context.Context) ([]string, error)
+	AddAnnotation(context.Context, string) (string, error)
+	FindAnnotations(context.Context, []string, int) ([]Annotation, error)
+
+	PostServiceAccount(context.Context, string, string) (string, error)
+	DeleteServiceAccount(context.Context, string) error
+	GetServiceAccount(context.Context, string) (*Object, error)
+	GetAllServiceAccountIds(context.Context) ([]string, error)
+	CreateServiceAccountToken(context.Context, string, string, int64) (string, string, error)
+	DeleteServiceAccountToken(context.Context, string, string) error
+	GetServiceAccountTokenIds(context.Context, string) ([]string, error)
+
+	GetOrgUserIds(context.Context) (map[string]string, error)
+
+	GetFolderPermissions(context.Context, string) ([]Permission, error)
+	SetFolderPermissions(context.Context, string, []Permission) error
+	GetDashboardPermissions(context.Context, string) ([]Permission, error)
+	SetDashboardPermissions(context.Context, string, []Permission) error
+}
 
-	PostFolder(string, string) (string, string, error)
-	DeleteFolder(string) error
-	GetAllFolderIds() ([]string, error)
+// ClientOptions configures how a Client connects to grafana.  The zero value connects
+// without credentials using the system certificate pool.
+type ClientOptions struct {
+	Credentials *Credentials
+	TLS         *TLSOptions
+
+	// ConnectTimeout bounds establishing a connection, including the TLS handshake.
+	ConnectTimeout time.Duration
+	// ReadTimeout bounds waiting for response headers after a request has been written.
+	ReadTimeout time.Duration
+	// Timeout bounds an entire request including reading the response body.
+	Timeout time.Duration
+
+	// Retry configures retries of transient failures.  nil disables retries.
+	Retry *RetryOptions
+
+	// QPS is the sustained number of requests per second sent to grafana.  0 is unlimited.
+	QPS float64
+	// Burst is the number of requests that may be sent at once before QPS applies.
+	Burst int
+	// MaxInFlight caps the number of concurrent requests to grafana.  0 is unlimited.
+	MaxInFlight int
 }
 
+// DefaultConnectTimeout is used when ClientOptions.ConnectTimeout is 0
+const DefaultConnectTimeout = 5 * time.Second
+
 type Client struct {
-	address string
+	address     string
+	credentials *Credentials
+	retry       *RetryOptions
+	limiter     *limiter
+	req         *req.Req
+
+	capabilitiesLock sync.Mutex
+	capabilities     *Capabilities
 }
 
-func init() {
-	// cost is required for prom metrics
-	req.SetFlags(req.LstdFlags | req.Lcost)
-}
+// NewClient creates a client for the grafana server at address.
+func NewClient(address string, options ClientOptions) (*Client, error) {
+
+	grafanaURL, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
 
-func NewClient(address string) *Client {
+	connectTimeout := options.ConnectTimeout
+	if connectTimeout == 0 {
+		connectTimeout = DefaultConnectTimeout
+	}
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   connectTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   connectTimeout,
+		ResponseHeaderTimeout: options.ReadTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	if options.TLS != nil {
+		transport.TLSClientConfig = options.TLS.tlsConfig(grafanaURL.Hostname())
+	}
+
+	r := req.New()
+	r.SetClient(&http.Client{
+		Transport: transport,
+		Timeout:   options.Timeout,
+	})
+	// cost is required for prom metrics
+	r.SetFlags(req.LstdFlags | req.Lcost)
 
 	client := &Client{
-		address: address,
+		address:     address,
+		credentials: options.Credentials,
+		retry:       options.Retry,
+		limiter:     newLimiter(options.QPS, options.Burst, options.MaxInFlight),
+		req:         r,
 	}
 
-	return client
+	return client, nil
 }
 
-func (client *Client) PostDashboard(dashboardJSON string, uid string) (string, error) {
-	return client.PostDashboardWithFolder(dashboardJSON, "0", uid)
+func (client *Client) PostDashboard(ctx context.Context, dashboardJSON string, uid string) (string, error) {
+	return client.PostDashboardWithFolder(ctx, dashboardJSON, "0", "", uid)
 }
 
-func (client *Client) PostDashboardWithFolder(dashboardJSON string, folderId string, uid string) (string, error) {
-	dashboardJSON, err := sanitizeObject(dashboardJSON, false)
+// PostDashboardWithFolder creates or overwrites a dashboard in a folder.  The folder is referenced
+// by folderUid if grafana supports it and by folderId otherwise.
+func (client *Client) PostDashboardWithFolder(ctx context.Context, dashboardJSON string, folderId string, folderUid string, uid string) (string, error) {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	dashboardJSON, err = sanitizeObject(dashboardJSON, false)
 
 	if err != nil {
 		return "", err
@@ -71,13 +225,25 @@ func (client *Client) PostDashboardWithFolder(dashboardJSON string, folderId str
 		}
 	}
 
+	folder := fmt.Sprintf(`"folderId": %v`, folderId)
+	if capabilities.DashboardFolderUID && folderUid != "" {
+		folder = fmt.Sprintf(`"folderUid": %q`, folderUid)
+	}
+
 	postJSON := fmt.Sprintf(`{
 		"dashboard": %v,
-		"folderId": %v,
+		%v,
 		"overwrite": true
-	}`, dashboardJSON, folderId)
+	}`, dashboardJSON, folder)
+
+	var response map[string]interface{}
 
-	response, err := client.postGrafanaObject(postJSON, "/api/dashboards/db", prometheus.TypeDashboard)
+	// overwriting a dashboard by uid can be safely repeated.  creating one can not
+	if uid != NO_ID {
+		response, err = client.postIdempotentGrafanaObject(ctx, postJSON, "/api/dashboards/db", prometheus.TypeDashboard)
+	} else {
+		response, err = client.postGrafanaObject(ctx, postJSON, "/api/dashboards/db", prometheus.TypeDashboard)
+	}
 
 	if err != nil {
 		return "", err
@@ -86,75 +252,98 @@ func (client *Client) PostDashboardWithFolder(dashboardJSON string, folderId str
 	return getField(response, "uid")
 }
 
-func (client *Client) DeleteDashboard(id string) error {
-	resp, err := req.Delete(client.address + "/api/dashboards/uid/" + id)
-	prometheus.GrafanaDeleteLatencyMilliseconds.WithLabelValues(prometheus.TypeDashboard).Observe(float64(resp.Cost() / time.Millisecond))
+func (client *Client) DeleteDashboard(ctx context.Context, id string) error {
+	return client.deleteGrafanaObject(ctx, "/api/dashboards/uid/"+id, prometheus.TypeDashboard)
+}
 
+// GetDashboard returns the dashboard with the given uid.  Object.JSON is the dashboard model
+// without grafana's surrounding metadata.
+func (client *Client) GetDashboard(ctx context.Context, uid string) (*Object, error) {
+	body, err := client.getGrafanaObject(ctx, "/api/dashboards/uid/"+uid, prometheus.TypeDashboard)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if !responseIsSuccessOrNotFound(resp) {
-		return errors.New(resp.Response().Status)
+	var response struct {
+		Dashboard json.RawMessage `json:"dashboard"`
+		Meta      json.RawMessage `json:"meta"`
 	}
 
-	return nil
-}
-
-func (client *Client) GetAllDashboardIds() ([]string, error) {
-	var resp *req.Resp
-	var err error
-	var dashboards []map[string]interface{}
-
-	// Request existing notification channels
-	if resp, err = req.Get(client.address + "/api/search?type=dash-db"); err != nil {
+	if err = json.Unmarshal(body, &response); err != nil {
 		return nil, err
 	}
-	prometheus.GrafanaGetLatencyMilliseconds.WithLabelValues(prometheus.TypeDashboard).Observe(float64(resp.Cost() / time.Millisecond))
 
-	if err = resp.ToJSON(&dashboards); err != nil {
+	object, err := newObject(response.Dashboard, response.Meta)
+	if err != nil {
 		return nil, err
 	}
 
-	var ids []string
+	// the version is tracked on the dashboard model.  meta only carries it on some grafanas
+	var dashboard struct {
+		Version int `json:"version"`
+	}
 
-	for _, dashboard := range dashboards {
-		ids = append(ids, dashboard["uid"].(string))
+	if err = json.Unmarshal(response.Dashboard, &dashboard); err != nil {
+		return nil, err
 	}
 
-	return ids, nil
+	object.Version = dashboard.Version
+
+	return object, nil
+}
+
+// GetAllDashboardIds returns the uid of every dashboard.  Search results are paged through so
+// the complete set is returned no matter how many dashboards exist.
+func (client *Client) GetAllDashboardIds(ctx context.Context) ([]string, error) {
+	return client.getPagedGrafanaObjectIds(ctx, "/api/search?type=dash-db", "", "limit", "uid", prometheus.TypeDashboard)
 }
 
-func (client *Client) PostAlertNotification(alertNotificationJson string, id string) (string, error) {
+// PostAlertNotification creates or updates an alert notification and returns its id.  The id is
+// a uid if grafana supports uid routes for alert notifications and a numeric id otherwise.
+func (client *Client) PostAlertNotification(ctx context.Context, alertNotificationJson string, id string) (string, error) {
 	var response map[string]interface{}
-	alertNotificationJson, err := sanitizeObject(alertNotificationJson, false)
+
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	alertNotificationJson, err = sanitizeObject(alertNotificationJson, false)
 
 	if err != nil {
 		return "", err
 	}
 
 	if id == NO_ID {
-		response, err = client.postGrafanaObject(alertNotificationJson, "/api/alert-notifications", prometheus.TypeAlertNotification)
+		response, err = client.postGrafanaObject(ctx, alertNotificationJson, "/api/alert-notifications", prometheus.TypeAlertNotification)
 
 		if err != nil {
 			return "", err
 		}
 	} else {
+		var path string
+
 		// alert notification requires the id in the object for unknown reasons
-		alertNotificationJson, err = setId(alertNotificationJson, "id", id)
+		if capabilities.AlertNotificationUIDRoutes && !isNumericId(id) {
+			alertNotificationJson, err = setId(alertNotificationJson, "uid", id)
+			path = fmt.Sprintf("/api/alert-notifications/uid/%v", id)
+		} else {
+			alertNotificationJson, err = setId(alertNotificationJson, "id", id)
+			path = fmt.Sprintf("/api/alert-notifications/%v", id)
+		}
 
 		if err != nil {
 			return "", err
 		}
 
-		response, err = client.putGrafanaObject(alertNotificationJson, fmt.Sprintf("/api/alert-notifications/%v", id), prometheus.TypeAlertNotification)
+		response, err = client.putGrafanaObject(ctx, alertNotificationJson, path, prometheus.TypeAlertNotification)
 
 		// try a put if the post fails
 		if err != nil {
 			runtime.HandleError(err)
 			prometheus.GrafanaWastedPutTotal.WithLabelValues(prometheus.TypeAlertNotification).Inc()
 
-			response, err = client.postGrafanaObject(alertNotificationJson, "/api/alert-notifications", prometheus.TypeAlertNotification)
+			response, err = client.postGrafanaObject(ctx, alertNotificationJson, "/api/alert-notifications", prometheus.TypeAlertNotification)
 
 			if err != nil {
 				return "", err
@@ -162,70 +351,131 @@ func (client *Client) PostAlertNotification(alertNotificationJson string, id str
 		}
 	}
 
+	if capabilities.AlertNotificationUIDRoutes {
+		return getField(response, "uid")
+	}
+
 	return getField(response, "id")
 }
 
-func (client *Client) DeleteAlertNotification(id string) error {
-	resp, err := req.Delete(client.address + "/api/alert-notifications/" + id)
-	prometheus.GrafanaDeleteLatencyMilliseconds.WithLabelValues(prometheus.TypeAlertNotification).Observe(float64(resp.Cost() / time.Millisecond))
-
+func (client *Client) DeleteAlertNotification(ctx context.Context, id string) error {
+	capabilities, err := client.Capabilities(ctx)
 	if err != nil {
 		return err
 	}
 
-	if !responseIsSuccessOrNotFound(resp) {
-		return errors.New(resp.Response().Status)
+	if capabilities.AlertNotificationUIDRoutes && !isNumericId(id) {
+		return client.deleteGrafanaObject(ctx, "/api/alert-notifications/uid/"+id, prometheus.TypeAlertNotification)
 	}
 
-	return nil
+	return client.deleteGrafanaObject(ctx, "/api/alert-notifications/"+id, prometheus.TypeAlertNotification)
 }
 
-func (client *Client) GetAllAlertNotificationIds() ([]string, error) {
-	var resp *req.Resp
-	var err error
-	var channels []map[string]interface{}
+// GetAlertNotification returns the alert notification with the given id.  The id is interpreted
+// the same way as by PostAlertNotification.
+func (client *Client) GetAlertNotification(ctx context.Context, id string) (*Object, error) {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path := "/api/alert-notifications/" + id
+	if capabilities.AlertNotificationUIDRoutes && !isNumericId(id) {
+		path = "/api/alert-notifications/uid/" + id
+	}
+
+	body, err := client.getGrafanaObject(ctx, path, prometheus.TypeAlertNotification)
+	if err != nil {
+		return nil, err
+	}
+
+	return newObject(body, body)
+}
 
-	// Request existing notification channels
-	if resp, err = req.Get(client.address + "/api/alert-notifications"); err != nil {
+// GetAllAlertNotificationIds returns every alert notification.  Grafana does not paginate this
+// endpoint.
+func (client *Client) GetAllAlertNotificationIds(ctx context.Context) ([]string, error) {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
 		return nil, err
 	}
-	prometheus.GrafanaGetLatencyMilliseconds.WithLabelValues(prometheus.TypeAlertNotification).Observe(float64(resp.Cost() / time.Millisecond))
 
-	if err = resp.ToJSON(&channels); err != nil {
+	channels, err := client.getGrafanaObjects(ctx, "/api/alert-notifications", prometheus.TypeAlertNotification)
+	if err != nil {
 		return nil, err
 	}
 
+	idField := "id"
+	if capabilities.AlertNotificationUIDRoutes {
+		idField = "uid"
+	}
+
 	var ids []string
 
 	for _, channel := range channels {
-		ids = append(ids, fmt.Sprintf("%v", channel["id"]))
+		id, err := getField(channel, idField)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
 	}
 
 	return ids, nil
 }
 
-func (client *Client) PostDataSource(dataSourceJson string, id string) (string, error) {
+// GetAlertNotificationNumericIds returns the numeric id of every alert notification keyed by the
+// id GetAllAlertNotificationIds returns for it.  Objects synced before grafana supported uids
+// still hold the numeric id.
+func (client *Client) GetAlertNotificationNumericIds(ctx context.Context) (map[string]string, error) {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := client.getGrafanaObjects(ctx, "/api/alert-notifications", prometheus.TypeAlertNotification)
+	if err != nil {
+		return nil, err
+	}
+
+	return numericIds(channels, capabilities.AlertNotificationUIDRoutes)
+}
+
+// PostDataSource creates or updates a data source and returns its id.  The id is a uid if grafana
+// supports uid routes for data sources and a numeric id otherwise.
+func (client *Client) PostDataSource(ctx context.Context, dataSourceJson string, id string) (string, error) {
 	var response map[string]interface{}
-	dataSourceJson, err := sanitizeObject(dataSourceJson, false)
+
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	dataSourceJson, err = sanitizeObject(dataSourceJson, false)
 
 	if err != nil {
 		return "", err
 	}
 
 	if id == NO_ID {
-		response, err = client.postGrafanaObject(dataSourceJson, "/api/datasources", prometheus.TypeDataSource)
+		response, err = client.postGrafanaObject(ctx, dataSourceJson, "/api/datasources", prometheus.TypeDataSource)
 
 		if err != nil {
 			return "", err
 		}
 	} else {
-		response, err = client.putGrafanaObject(dataSourceJson, fmt.Sprintf("/api/datasources/%v", id), prometheus.TypeDataSource)
+		path := fmt.Sprintf("/api/datasources/%v", id)
+		if capabilities.DataSourceUIDRoutes && !isNumericId(id) {
+			path = fmt.Sprintf("/api/datasources/uid/%v", id)
+		}
+
+		response, err = client.putGrafanaObject(ctx, dataSourceJson, path, prometheus.TypeDataSource)
 
 		if err != nil {
 			runtime.HandleError(err)
 			prometheus.GrafanaWastedPutTotal.WithLabelValues(prometheus.TypeDataSource).Inc()
 
-			response, err = client.postGrafanaObject(dataSourceJson, "/api/datasources", prometheus.TypeDataSource)
+			response, err = client.postGrafanaObject(ctx, dataSourceJson, "/api/datasources", prometheus.TypeDataSource)
 
 			if err != nil {
 				return "", err
@@ -233,49 +483,129 @@ func (client *Client) PostDataSource(dataSourceJson string, id string) (string,
 		}
 	}
 
+	if capabilities.DataSourceUIDRoutes {
+		// the uid is only returned in the nested data source
+		dataSource, ok := response["datasource"].(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("Map did not have field datasource")
+		}
+
+		return getField(dataSource, "uid")
+	}
+
 	return getField(response, "id")
 }
 
-func (client *Client) DeleteDataSource(id string) error {
-	resp, err := req.Delete(client.address + "/api/datasources/" + id)
-	prometheus.GrafanaDeleteLatencyMilliseconds.WithLabelValues(prometheus.TypeDataSource).Observe(float64(resp.Cost() / time.Millisecond))
-
+func (client *Client) DeleteDataSource(ctx context.Context, id string) error {
+	capabilities, err := client.Capabilities(ctx)
 	if err != nil {
 		return err
 	}
 
-	if !responseIsSuccessOrNotFound(resp) {
-		return errors.New(resp.Response().Status)
+	if capabilities.DataSourceUIDRoutes && !isNumericId(id) {
+		return client.deleteGrafanaObject(ctx, "/api/datasources/uid/"+id, prometheus.TypeDataSource)
 	}
 
-	return nil
+	return client.deleteGrafanaObject(ctx, "/api/datasources/"+id, prometheus.TypeDataSource)
 }
 
-func (client *Client) GetAllDataSourceIds() ([]string, error) {
-	var resp *req.Resp
-	var err error
-	var datasources []map[string]interface{}
+// GetDataSource returns the data source with the given id.  The id is interpreted the same way
+// as by PostDataSource.
+func (client *Client) GetDataSource(ctx context.Context, id string) (*Object, error) {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path := "/api/datasources/" + id
+	if capabilities.DataSourceUIDRoutes && !isNumericId(id) {
+		path = "/api/datasources/uid/" + id
+	}
 
-	// Request existing notification channels
-	if resp, err = req.Get(client.address + "/api/datasources"); err != nil {
+	body, err := client.getGrafanaObject(ctx, path, prometheus.TypeDataSource)
+	if err != nil {
+		return nil, err
+	}
+
+	return newObject(body, body)
+}
+
+// GetAllDataSourceIds returns every data source.  Grafana does not paginate this endpoint.
+func (client *Client) GetAllDataSourceIds(ctx context.Context) ([]string, error) {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
 		return nil, err
 	}
-	prometheus.GrafanaGetLatencyMilliseconds.WithLabelValues(prometheus.TypeDataSource).Observe(float64(resp.Cost() / time.Millisecond))
 
-	if err = resp.ToJSON(&datasources); err != nil {
+	datasources, err := client.getGrafanaObjects(ctx, "/api/datasources", prometheus.TypeDataSource)
+	if err != nil {
 		return nil, err
 	}
 
+	idField := "id"
+	if capabilities.DataSourceUIDRoutes {
+		idField = "uid"
+	}
+
 	var ids []string
 
 	for _, datasource := range datasources {
-		ids = append(ids, fmt.Sprintf("%v", datasource["id"]))
+		id, err := getField(datasource, idField)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// GetDataSourceNumericIds returns the numeric id of every data source keyed by the id
+// GetAllDataSourceIds returns for it.  Objects synced before grafana supported uids still hold
+// the numeric id.
+func (client *Client) GetDataSourceNumericIds(ctx context.Context) (map[string]string, error) {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	datasources, err := client.getGrafanaObjects(ctx, "/api/datasources", prometheus.TypeDataSource)
+	if err != nil {
+		return nil, err
+	}
+
+	return numericIds(datasources, capabilities.DataSourceUIDRoutes)
+}
+
+// numericIds maps the uid of every object to its numeric id if uidRoutes is set.  Otherwise every
+// numeric id maps to itself.
+func numericIds(objects []map[string]interface{}, uidRoutes bool) (map[string]string, error) {
+	idField := "id"
+	if uidRoutes {
+		idField = "uid"
+	}
+
+	ids := make(map[string]string, len(objects))
+
+	for _, object := range objects {
+		id, err := getField(object, idField)
+		if err != nil {
+			return nil, err
+		}
+
+		numericId, err := getField(object, "id")
+		if err != nil {
+			return nil, err
+		}
+
+		ids[id] = numericId
 	}
 
 	return ids, nil
 }
 
-func (client *Client) PostFolder(folderJson string, id string) (string, string, error) {
+func (client *Client) PostFolder(ctx context.Context, folderJson string, id string) (string, string, error) {
 	var response map[string]interface{}
 	folderJson, err := sanitizeObject(folderJson, true)
 
@@ -284,19 +614,19 @@ func (client *Client) PostFolder(folderJson string, id string) (string, string,
 	}
 
 	if id == NO_ID {
-		response, err = client.postGrafanaObject(folderJson, "/api/folders", prometheus.TypeFolder)
+		response, err = client.postGrafanaObject(ctx, folderJson, "/api/folders", prometheus.TypeFolder)
 
 		if err != nil {
 			return "", "", err
 		}
 	} else {
-		response, err = client.putGrafanaObject(folderJson, fmt.Sprintf("/api/folders/%v", id), prometheus.TypeFolder)
+		response, err = client.putGrafanaObject(ctx, folderJson, fmt.Sprintf("/api/folders/%v", id), prometheus.TypeFolder)
 
 		if err != nil {
 			runtime.HandleError(err)
 			prometheus.GrafanaWastedPutTotal.WithLabelValues(prometheus.TypeFolder).Inc()
 
-			response, err = client.postGrafanaObject(folderJson, "/api/folders", prometheus.TypeFolder)
+			response, err = client.postGrafanaObject(ctx, folderJson, "/api/folders", prometheus.TypeFolder)
 
 			if err != nil {
 				return "", "", err
@@ -317,110 +647,489 @@ func (client *Client) PostFolder(folderJson string, id string) (string, string,
 	return uid, id, nil
 }
 
-func (client *Client) DeleteFolder(id string) error {
-	resp, err := req.Delete(client.address + "/api/folders/" + id)
-	prometheus.GrafanaDeleteLatencyMilliseconds.WithLabelValues(prometheus.TypeFolder).Observe(float64(resp.Cost() / time.Millisecond))
+func (client *Client) DeleteFolder(ctx context.Context, id string) error {
+	return client.deleteGrafanaObject(ctx, "/api/folders/"+id, prometheus.TypeFolder)
+}
 
+// GetFolder returns the folder with the given uid.
+func (client *Client) GetFolder(ctx context.Context, uid string) (*Object, error) {
+	body, err := client.getGrafanaObject(ctx, "/api/folders/"+uid, prometheus.TypeFolder)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if !responseIsSuccessOrNotFound(resp) {
-		return errors.New(resp.Response().Status)
+	return newObject(body, body)
+}
+
+// GetAllFolderIds returns the uid of every folder.  Results are paged through so the complete
+// set is returned no matter how many folders exist.
+func (client *Client) GetAllFolderIds(ctx context.Context) ([]string, error) {
+	return client.getPagedGrafanaObjectIds(ctx, "/api/folders", "", "limit", "uid", prometheus.TypeFolder)
+}
+
+// PostOrganization creates or renames an organization and returns its id.  Managing
+// organizations requires server admin credentials.
+func (client *Client) PostOrganization(ctx context.Context, organizationJson string, id string) (string, error) {
+	var response map[string]interface{}
+	organizationJson, err := sanitizeObject(organizationJson, false)
+
+	if err != nil {
+		return "", err
 	}
 
-	return nil
+	if id == NO_ID {
+		response, err = client.postGrafanaObject(ctx, organizationJson, "/api/orgs", prometheus.TypeOrganization)
+
+		if err != nil {
+			return "", err
+		}
+
+		return getField(response, "orgId")
+	}
+
+	// a put only returns a message so the id is kept
+	_, err = client.putGrafanaObject(ctx, organizationJson, fmt.Sprintf("/api/orgs/%v", id), prometheus.TypeOrganization)
+
+	if err != nil {
+		runtime.HandleError(err)
+		prometheus.GrafanaWastedPutTotal.WithLabelValues(prometheus.TypeOrganization).Inc()
+
+		response, err = client.postGrafanaObject(ctx, organizationJson, "/api/orgs", prometheus.TypeOrganization)
+
+		if err != nil {
+			return "", err
+		}
+
+		return getField(response, "orgId")
+	}
+
+	return id, nil
 }
 
-func (client *Client) GetAllFolderIds() ([]string, error) {
-	var resp *req.Resp
-	var err error
-	var folders []map[string]interface{}
+func (client *Client) DeleteOrganization(ctx context.Context, id string) error {
+	return client.deleteGrafanaObject(ctx, "/api/orgs/"+id, prometheus.TypeOrganization)
+}
 
-	// Request existing notification channels
-	if resp, err = req.Get(client.address + "/api/folders"); err != nil {
+// GetOrganization returns the organization with the given id.
+func (client *Client) GetOrganization(ctx context.Context, id string) (*Object, error) {
+	body, err := client.getGrafanaObject(ctx, "/api/orgs/"+id, prometheus.TypeOrganization)
+	if err != nil {
 		return nil, err
 	}
-	prometheus.GrafanaGetLatencyMilliseconds.WithLabelValues(prometheus.TypeFolder).Observe(float64(resp.Cost() / time.Millisecond))
 
-	if err = resp.ToJSON(&folders); err != nil {
+	return newObject(body, body)
+}
+
+// GetAllOrganizationIds returns every organization except the main org, which can not be deleted.
+// Organizations created by hand are returned as well.
+func (client *Client) GetAllOrganizationIds(ctx context.Context) ([]string, error) {
+	organizationIds, err := client.getPagedGrafanaObjectIds(ctx, "/api/orgs", "", "perpage", "id", prometheus.TypeOrganization)
+	if err != nil {
 		return nil, err
 	}
 
 	var ids []string
 
-	for _, folder := range folders {
-		ids = append(ids, fmt.Sprintf("%v", folder["uid"]))
+	for _, id := range organizationIds {
+		if id != mainOrgID {
+			ids = append(ids, id)
+		}
 	}
 
 	return ids, nil
 }
 
+// PostTeam creates or updates a team and returns its id.
+func (client *Client) PostTeam(ctx context.Context, teamJson string, id string) (string, error) {
+	var response map[string]interface{}
+	teamJson, err := sanitizeObject(teamJson, false)
+
+	if err != nil {
+		return "", err
+	}
+
+	if id == NO_ID {
+		response, err = client.postGrafanaObject(ctx, teamJson, "/api/teams", prometheus.TypeTeam)
+
+		if err != nil {
+			return "", err
+		}
+
+		return getField(response, "teamId")
+	}
+
+	// a put only returns a message so the id is kept
+	_, err = client.putGrafanaObject(ctx, teamJson, fmt.Sprintf("/api/teams/%v", id), prometheus.TypeTeam)
+
+	if err != nil {
+		runtime.HandleError(err)
+		prometheus.GrafanaWastedPutTotal.WithLabelValues(prometheus.TypeTeam).Inc()
+
+		response, err = client.postGrafanaObject(ctx, teamJson, "/api/teams", prometheus.TypeTeam)
+
+		if err != nil {
+			return "", err
+		}
+
+		return getField(response, "teamId")
+	}
+
+	return id, nil
+}
+
+func (client *Client) DeleteTeam(ctx context.Context, id string) error {
+	return client.deleteGrafanaObject(ctx, "/api/teams/"+id, prometheus.TypeTeam)
+}
+
+// GetTeam returns the team with the given id.
+func (client *Client) GetTeam(ctx context.Context, id string) (*Object, error) {
+	body, err := client.getGrafanaObject(ctx, "/api/teams/"+id, prometheus.TypeTeam)
+	if err != nil {
+		return nil, err
+	}
+
+	return newObject(body, body)
+}
+
+// GetAllTeamIds returns the id of every team.  Search results are paged through so the complete
+// set is returned no matter how many teams exist.
+func (client *Client) GetAllTeamIds(ctx context.Context) ([]string, error) {
+	return client.getPagedGrafanaObjectIds(ctx, "/api/teams/search", "teams", "perpage", "id", prometheus.TypeTeam)
+}
+
+// SetTeamMembers makes members, a list of user logins or emails, the only members of a team.
+// Users are looked up in the team's organization and must exist.
+func (client *Client) SetTeamMembers(ctx context.Context, id string, members []string) error {
+	userIds, err := client.GetOrgUserIds(ctx)
+	if err != nil {
+		return err
+	}
+
+	desired := make(map[string]bool)
+	for _, member := range members {
+		userId, ok := userIds[strings.ToLower(member)]
+		if !ok {
+			return fmt.Errorf("grafana user %s not found", member)
+		}
+
+		desired[userId] = true
+	}
+
+	path := fmt.Sprintf("/api/teams/%v/members", id)
+
+	current, err := client.getGrafanaObjects(ctx, path, prometheus.TypeTeam)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range current {
+		userId, err := getField(member, "userId")
+		if err != nil {
+			return err
+		}
+
+		if desired[userId] {
+			delete(desired, userId)
+			continue
+		}
+
+		if err := client.deleteGrafanaObject(ctx, path+"/"+userId, prometheus.TypeTeam); err != nil {
+			return err
+		}
+	}
+
+	for userId := range desired {
+		if _, err := client.postGrafanaObject(ctx, fmt.Sprintf(`{"userId": %v}`, userId), path, prometheus.TypeTeam); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetOrgUserIds returns the ids of the users in the organization keyed by their lowercased login
+// and email.
+func (client *Client) GetOrgUserIds(ctx context.Context) (map[string]string, error) {
+	users, err := client.getGrafanaObjects(ctx, "/api/org/users", prometheus.TypeUser)
+	if err != nil {
+		return nil, err
+	}
+
+	userIds := make(map[string]string)
+	for _, user := range users {
+		userId, err := getField(user, "userId")
+		if err != nil {
+			return nil, err
+		}
+
+		userIds[strings.ToLower(fmt.Sprintf("%v", user["login"]))] = userId
+		userIds[strings.ToLower(fmt.Sprintf("%v", user["email"]))] = userId
+	}
+
+	return userIds, nil
+}
+
 //
 // shared
 //
 
-func (client *Client) postGrafanaObject(postJSON string, path string, prometheusType string) (map[string]interface{}, error) {
+func (client *Client) postGrafanaObject(ctx context.Context, postJSON string, path string, prometheusType string) (map[string]interface{}, error) {
+	return client.sendGrafanaObject(ctx, http.MethodPost, postJSON, path, prometheusType, false)
+}
+
+// postIdempotentGrafanaObject is for posts that can be safely repeated, e.g. overwriting a
+// dashboard with a known uid
+func (client *Client) postIdempotentGrafanaObject(ctx context.Context, postJSON string, path string, prometheusType string) (map[string]interface{}, error) {
+	return client.sendGrafanaObject(ctx, http.MethodPost, postJSON, path, prometheusType, true)
+}
+
+func (client *Client) putGrafanaObject(ctx context.Context, putJSON string, path string, prometheusType string) (map[string]interface{}, error) {
+	return client.sendGrafanaObject(ctx, http.MethodPut, putJSON, path, prometheusType, true)
+}
+
+// patchGrafanaObject is not idempotent.  grafana rejects a repeated patch of a versioned object
+func (client *Client) patchGrafanaObject(ctx context.Context, patchJSON string, path string, prometheusType string) (map[string]interface{}, error) {
+	return client.sendGrafanaObject(ctx, http.MethodPatch, patchJSON, path, prometheusType, false)
+}
+
+func (client *Client) sendGrafanaObject(ctx context.Context, method string, body string, path string, prometheusType string, idempotent bool) (map[string]interface{}, error) {
 	var responseBody map[string]interface{}
 
-	header := req.Header{
-		"Content-Type": "application/json",
+	resp, err := client.request(ctx, method, path, body, prometheusType, idempotent)
+	if err != nil {
+		return nil, err
 	}
 
-	resp, err := req.Post(client.address+path, header, postJSON)
-	prometheus.GrafanaPostLatencyMilliseconds.WithLabelValues(prometheusType).Observe(float64(resp.Cost() / time.Millisecond))
+	if !responseIsSuccess(resp) {
+		return nil, newAPIError(resp, method, path)
+	}
+
+	err = resp.ToJSON(&responseBody)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if resp == nil {
-		return nil, errors.New("Error and response are nil")
+	return responseBody, nil
+}
+
+// getGrafanaObject returns the body of a single object
+func (client *Client) getGrafanaObject(ctx context.Context, path string, prometheusType string) ([]byte, error) {
+	resp, err := client.request(ctx, http.MethodGet, path, "", prometheusType, true)
+	if err != nil {
+		return nil, err
 	}
 
 	if !responseIsSuccess(resp) {
-		return nil, errors.New(resp.Response().Status)
+		return nil, newAPIError(resp, http.MethodGet, path)
 	}
 
-	err = resp.ToJSON(&responseBody)
+	return resp.ToBytes()
+}
+
+func (client *Client) getGrafanaObjects(ctx context.Context, path string, prometheusType string) ([]map[string]interface{}, error) {
+	return client.getGrafanaObjectsIn(ctx, path, "", prometheusType)
+}
 
+// getGrafanaObjectsIn returns the objects listed in listField of the response.  An empty
+// listField is for endpoints that respond with the list itself.
+func (client *Client) getGrafanaObjectsIn(ctx context.Context, path string, listField string, prometheusType string) ([]map[string]interface{}, error) {
+	var objects []map[string]interface{}
+
+	resp, err := client.request(ctx, http.MethodGet, path, "", prometheusType, true)
 	if err != nil {
 		return nil, err
 	}
 
-	return responseBody, nil
+	if !responseIsSuccess(resp) {
+		return nil, newAPIError(resp, http.MethodGet, path)
+	}
+
+	if listField == "" {
+		if err = resp.ToJSON(&objects); err != nil {
+			return nil, err
+		}
+
+		return objects, nil
+	}
+
+	// search responses also hold counts and paging fields
+	var response map[string]json.RawMessage
+
+	if err = resp.ToJSON(&response); err != nil {
+		return nil, err
+	}
+
+	list, ok := response[listField]
+	if !ok {
+		return nil, nil
+	}
+
+	if err = json.Unmarshal(list, &objects); err != nil {
+		return nil, err
+	}
+
+	return objects, nil
 }
 
-func (client *Client) putGrafanaObject(putJSON string, path string, prometheusType string) (map[string]interface{}, error) {
-	var responseBody map[string]interface{}
+// getPagedGrafanaObjectIds pages through a list endpoint that supports page and page size
+// parameters and returns idField of every object.  listField and limitParam name the field
+// holding the list, see getGrafanaObjectsIn, and the page size parameter.  Paging stops at the
+// first short page.  A page without any new ids also ends paging so a grafana that ignores the
+// parameters is not requested forever.
+func (client *Client) getPagedGrafanaObjectIds(ctx context.Context, path string, listField string, limitParam string, idField string, prometheusType string) ([]string, error) {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+
+	for page := 1; ; page++ {
+		objects, err := client.getGrafanaObjectsIn(ctx, fmt.Sprintf("%s%s%s=%d&page=%d", path, separator, limitParam, pageSize, page), listField, prometheusType)
+		if err != nil {
+			return nil, err
+		}
+
+		newIds := 0
+
+		for _, object := range objects {
+			id, err := getField(object, idField)
+			if err != nil {
+				return nil, err
+			}
+
+			// objects created or deleted while paging can shift results between pages
+			if seen[id] {
+				continue
+			}
+
+			seen[id] = true
+			ids = append(ids, id)
+			newIds++
+		}
+
+		if len(objects) < pageSize || newIds == 0 {
+			return ids, nil
+		}
+	}
+}
+
+func (client *Client) deleteGrafanaObject(ctx context.Context, path string, prometheusType string) error {
+	resp, err := client.request(ctx, http.MethodDelete, path, "", prometheusType, true)
+	if err != nil {
+		return err
+	}
+
+	if !responseIsSuccessOrNotFound(resp) {
+		return newAPIError(resp, http.MethodDelete, path)
+	}
+
+	return nil
+}
+
+// request sends a request to grafana retrying transient failures according to the client's
+// retry options.  Only idempotent requests are retried unless grafana refused the request
+// outright.  The unsuccessful response or error of the final attempt is returned.
+func (client *Client) request(ctx context.Context, method string, path string, body string, prometheusType string, idempotent bool) (*req.Resp, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := client.send(ctx, method, path, body, prometheusType)
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		wait, retry := client.retry.backoff(attempt, idempotent, resp, err)
+		if !retry {
+			return resp, err
+		}
+
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Response().Status
+		}
+
+		prometheus.GrafanaRetryTotal.WithLabelValues(prometheusType).Inc()
+		klog.V(4).Infof("Retrying grafana %s %s in %v after %s", method, path, wait, reason)
 
-	header := req.Header{
-		"Content-Type": "application/json",
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
 	}
+}
 
-	resp, err := req.Put(client.address+path, header, putJSON)
-	prometheus.GrafanaPutLatencyMilliseconds.WithLabelValues(prometheusType).Observe(float64(resp.Cost() / time.Millisecond))
+// send sends a single request to grafana and records its latency.  The request waits on the
+// client's rate limits and is abandoned if ctx is cancelled or the client's timeouts are
+// exceeded.  The response body is read before returning.
+func (client *Client) send(ctx context.Context, method string, path string, body string, prometheusType string) (*req.Resp, error) {
+	header, err := client.header(ctx)
+	if err != nil {
+		return nil, err
+	}
 
+	release, err := client.limiter.acquire(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
+
+	args := []interface{}{header, ctx}
 
-	if resp == nil {
-		return nil, errors.New("Error and response are nil")
+	if body != "" {
+		header["Content-Type"] = "application/json"
+		args = append(args, body)
 	}
 
-	if !responseIsSuccess(resp) {
-		body, _ := resp.ToString()
-		return nil, errors.New(resp.Response().Status + ": " + body)
+	resp, err := client.req.Do(method, client.address+path, args...)
+	if err != nil {
+		return nil, err
 	}
 
-	err = resp.ToJSON(&responseBody)
+	// read the body while holding the in flight slot.  it is cached on resp for callers
+	if _, err = resp.ToBytes(); err != nil {
+		return nil, err
+	}
+
+	latency := float64(resp.Cost() / time.Millisecond)
+
+	switch method {
+	case http.MethodGet:
+		prometheus.GrafanaGetLatencyMilliseconds.WithLabelValues(prometheusType).Observe(latency)
+	case http.MethodPost:
+		prometheus.GrafanaPostLatencyMilliseconds.WithLabelValues(prometheusType).Observe(latency)
+	case http.MethodPut:
+		prometheus.GrafanaPutLatencyMilliseconds.WithLabelValues(prometheusType).Observe(latency)
+	case http.MethodPatch:
+		prometheus.GrafanaPatchLatencyMilliseconds.WithLabelValues(prometheusType).Observe(latency)
+	case http.MethodDelete:
+		prometheus.GrafanaDeleteLatencyMilliseconds.WithLabelValues(prometheusType).Observe(latency)
+	}
+
+	return resp, nil
+}
+
+func (client *Client) header(ctx context.Context) (req.Header, error) {
+	header := req.Header{}
 
+	if orgID := OrgID(ctx); orgID != "" {
+		header[OrgIDHeader] = orgID
+	}
+
+	authorization, err := client.credentials.Authorization()
 	if err != nil {
 		return nil, err
 	}
 
-	return responseBody, nil
+	if authorization != "" {
+		header["Authorization"] = authorization
+	}
+
+	return header, nil
 }
 
 func responseIsSuccess(resp *req.Resp) bool {
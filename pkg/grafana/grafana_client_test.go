@@ -0,0 +1,136 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newHangingServer returns a server that does not respond until the test finishes
+func newHangingServer() (*httptest.Server, func()) {
+	done := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+
+	return server, func() {
+		close(done)
+		server.Close()
+	}
+}
+
+func TestReadTimeout(t *testing.T) {
+	server, cleanup := newHangingServer()
+	defer cleanup()
+
+	client, err := NewClient(server.URL, ClientOptions{
+		ReadTimeout: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if _, err := client.GetAllFolderIds(context.Background()); err == nil {
+		t.Error("expected request to a hung grafana to time out")
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request took %v to time out", elapsed)
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	server, cleanup := newHangingServer()
+	defer cleanup()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	if err := client.DeleteFolder(ctx, "abc"); err == nil {
+		t.Error("expected cancelled request to fail")
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request took %v to be cancelled", elapsed)
+	}
+}
+
+// newPagingServer serves total dashboards from /api/search honoring the limit and page
+// parameters unless ignorePaging is set
+func newPagingServer(total int, ignorePaging bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		if ignorePaging || limit == 0 {
+			limit, page = 1000, 1
+		}
+
+		var dashboards []map[string]interface{}
+		for i := (page - 1) * limit; i < page*limit && i < total; i++ {
+			dashboards = append(dashboards, map[string]interface{}{
+				"uid": fmt.Sprintf("dashboard-%d", i),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(dashboards)
+	}))
+}
+
+func TestGetAllDashboardIdsPages(t *testing.T) {
+	for _, total := range []int{0, 10, 1000, 2500} {
+		server := newPagingServer(total, false)
+
+		client, err := NewClient(server.URL, ClientOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids, err := client.GetAllDashboardIds(context.Background())
+		server.Close()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(ids) != total {
+			t.Errorf("expected %d dashboards, got %d", total, len(ids))
+		}
+	}
+}
+
+func TestGetAllDashboardIdsIgnoredPaging(t *testing.T) {
+	server := newPagingServer(2500, true)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := client.GetAllDashboardIds(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 1000 {
+		t.Errorf("expected the single page grafana returned, got %d dashboards", len(ids))
+	}
+}
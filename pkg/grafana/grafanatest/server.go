@@ -0,0 +1,811 @@
+// Package grafanatest provides an in memory grafana HTTP API for tests.  It serves the endpoints
+// used by grafana.Client with grafana's id, uid and version semantics so controllers can be run
+// end to end without a grafana deployment.
+package grafanatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultVersion is the grafana version reported by servers created with NewServer
+const DefaultVersion = "6.7.4"
+
+// defaultPageSize is the number of results grafana returns from paged endpoints without a limit
+const defaultPageSize = 1000
+
+type object struct {
+	id       int
+	uid      string
+	folderId int
+	model    map[string]interface{}
+	version  int
+	created  time.Time
+	updated  time.Time
+}
+
+// Server is a grafana HTTP API backed by memory.  It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	version                    string
+	dataSourceUIDRoutes        bool
+	alertNotificationUIDRoutes bool
+	dashboardFolderUID         bool
+
+	lock               sync.Mutex
+	nextId             int
+	dashboards         map[int]*object
+	folders            map[int]*object
+	dataSources        map[int]*object
+	alertNotifications map[int]*object
+
+	failures int
+	status   int
+	requests []string
+}
+
+// NewServer starts a server simulating grafana DefaultVersion
+func NewServer() *Server {
+	return NewServerWithVersion(DefaultVersion)
+}
+
+// NewServerWithVersion starts a server simulating the given grafana version.  Routes that the
+// version does not support return 404 the way the real grafana does.
+func NewServerWithVersion(version string) *Server {
+	s := &Server{
+		version:            version,
+		nextId:             1,
+		dashboards:         make(map[int]*object),
+		folders:            make(map[int]*object),
+		dataSources:        make(map[int]*object),
+		alertNotifications: make(map[int]*object),
+	}
+
+	major := 0
+	if matches := regexp.MustCompile(`^v?(\d+)\.`).FindStringSubmatch(version); matches != nil {
+		major, _ = strconv.Atoi(matches[1])
+	}
+
+	s.alertNotificationUIDRoutes = major >= 7
+	s.dataSourceUIDRoutes = major >= 9
+	s.dashboardFolderUID = major >= 9
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/health", s.handleHealth)
+	mux.HandleFunc("/api/frontend/settings", s.handleFrontendSettings)
+	mux.HandleFunc("/api/search", s.handleSearch)
+	mux.HandleFunc("/api/dashboards/db", s.handlePostDashboard)
+	mux.HandleFunc("/api/dashboards/uid/", s.handleDashboard)
+	mux.HandleFunc("/api/folders", s.handleFolders)
+	mux.HandleFunc("/api/folders/", s.handleFolder)
+	mux.HandleFunc("/api/datasources", s.handleDataSources)
+	mux.HandleFunc("/api/datasources/", s.handleDataSource)
+	mux.HandleFunc("/api/alert-notifications", s.handleAlertNotifications)
+	mux.HandleFunc("/api/alert-notifications/", s.handleAlertNotification)
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+
+		if s.failures > 0 {
+			s.failures--
+			writeMessage(w, s.status, "injected failure")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		mux.ServeHTTP(w, r)
+	}))
+
+	return s
+}
+
+// FailNext makes the next count requests fail with status
+func (s *Server) FailNext(count int, status int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.failures = count
+	s.status = status
+}
+
+// Requests returns every request received so far as "METHOD /path?query"
+func (s *Server) Requests() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+// DashboardUids returns the uids of every dashboard in the server
+func (s *Server) DashboardUids() []string {
+	return s.uids(s.dashboards)
+}
+
+// FolderUids returns the uids of every folder in the server
+func (s *Server) FolderUids() []string {
+	return s.uids(s.folders)
+}
+
+// DataSourceNames returns the names of every data source in the server
+func (s *Server) DataSourceNames() []string {
+	return s.names(s.dataSources)
+}
+
+// AlertNotificationNames returns the names of every alert notification in the server
+func (s *Server) AlertNotificationNames() []string {
+	return s.names(s.alertNotifications)
+}
+
+// Dashboard returns the model of the dashboard with uid and the uid of its folder
+func (s *Server) Dashboard(uid string) (map[string]interface{}, string, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	dashboard := findByUid(s.dashboards, uid)
+	if dashboard == nil {
+		return nil, "", false
+	}
+
+	folderUid := ""
+	if folder, ok := s.folders[dashboard.folderId]; ok {
+		folderUid = folder.uid
+	}
+
+	return dashboard.json(), folderUid, true
+}
+
+func (s *Server) uids(objects map[int]*object) []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var uids []string
+	for _, o := range sorted(objects) {
+		uids = append(uids, o.uid)
+	}
+
+	return uids
+}
+
+func (s *Server) names(objects map[int]*object) []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var names []string
+	for _, o := range sorted(objects) {
+		names = append(names, o.stringField("name"))
+	}
+
+	return names
+}
+
+//
+// health
+//
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"commit":   "grafanatest",
+		"database": "ok",
+		"version":  s.version,
+	})
+}
+
+func (s *Server) handleFrontendSettings(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"buildInfo": map[string]interface{}{
+			"version": s.version,
+		},
+	})
+}
+
+//
+// dashboards
+//
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMessage(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	results := []map[string]interface{}{}
+
+	searchType := r.URL.Query().Get("type")
+
+	if searchType == "" || searchType == "dash-folder" {
+		for _, folder := range sorted(s.folders) {
+			results = append(results, map[string]interface{}{
+				"id":    folder.id,
+				"uid":   folder.uid,
+				"title": folder.stringField("title"),
+				"type":  "dash-folder",
+			})
+		}
+	}
+
+	if searchType == "" || searchType == "dash-db" {
+		for _, dashboard := range sorted(s.dashboards) {
+			result := map[string]interface{}{
+				"id":    dashboard.id,
+				"uid":   dashboard.uid,
+				"title": dashboard.stringField("title"),
+				"type":  "dash-db",
+			}
+
+			if folder, ok := s.folders[dashboard.folderId]; ok {
+				result["folderId"] = folder.id
+				result["folderUid"] = folder.uid
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, page(r, results))
+}
+
+func (s *Server) handlePostDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeMessage(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	var body struct {
+		Dashboard map[string]interface{} `json:"dashboard"`
+		FolderId  int                    `json:"folderId"`
+		FolderUid string                 `json:"folderUid"`
+		Overwrite bool                   `json:"overwrite"`
+	}
+
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	if body.Dashboard == nil {
+		writeMessage(w, http.StatusBadRequest, "bad request data")
+		return
+	}
+
+	model := body.Dashboard
+	if title, _ := model["title"].(string); strings.TrimSpace(title) == "" {
+		writeMessage(w, http.StatusBadRequest, "Dashboard title cannot be empty")
+		return
+	}
+
+	folderId := body.FolderId
+	if s.dashboardFolderUID && body.FolderUid != "" {
+		folder := findByUid(s.folders, body.FolderUid)
+		if folder == nil {
+			writeMessage(w, http.StatusBadRequest, "Folder not found")
+			return
+		}
+
+		folderId = folder.id
+	} else if _, ok := s.folders[folderId]; folderId != 0 && !ok {
+		writeMessage(w, http.StatusBadRequest, "Folder not found")
+		return
+	}
+
+	uid, _ := model["uid"].(string)
+
+	dashboard := findByUid(s.dashboards, uid)
+	if dashboard == nil {
+		dashboard = s.newObject(uid)
+		s.dashboards[dashboard.id] = dashboard
+	} else if !body.Overwrite && versionOf(model) != dashboard.version {
+		writeMessage(w, http.StatusPreconditionFailed, "The dashboard has been changed by someone else")
+		return
+	}
+
+	dashboard.folderId = folderId
+	dashboard.update(model)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":      dashboard.id,
+		"uid":     dashboard.uid,
+		"url":     "/d/" + dashboard.uid,
+		"status":  "success",
+		"version": dashboard.version,
+	})
+}
+
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	uid := strings.TrimPrefix(r.URL.Path, "/api/dashboards/uid/")
+
+	dashboard := findByUid(s.dashboards, uid)
+	if dashboard == nil {
+		writeMessage(w, http.StatusNotFound, "Dashboard not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		meta := dashboard.meta()
+		meta["folderId"] = dashboard.folderId
+		if folder, ok := s.folders[dashboard.folderId]; ok {
+			meta["folderUid"] = folder.uid
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"dashboard": dashboard.json(),
+			"meta":      meta,
+		})
+	case http.MethodDelete:
+		delete(s.dashboards, dashboard.id)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"title":   dashboard.stringField("title"),
+			"message": "Dashboard deleted",
+		})
+	default:
+		writeMessage(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+//
+// folders
+//
+
+func (s *Server) handleFolders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		folders := []map[string]interface{}{}
+		for _, folder := range sorted(s.folders) {
+			folders = append(folders, map[string]interface{}{
+				"id":    folder.id,
+				"uid":   folder.uid,
+				"title": folder.stringField("title"),
+			})
+		}
+
+		writeJSON(w, http.StatusOK, page(r, folders))
+	case http.MethodPost:
+		var model map[string]interface{}
+		if !readJSON(w, r, &model) {
+			return
+		}
+
+		title, _ := model["title"].(string)
+		if strings.TrimSpace(title) == "" {
+			writeMessage(w, http.StatusBadRequest, "folder title cannot be empty")
+			return
+		}
+
+		uid, _ := model["uid"].(string)
+		if findByUid(s.folders, uid) != nil {
+			writeMessage(w, http.StatusConflict, "a folder with the same uid already exists")
+			return
+		}
+
+		if s.folderTitleExists(title, 0) {
+			writeMessage(w, http.StatusConflict, "a folder or dashboard in the general folder with the same name already exists")
+			return
+		}
+
+		folder := s.newObject(uid)
+		folder.update(folderModel(model))
+		s.folders[folder.id] = folder
+
+		writeJSON(w, http.StatusOK, folder.withMeta())
+	default:
+		writeMessage(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+func (s *Server) handleFolder(w http.ResponseWriter, r *http.Request) {
+	uid := strings.TrimPrefix(r.URL.Path, "/api/folders/")
+
+	folder := findByUid(s.folders, uid)
+	if folder == nil {
+		writeMessage(w, http.StatusNotFound, "folder not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, folder.withMeta())
+	case http.MethodPut:
+		var model map[string]interface{}
+		if !readJSON(w, r, &model) {
+			return
+		}
+
+		if overwrite, _ := model["overwrite"].(bool); !overwrite && versionOf(model) != folder.version {
+			writeMessage(w, http.StatusPreconditionFailed, "the folder has been changed by someone else")
+			return
+		}
+
+		title, _ := model["title"].(string)
+		if s.folderTitleExists(title, folder.id) {
+			writeMessage(w, http.StatusConflict, "a folder or dashboard in the general folder with the same name already exists")
+			return
+		}
+
+		folder.update(folderModel(model))
+
+		writeJSON(w, http.StatusOK, folder.withMeta())
+	case http.MethodDelete:
+		delete(s.folders, folder.id)
+
+		// dashboards are deleted with their folder
+		for id, dashboard := range s.dashboards {
+			if dashboard.folderId == folder.id {
+				delete(s.dashboards, id)
+			}
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"title":   folder.stringField("title"),
+			"message": "Folder deleted",
+		})
+	default:
+		writeMessage(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+func (s *Server) folderTitleExists(title string, exceptId int) bool {
+	for _, folder := range s.folders {
+		if folder.id != exceptId && folder.stringField("title") == title {
+			return true
+		}
+	}
+
+	return false
+}
+
+// folderModel keeps the fields grafana stores for a folder
+func folderModel(model map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"title": model["title"],
+	}
+}
+
+//
+// data sources
+//
+
+func (s *Server) handleDataSources(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		dataSources := []map[string]interface{}{}
+		for _, dataSource := range sorted(s.dataSources) {
+			dataSources = append(dataSources, dataSource.json())
+		}
+
+		writeJSON(w, http.StatusOK, dataSources)
+	case http.MethodPost:
+		var model map[string]interface{}
+		if !readJSON(w, r, &model) {
+			return
+		}
+
+		if !s.validName(w, s.dataSources, model, 0, "data source") {
+			return
+		}
+
+		uid, _ := model["uid"].(string)
+		dataSource := s.newObject(uid)
+		dataSource.update(model)
+		s.dataSources[dataSource.id] = dataSource
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"datasource": dataSource.json(),
+			"id":         dataSource.id,
+			"message":    "Datasource added",
+			"name":       dataSource.stringField("name"),
+		})
+	default:
+		writeMessage(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+func (s *Server) handleDataSource(w http.ResponseWriter, r *http.Request) {
+	dataSource, ok := s.lookup(s.dataSources, strings.TrimPrefix(r.URL.Path, "/api/datasources/"), s.dataSourceUIDRoutes)
+	if !ok {
+		writeMessage(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	if dataSource == nil {
+		writeMessage(w, http.StatusNotFound, "Data source not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, dataSource.json())
+	case http.MethodPut:
+		var model map[string]interface{}
+		if !readJSON(w, r, &model) {
+			return
+		}
+
+		if !s.validName(w, s.dataSources, model, dataSource.id, "data source") {
+			return
+		}
+
+		dataSource.update(model)
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"datasource": dataSource.json(),
+			"id":         dataSource.id,
+			"message":    "Datasource updated",
+			"name":       dataSource.stringField("name"),
+		})
+	case http.MethodDelete:
+		delete(s.dataSources, dataSource.id)
+		writeMessage(w, http.StatusOK, "Data source deleted")
+	default:
+		writeMessage(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+//
+// alert notifications
+//
+
+func (s *Server) handleAlertNotifications(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		alertNotifications := []map[string]interface{}{}
+		for _, alertNotification := range sorted(s.alertNotifications) {
+			alertNotifications = append(alertNotifications, alertNotification.withMeta())
+		}
+
+		writeJSON(w, http.StatusOK, alertNotifications)
+	case http.MethodPost:
+		var model map[string]interface{}
+		if !readJSON(w, r, &model) {
+			return
+		}
+
+		if !s.validName(w, s.alertNotifications, model, 0, "alert notification") {
+			return
+		}
+
+		uid, _ := model["uid"].(string)
+		alertNotification := s.newObject(uid)
+		alertNotification.update(model)
+		s.alertNotifications[alertNotification.id] = alertNotification
+
+		writeJSON(w, http.StatusOK, alertNotification.withMeta())
+	default:
+		writeMessage(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+func (s *Server) handleAlertNotification(w http.ResponseWriter, r *http.Request) {
+	alertNotification, ok := s.lookup(s.alertNotifications, strings.TrimPrefix(r.URL.Path, "/api/alert-notifications/"), s.alertNotificationUIDRoutes)
+	if !ok {
+		writeMessage(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	if alertNotification == nil {
+		writeMessage(w, http.StatusNotFound, "Alert notification not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, alertNotification.withMeta())
+	case http.MethodPut:
+		var model map[string]interface{}
+		if !readJSON(w, r, &model) {
+			return
+		}
+
+		if !s.validName(w, s.alertNotifications, model, alertNotification.id, "alert notification") {
+			return
+		}
+
+		alertNotification.update(model)
+
+		writeJSON(w, http.StatusOK, alertNotification.withMeta())
+	case http.MethodDelete:
+		delete(s.alertNotifications, alertNotification.id)
+		writeMessage(w, http.StatusOK, "Notification deleted")
+	default:
+		writeMessage(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+//
+// shared
+//
+
+// lookup finds an object from the remainder of a path, either "<id>" or "uid/<uid>".  ok is
+// false if the route does not exist.
+func (s *Server) lookup(objects map[int]*object, path string, uidRoutes bool) (*object, bool) {
+	if strings.HasPrefix(path, "uid/") {
+		if !uidRoutes {
+			return nil, false
+		}
+
+		return findByUid(objects, strings.TrimPrefix(path, "uid/")), true
+	}
+
+	id, err := strconv.Atoi(path)
+	if err != nil {
+		return nil, false
+	}
+
+	return objects[id], true
+}
+
+// validName writes an error and returns false if the model has no name or another object has
+// the same name
+func (s *Server) validName(w http.ResponseWriter, objects map[int]*object, model map[string]interface{}, exceptId int, kind string) bool {
+	name, _ := model["name"].(string)
+	if name == "" {
+		writeMessage(w, http.StatusBadRequest, "Required field name missing")
+		return false
+	}
+
+	for _, o := range objects {
+		if o.id != exceptId && o.stringField("name") == name {
+			writeMessage(w, http.StatusConflict, fmt.Sprintf("%s with the same name already exists", kind))
+			return false
+		}
+	}
+
+	return true
+}
+
+// newObject allocates the next id.  A uid is generated if one is not provided.
+func (s *Server) newObject(uid string) *object {
+	id := s.nextId
+	s.nextId++
+
+	if uid == "" {
+		uid = fmt.Sprintf("gen%06d", id)
+	}
+
+	return &object{
+		id:      id,
+		uid:     uid,
+		created: time.Now().UTC(),
+	}
+}
+
+func (o *object) update(model map[string]interface{}) {
+	stored := make(map[string]interface{}, len(model))
+	for k, v := range model {
+		switch k {
+		case "id", "uid", "version", "overwrite":
+		default:
+			stored[k] = v
+		}
+	}
+
+	o.model = stored
+	o.version++
+	o.updated = time.Now().UTC()
+}
+
+// json returns the stored model with the fields grafana manages
+func (o *object) json() map[string]interface{} {
+	result := make(map[string]interface{}, len(o.model)+3)
+	for k, v := range o.model {
+		result[k] = v
+	}
+
+	result["id"] = o.id
+	result["uid"] = o.uid
+	result["version"] = o.version
+
+	return result
+}
+
+func (o *object) meta() map[string]interface{} {
+	return map[string]interface{}{
+		"version":   o.version,
+		"created":   o.created.Format(time.RFC3339),
+		"updated":   o.updated.Format(time.RFC3339),
+		"createdBy": "admin",
+		"updatedBy": "admin",
+	}
+}
+
+// withMeta returns json with the metadata merged in the way folders and alert notifications
+// are returned
+func (o *object) withMeta() map[string]interface{} {
+	result := o.json()
+	for k, v := range o.meta() {
+		result[k] = v
+	}
+
+	return result
+}
+
+func (o *object) stringField(field string) string {
+	value, _ := o.model[field].(string)
+	return value
+}
+
+func findByUid(objects map[int]*object, uid string) *object {
+	if uid == "" {
+		return nil
+	}
+
+	for _, o := range objects {
+		if o.uid == uid {
+			return o
+		}
+	}
+
+	return nil
+}
+
+func sorted(objects map[int]*object) []*object {
+	var result []*object
+	for _, o := range objects {
+		result = append(result, o)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].id < result[j].id
+	})
+
+	return result
+}
+
+func versionOf(model map[string]interface{}) int {
+	version, _ := model["version"].(float64)
+	return int(version)
+}
+
+// page applies the limit and page query parameters the way grafana's paged endpoints do
+func page(r *http.Request, results []map[string]interface{}) []map[string]interface{} {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultPageSize
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	start := (page - 1) * limit
+	if start >= len(results) {
+		return []map[string]interface{}{}
+	}
+
+	end := start + limit
+	if end > len(results) {
+		end = len(results)
+	}
+
+	return results[start:end]
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeMessage(w, http.StatusBadRequest, "bad request data")
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeMessage(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
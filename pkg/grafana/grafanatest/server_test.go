@@ -0,0 +1,93 @@
+package grafanatest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/grafana"
+)
+
+func TestClientRoundTrip(t *testing.T) {
+	for _, version := range []string{"5.4.3", DefaultVersion, "9.3.2"} {
+		server := NewServerWithVersion(version)
+
+		client, err := grafana.NewClient(server.URL, grafana.ClientOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx := context.Background()
+
+		folderUid, folderId, err := client.PostFolder(ctx, `{"title": "folder"}`, grafana.NO_ID)
+		if err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+
+		dashboardUid, err := client.PostDashboardWithFolder(ctx, `{"title": "dashboard"}`, folderId, folderUid, grafana.NO_ID)
+		if err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+
+		if _, inFolder, _ := server.Dashboard(dashboardUid); inFolder != folderUid {
+			t.Errorf("%s: expected dashboard in folder %s, got %q", version, folderUid, inFolder)
+		}
+
+		dataSourceId, err := client.PostDataSource(ctx, `{"name": "prometheus", "type": "prometheus"}`, grafana.NO_ID)
+		if err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+
+		if _, err := client.PostDataSource(ctx, `{"name": "prometheus", "type": "prometheus", "url": "http://prometheus"}`, dataSourceId); err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+
+		dataSource, err := client.GetDataSource(ctx, dataSourceId)
+		if err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+
+		if dataSource.Version != 2 {
+			t.Errorf("%s: expected data source to be updated in place, got version %d", version, dataSource.Version)
+		}
+
+		ids, err := client.GetAllDataSourceIds(ctx)
+		if err != nil || len(ids) != 1 || ids[0] != dataSourceId {
+			t.Errorf("%s: expected data source ids [%s], got %v %v", version, dataSourceId, ids, err)
+		}
+
+		if err := client.DeleteFolder(ctx, folderUid); err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+
+		if uids := server.DashboardUids(); len(uids) != 0 {
+			t.Errorf("%s: expected dashboards to be deleted with their folder, got %v", version, uids)
+		}
+
+		server.Close()
+	}
+}
+
+func TestPaging(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client, err := grafana.NewClient(server.URL, grafana.ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 1001; i++ {
+		if _, err := client.PostDashboard(context.Background(), `{"title": "dashboard"}`, grafana.NO_ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids, err := client.GetAllDashboardIds(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 1001 {
+		t.Errorf("expected 1001 dashboards, got %d", len(ids))
+	}
+}
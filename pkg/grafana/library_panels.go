@@ -0,0 +1,210 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+// libraryPanelKind is the kind of library elements that are panels
+const libraryPanelKind = 1
+
+// libraryElement is a library element as grafana returns it
+type libraryElement struct {
+	UID     string `json:"uid"`
+	Version int    `json:"version"`
+	Meta    struct {
+		Created string `json:"created"`
+		Updated string `json:"updated"`
+	} `json:"meta"`
+}
+
+// PostLibraryPanel creates or updates a library panel and returns its uid.  libraryPanelJson has
+// the panel's name and model.  The panel is placed in the folder folderId or, on grafanas that
+// place dashboards by uid, folderUid.  An empty folderId is the General folder.  A library panel
+// that was deleted in grafana is created again with the same uid so dashboards referencing it
+// keep working.
+func (client *Client) PostLibraryPanel(ctx context.Context, libraryPanelJson string, folderId string, folderUid string, uid string) (string, error) {
+	if err := client.requireLibraryPanels(ctx); err != nil {
+		return "", err
+	}
+
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var libraryPanel map[string]interface{}
+
+	if err := json.Unmarshal([]byte(libraryPanelJson), &libraryPanel); err != nil {
+		return "", err
+	}
+
+	libraryPanel["kind"] = libraryPanelKind
+
+	delete(libraryPanel, "folderId")
+	delete(libraryPanel, "folderUid")
+
+	if capabilities.DashboardFolderUID && folderUid != "" {
+		libraryPanel["folderUid"] = folderUid
+	} else {
+		id, err := strconv.Atoi(defaultId(folderId))
+		if err != nil {
+			return "", fmt.Errorf("folder id %q is not numeric", folderId)
+		}
+
+		libraryPanel["folderId"] = id
+	}
+
+	if uid != NO_ID {
+		current, err := client.GetLibraryPanel(ctx, uid)
+
+		// grafana only patches the current version
+		if err == nil {
+			libraryPanel["version"] = current.Version
+
+			return client.sendLibraryPanel(ctx, libraryPanel, uid)
+		}
+
+		if !IsNotFound(err) {
+			return "", err
+		}
+
+		libraryPanel["uid"] = uid
+	} else {
+		delete(libraryPanel, "uid")
+	}
+
+	return client.sendLibraryPanel(ctx, libraryPanel, NO_ID)
+}
+
+// sendLibraryPanel creates a library panel or patches the one with the given uid
+func (client *Client) sendLibraryPanel(ctx context.Context, libraryPanel map[string]interface{}, uid string) (string, error) {
+	libraryPanelJson, err := json.Marshal(libraryPanel)
+	if err != nil {
+		return "", err
+	}
+
+	var response map[string]interface{}
+
+	if uid != NO_ID {
+		response, err = client.patchGrafanaObject(ctx, string(libraryPanelJson), "/api/library-elements/"+url.PathEscape(uid), prometheus.TypeLibraryPanel)
+	} else {
+		response, err = client.postGrafanaObject(ctx, string(libraryPanelJson), "/api/library-elements", prometheus.TypeLibraryPanel)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	result, ok := response["result"].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("library panel response has no result")
+	}
+
+	return getField(result, "uid")
+}
+
+// DeleteLibraryPanel deletes a library panel.  grafana refuses to delete panels that are still
+// used by a dashboard.
+func (client *Client) DeleteLibraryPanel(ctx context.Context, id string) error {
+	return client.deleteGrafanaObject(ctx, "/api/library-elements/"+url.PathEscape(id), prometheus.TypeLibraryPanel)
+}
+
+// GetLibraryPanel returns the library panel with the given uid.  Object.JSON is the library
+// element with its name and model.
+func (client *Client) GetLibraryPanel(ctx context.Context, id string) (*Object, error) {
+	body, err := client.getGrafanaObject(ctx, "/api/library-elements/"+url.PathEscape(id), prometheus.TypeLibraryPanel)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	object, err := newObject(response.Result, response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	var element libraryElement
+	if err := json.Unmarshal(response.Result, &element); err != nil {
+		return nil, err
+	}
+
+	// library elements keep their timestamps in meta
+	object.Created = parseTime(element.Meta.Created)
+	object.Updated = parseTime(element.Meta.Updated)
+
+	return object, nil
+}
+
+// GetAllLibraryPanelIds returns the uid of every library panel.  Nothing is returned if grafana
+// is too old for library panels.
+func (client *Client) GetAllLibraryPanelIds(ctx context.Context) ([]string, error) {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !capabilities.LibraryPanels {
+		return nil, nil
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("/api/library-elements?kind=%d&perPage=%d&page=%d", libraryPanelKind, pageSize, page)
+
+		body, err := client.getGrafanaObject(ctx, path, prometheus.TypeLibraryPanel)
+		if err != nil {
+			return nil, err
+		}
+
+		var response struct {
+			Result struct {
+				Elements []libraryElement `json:"elements"`
+			} `json:"result"`
+		}
+
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, err
+		}
+
+		newIds := 0
+
+		// like getPagedGrafanaObjectIds elements can shift between pages
+		for _, element := range response.Result.Elements {
+			if seen[element.UID] {
+				continue
+			}
+
+			seen[element.UID] = true
+			ids = append(ids, element.UID)
+			newIds++
+		}
+
+		if len(response.Result.Elements) < pageSize || newIds == 0 {
+			return ids, nil
+		}
+	}
+}
+
+// defaultId returns the id of the General folder for an empty folder id
+func defaultId(folderId string) string {
+	if folderId == "" {
+		return "0"
+	}
+
+	return folderId
+}
@@ -0,0 +1,136 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newLibraryPanelServer serves one library panel, "existing" at version 3, and records the body
+// of every change
+func newLibraryPanelServer(version string, changes map[string]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/health":
+			w.Write([]byte(`{"version": "` + version + `"}`))
+		case "GET /api/library-elements/existing":
+			w.Write([]byte(`{"result": {"uid": "existing", "name": "cpu", "version": 3, "model": {}}}`))
+		case "GET /api/library-elements/deleted":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "library element could not be found"}`))
+		default:
+			body, _ := ioutil.ReadAll(r.Body)
+
+			var libraryPanel map[string]interface{}
+			json.Unmarshal(body, &libraryPanel)
+
+			changes[r.Method+" "+r.URL.Path] = libraryPanel
+
+			uid, ok := libraryPanel["uid"].(string)
+			if r.Method == http.MethodPatch {
+				uid = strings.TrimPrefix(r.URL.Path, "/api/library-elements/")
+			} else if !ok {
+				uid = "new"
+			}
+
+			response, _ := json.Marshal(map[string]interface{}{"result": map[string]interface{}{"uid": uid}})
+			w.Write(response)
+		}
+	}))
+}
+
+func TestPostLibraryPanel(t *testing.T) {
+	tests := []struct {
+		name        string
+		uid         string
+		expectedReq string
+		expectedUid string
+		check       func(t *testing.T, posted map[string]interface{})
+	}{
+		{
+			name:        "create",
+			uid:         NO_ID,
+			expectedReq: "POST /api/library-elements",
+			expectedUid: "new",
+			check: func(t *testing.T, posted map[string]interface{}) {
+				if posted["kind"] != float64(libraryPanelKind) || posted["folderId"] != float64(0) {
+					t.Errorf("expected a panel in the General folder, got %v", posted)
+				}
+			},
+		},
+		{
+			name:        "update",
+			uid:         "existing",
+			expectedReq: "PATCH /api/library-elements/existing",
+			expectedUid: "existing",
+			check: func(t *testing.T, posted map[string]interface{}) {
+				if posted["version"] != float64(3) {
+					t.Errorf("expected the current version to be patched, got %v", posted["version"])
+				}
+			},
+		},
+		{
+			name:        "recreate",
+			uid:         "deleted",
+			expectedReq: "POST /api/library-elements",
+			expectedUid: "deleted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := make(map[string]map[string]interface{})
+
+			server := newLibraryPanelServer("8.5.0", changes)
+			defer server.Close()
+
+			client, err := NewClient(server.URL, ClientOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			uid, err := client.PostLibraryPanel(context.Background(), `{"name": "cpu", "model": {"type": "graph"}}`, "", "", tt.uid)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if uid != tt.expectedUid {
+				t.Errorf("expected uid %s, got %s", tt.expectedUid, uid)
+			}
+
+			posted, ok := changes[tt.expectedReq]
+			if !ok || len(changes) != 1 {
+				t.Fatalf("expected %s, got %v", tt.expectedReq, changes)
+			}
+
+			if tt.check != nil {
+				tt.check(t, posted)
+			}
+		})
+	}
+}
+
+func TestPostLibraryPanelRequiresGrafana8(t *testing.T) {
+	server := newLibraryPanelServer("7.5.0", make(map[string]map[string]interface{}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.PostLibraryPanel(context.Background(), `{"name": "cpu", "model": {}}`, "", "", NO_ID)
+	if _, ok := err.(*UnsupportedError); !ok {
+		t.Errorf("expected an unsupported error, got %v", err)
+	}
+
+	if ids, err := client.GetAllLibraryPanelIds(context.Background()); err != nil || ids != nil {
+		t.Errorf("expected no library panels, got %v %v", ids, err)
+	}
+}
@@ -0,0 +1,67 @@
+package grafana
+
+import (
+	"context"
+	"time"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+
+	"golang.org/x/time/rate"
+)
+
+// limiter throttles requests sent to grafana.  A single client is shared by every syncer so
+// this bounds the total load the controller puts on grafana.
+type limiter struct {
+	rateLimiter *rate.Limiter
+	inFlight    chan struct{}
+}
+
+// newLimiter returns a limiter allowing qps requests per second with the given burst and at
+// most maxInFlight concurrent requests.  A qps or maxInFlight of 0 disables that limit.
+func newLimiter(qps float64, burst int, maxInFlight int) *limiter {
+	l := &limiter{}
+
+	if qps > 0 {
+		if burst < 1 {
+			burst = 1
+		}
+
+		l.rateLimiter = rate.NewLimiter(rate.Limit(qps), burst)
+	}
+
+	if maxInFlight > 0 {
+		l.inFlight = make(chan struct{}, maxInFlight)
+	}
+
+	return l
+}
+
+// acquire blocks until a request may be sent.  The returned func must be called when the
+// request completes.
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	start := time.Now()
+
+	prometheus.GrafanaQueuedRequests.Inc()
+	defer prometheus.GrafanaQueuedRequests.Dec()
+
+	if l.rateLimiter != nil {
+		if err := l.rateLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	release := func() {}
+
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+			release = func() { <-l.inFlight }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	prometheus.GrafanaRateLimitWaitMilliseconds.Observe(float64(time.Since(start) / time.Millisecond))
+
+	return release, nil
+}
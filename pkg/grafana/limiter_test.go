@@ -0,0 +1,90 @@
+package grafana
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMaxInFlight(t *testing.T) {
+	var inFlight, maxSeen int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			seen := atomic.LoadInt32(&maxSeen)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxSeen, seen, current) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{
+		MaxInFlight: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := client.GetAllFolderIds(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if seen := atomic.LoadInt32(&maxSeen); seen > 2 {
+		t.Errorf("expected at most 2 concurrent requests, saw %d", seen)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	l := newLimiter(20, 1, 0)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		release, err := l.acquire(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+
+	// the first request uses the burst and the remaining 4 wait 50ms each
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("expected requests to be rate limited, took %v", elapsed)
+	}
+}
+
+func TestLimiterContextCancellation(t *testing.T) {
+	l := newLimiter(0, 0, 1)
+
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := l.acquire(ctx); err == nil {
+		t.Error("expected waiting for a full limiter to be cancelled")
+	}
+}
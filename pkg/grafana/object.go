@@ -0,0 +1,56 @@
+package grafana
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Object is an object as it is currently stored in grafana.  Grafana does not track every field
+// for every type.  Fields it does not return are left as zero values.
+type Object struct {
+	// JSON is the object as returned by grafana
+	JSON string
+
+	Version   int
+	Created   time.Time
+	Updated   time.Time
+	CreatedBy string
+	UpdatedBy string
+}
+
+// objectMetadata is the metadata grafana returns alongside or inside an object
+type objectMetadata struct {
+	Version   int    `json:"version"`
+	Created   string `json:"created"`
+	Updated   string `json:"updated"`
+	CreatedBy string `json:"createdBy"`
+	UpdatedBy string `json:"updatedBy"`
+}
+
+func newObject(objectJSON []byte, metadataJSON []byte) (*Object, error) {
+	var metadata objectMetadata
+
+	if err := json.Unmarshal(metadataJSON, &metadata); err != nil {
+		return nil, err
+	}
+
+	return &Object{
+		JSON:      string(objectJSON),
+		Version:   metadata.Version,
+		Created:   parseTime(metadata.Created),
+		Updated:   parseTime(metadata.Updated),
+		CreatedBy: metadata.CreatedBy,
+		UpdatedBy: metadata.UpdatedBy,
+	}, nil
+}
+
+// parseTime parses a grafana timestamp.  Missing or malformed timestamps are returned as the
+// zero time.
+func parseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
@@ -0,0 +1,76 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetDashboard(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/dashboards/uid/abc" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Dashboard not found"}`))
+			return
+		}
+
+		w.Write([]byte(`{
+			"dashboard": {"uid": "abc", "title": "test", "version": 3},
+			"meta": {"created": "2019-01-02T03:04:05Z", "updated": "2019-02-03T04:05:06+01:00", "createdBy": "admin", "updatedBy": "controller"}
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dashboard, err := client.GetDashboard(context.Background(), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var model map[string]interface{}
+	if err := json.Unmarshal([]byte(dashboard.JSON), &model); err != nil {
+		t.Fatal(err)
+	}
+
+	if model["title"] != "test" || dashboard.Version != 3 || dashboard.CreatedBy != "admin" || dashboard.UpdatedBy != "controller" {
+		t.Errorf("unexpected dashboard %#v", dashboard)
+	}
+
+	if !dashboard.Updated.Equal(time.Date(2019, 2, 3, 3, 5, 6, 0, time.UTC)) {
+		t.Errorf("unexpected updated time %v", dashboard.Updated)
+	}
+
+	if _, err := client.GetDashboard(context.Background(), "missing"); !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestGetDataSource(t *testing.T) {
+	server := newRecordingServer("9.1.0", `{"id": 3, "uid": "abc", "name": "test", "version": 2}`)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataSource, err := client.GetDataSource(context.Background(), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if server.requests[0] != "GET /api/datasources/uid/abc" {
+		t.Errorf("unexpected request %v", server.requests)
+	}
+
+	if dataSource.Version != 2 || !dataSource.Updated.IsZero() {
+		t.Errorf("unexpected data source %#v", dataSource)
+	}
+}
@@ -0,0 +1,24 @@
+package grafana
+
+import "context"
+
+// OrgIDHeader selects the organization a request acts on
+const OrgIDHeader = "X-Grafana-Org-Id"
+
+// mainOrgID is the organization every grafana is created with.  It can not be deleted and is
+// never returned as a managed organization.
+const mainOrgID = "1"
+
+type orgIDKey struct{}
+
+// WithOrgID returns a context whose requests act on the grafana organization orgID.  An empty
+// orgID acts on the current organization of the client's credentials, usually the main org.
+func WithOrgID(ctx context.Context, orgID string) context.Context {
+	return context.WithValue(ctx, orgIDKey{}, orgID)
+}
+
+// OrgID returns the organization requests made with ctx act on
+func OrgID(ctx context.Context) string {
+	orgID, _ := ctx.Value(orgIDKey{}).(string)
+	return orgID
+}
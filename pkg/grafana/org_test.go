@@ -0,0 +1,76 @@
+package grafana
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestOrgIDHeader(t *testing.T) {
+	var lock sync.Mutex
+	headers := make(map[string]string)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		headers[r.URL.Path] = r.Header.Get(OrgIDHeader)
+		lock.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"version": "6.7.4"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.DeleteDashboard(WithOrgID(context.Background(), "4"), "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.DeleteFolder(context.Background(), "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if headers["/api/dashboards/uid/a"] != "4" {
+		t.Errorf("expected org header 4, got %q", headers["/api/dashboards/uid/a"])
+	}
+
+	if headers["/api/folders/b"] != "" {
+		t.Errorf("expected no org header, got %q", headers["/api/folders/b"])
+	}
+}
+
+func TestGetAllOrganizationIdsSkipsMainOrg(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Query().Get("page") != "1" {
+			w.Write([]byte(`[]`))
+			return
+		}
+
+		w.Write([]byte(`[{"id": 1, "name": "Main Org."}, {"id": 2, "name": "team"}]`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := client.GetAllOrganizationIds(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 1 || ids[0] != "2" {
+		t.Errorf("expected only organization 2, got %v", ids)
+	}
+}
@@ -0,0 +1,215 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+// Permission levels grafana grants on folders and dashboards
+const (
+	PermissionView  = 1
+	PermissionEdit  = 2
+	PermissionAdmin = 4
+)
+
+var permissionLevels = map[string]int{
+	"View":  PermissionView,
+	"Edit":  PermissionEdit,
+	"Admin": PermissionAdmin,
+}
+
+// ParsePermission returns the permission level named View, Edit or Admin
+func ParsePermission(name string) (int, error) {
+	level, ok := permissionLevels[name]
+	if !ok {
+		return 0, Invalidf("unknown permission %q.  expected View, Edit or Admin", name)
+	}
+
+	return level, nil
+}
+
+// Permission is a single entry of a folder or dashboard ACL.  Exactly one of TeamID, UserID and
+// Role is set.
+type Permission struct {
+	TeamID     string
+	UserID     string
+	Role       string
+	Permission int
+}
+
+type permissionItem struct {
+	TeamID     int64  `json:"teamId,omitempty"`
+	UserID     int64  `json:"userId,omitempty"`
+	Role       string `json:"role,omitempty"`
+	Permission int    `json:"permission"`
+	Inherited  bool   `json:"inherited,omitempty"`
+}
+
+// SortPermissions orders permissions so ACLs can be compared
+func SortPermissions(permissions []Permission) {
+	sort.Slice(permissions, func(i, j int) bool {
+		a, b := permissions[i], permissions[j]
+
+		if a.Role != b.Role {
+			return a.Role < b.Role
+		}
+		if a.TeamID != b.TeamID {
+			return a.TeamID < b.TeamID
+		}
+		if a.UserID != b.UserID {
+			return a.UserID < b.UserID
+		}
+		return a.Permission < b.Permission
+	})
+}
+
+// GetFolderPermissions returns the ACL of the folder with the given uid
+func (client *Client) GetFolderPermissions(ctx context.Context, uid string) ([]Permission, error) {
+	return client.getPermissions(ctx, fmt.Sprintf("/api/folders/%s/permissions", uid), prometheus.TypeFolder)
+}
+
+// SetFolderPermissions replaces the ACL of the folder with the given uid
+func (client *Client) SetFolderPermissions(ctx context.Context, uid string, permissions []Permission) error {
+	return client.setPermissions(ctx, fmt.Sprintf("/api/folders/%s/permissions", uid), permissions, prometheus.TypeFolder)
+}
+
+// GetDashboardPermissions returns the ACL of the dashboard with the given uid.  Permissions the
+// dashboard inherits from its folder are not included.
+func (client *Client) GetDashboardPermissions(ctx context.Context, uid string) ([]Permission, error) {
+	path, err := client.dashboardPermissionsPath(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.getPermissions(ctx, path, prometheus.TypeDashboard)
+}
+
+// SetDashboardPermissions replaces the ACL of the dashboard with the given uid
+func (client *Client) SetDashboardPermissions(ctx context.Context, uid string, permissions []Permission) error {
+	path, err := client.dashboardPermissionsPath(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	return client.setPermissions(ctx, path, permissions, prometheus.TypeDashboard)
+}
+
+// dashboardPermissionsPath returns the permissions route of a dashboard.  Grafanas without uid
+// routes address dashboard permissions by numeric id which is looked up first.
+func (client *Client) dashboardPermissionsPath(ctx context.Context, uid string) (string, error) {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if capabilities.DashboardPermissionsUID {
+		return fmt.Sprintf("/api/dashboards/uid/%s/permissions", uid), nil
+	}
+
+	id, err := client.dashboardId(ctx, uid)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("/api/dashboards/id/%d/permissions", id), nil
+}
+
+// dashboardId looks up the numeric id of a dashboard for the routes of older grafanas
+func (client *Client) dashboardId(ctx context.Context, uid string) (int64, error) {
+	body, err := client.getGrafanaObject(ctx, "/api/dashboards/uid/"+uid, prometheus.TypeDashboard)
+	if err != nil {
+		return 0, err
+	}
+
+	var response struct {
+		Dashboard struct {
+			ID int64 `json:"id"`
+		} `json:"dashboard"`
+	}
+
+	if err = json.Unmarshal(body, &response); err != nil {
+		return 0, err
+	}
+
+	return response.Dashboard.ID, nil
+}
+
+func (client *Client) getPermissions(ctx context.Context, path string, prometheusType string) ([]Permission, error) {
+	body, err := client.getGrafanaObject(ctx, path, prometheusType)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []permissionItem
+
+	if err = json.Unmarshal(body, &items); err != nil {
+		return nil, err
+	}
+
+	permissions := make([]Permission, 0, len(items))
+
+	for _, item := range items {
+		if item.Inherited {
+			continue
+		}
+
+		permission := Permission{
+			Role:       item.Role,
+			Permission: item.Permission,
+		}
+
+		if item.TeamID != 0 {
+			permission.TeamID = strconv.FormatInt(item.TeamID, 10)
+		}
+
+		if item.UserID != 0 {
+			permission.UserID = strconv.FormatInt(item.UserID, 10)
+		}
+
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, nil
+}
+
+func (client *Client) setPermissions(ctx context.Context, path string, permissions []Permission, prometheusType string) error {
+	items := make([]permissionItem, 0, len(permissions))
+
+	for _, permission := range permissions {
+		item := permissionItem{
+			Role:       permission.Role,
+			Permission: permission.Permission,
+		}
+
+		var err error
+
+		if permission.TeamID != "" {
+			if item.TeamID, err = strconv.ParseInt(permission.TeamID, 10, 64); err != nil {
+				return fmt.Errorf("invalid team id %q: %v", permission.TeamID, err)
+			}
+		}
+
+		if permission.UserID != "" {
+			if item.UserID, err = strconv.ParseInt(permission.UserID, 10, 64); err != nil {
+				return fmt.Errorf("invalid user id %q: %v", permission.UserID, err)
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"items": items})
+	if err != nil {
+		return err
+	}
+
+	// the whole acl is replaced so the post can be safely repeated
+	_, err = client.postIdempotentGrafanaObject(ctx, string(body), path, prometheusType)
+
+	return err
+}
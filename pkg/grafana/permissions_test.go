@@ -0,0 +1,102 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParsePermission(t *testing.T) {
+	for name, expected := range map[string]int{"View": PermissionView, "Edit": PermissionEdit, "Admin": PermissionAdmin} {
+		level, err := ParsePermission(name)
+		if err != nil || level != expected {
+			t.Errorf("%s: expected %d, got %d %v", name, expected, level, err)
+		}
+	}
+
+	if _, err := ParsePermission("Owner"); err == nil {
+		t.Error("expected an error for an unknown permission")
+	}
+}
+
+func TestDashboardPermissionRoutes(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected []string
+	}{
+		// older grafanas need the numeric id of the dashboard
+		{"8.5.0", []string{"GET /api/dashboards/uid/abc", "POST /api/dashboards/id/7/permissions"}},
+		{"9.1.0", []string{"POST /api/dashboards/uid/abc/permissions"}},
+	}
+
+	for _, test := range tests {
+		server := newRecordingServer(test.version, `{"dashboard": {"id": 7, "uid": "abc"}}`)
+
+		client, err := NewClient(server.URL, ClientOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = client.SetDashboardPermissions(context.Background(), "abc", []Permission{{TeamID: "3", Permission: PermissionEdit}})
+		server.Close()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(server.requests) != len(test.expected) {
+			t.Fatalf("%s: expected %v, got %v", test.version, test.expected, server.requests)
+		}
+
+		for i := range test.expected {
+			if server.requests[i] != test.expected[i] {
+				t.Errorf("%s: expected %v, got %v", test.version, test.expected, server.requests)
+			}
+		}
+
+		var body struct {
+			Items []map[string]interface{} `json:"items"`
+		}
+
+		if err := json.Unmarshal([]byte(server.bodies[len(server.bodies)-1]), &body); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(body.Items) != 1 || body.Items[0]["teamId"] != float64(3) || body.Items[0]["permission"] != float64(PermissionEdit) {
+			t.Errorf("%s: unexpected acl %v", test.version, body.Items)
+		}
+	}
+}
+
+func TestGetFolderPermissionsSkipsInherited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"role": "Viewer", "permission": 1},
+			{"teamId": 2, "permission": 2},
+			{"userId": 5, "permission": 4, "inherited": true}
+		]`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	permissions, err := client.GetFolderPermissions(context.Background(), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Permission{
+		{Role: "Viewer", Permission: PermissionView},
+		{TeamID: "2", Permission: PermissionEdit},
+	}
+
+	if len(permissions) != len(expected) || permissions[0] != expected[0] || permissions[1] != expected[1] {
+		t.Errorf("expected %v, got %v", expected, permissions)
+	}
+}
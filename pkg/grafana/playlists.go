@@ -0,0 +1,102 @@
+package grafana
+
+import (
+	"context"
+	"net/url"
+
+	"k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+// PostPlaylist creates or updates a playlist and returns its uid.  A playlist that was deleted in
+// grafana is created again with the same uid.
+func (client *Client) PostPlaylist(ctx context.Context, playlistJson string, uid string) (string, error) {
+	if err := client.requirePlaylists(ctx); err != nil {
+		return "", err
+	}
+
+	playlistJson, err := sanitizeObject(playlistJson, false)
+	if err != nil {
+		return "", err
+	}
+
+	if uid == NO_ID {
+		return client.postPlaylist(ctx, playlistJson)
+	}
+
+	playlistJson, err = setUid(playlistJson, uid)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = client.putGrafanaObject(ctx, playlistJson, "/api/playlists/"+url.PathEscape(uid), prometheus.TypePlaylist)
+
+	if IsNotFound(err) {
+		runtime.HandleError(err)
+		prometheus.GrafanaWastedPutTotal.WithLabelValues(prometheus.TypePlaylist).Inc()
+
+		return client.postPlaylist(ctx, playlistJson)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return uid, nil
+}
+
+func (client *Client) postPlaylist(ctx context.Context, playlistJson string) (string, error) {
+	response, err := client.postGrafanaObject(ctx, playlistJson, "/api/playlists", prometheus.TypePlaylist)
+	if err != nil {
+		return "", err
+	}
+
+	return getField(response, "uid")
+}
+
+func (client *Client) DeletePlaylist(ctx context.Context, id string) error {
+	return client.deleteGrafanaObject(ctx, "/api/playlists/"+url.PathEscape(id), prometheus.TypePlaylist)
+}
+
+// GetPlaylist returns the playlist with the given uid and its items
+func (client *Client) GetPlaylist(ctx context.Context, id string) (*Object, error) {
+	body, err := client.getGrafanaObject(ctx, "/api/playlists/"+url.PathEscape(id), prometheus.TypePlaylist)
+	if err != nil {
+		return nil, err
+	}
+
+	// playlists have no versions or timestamps
+	return newObject(body, []byte("{}"))
+}
+
+// GetAllPlaylistIds returns the uid of every playlist, including playlists created by hand.
+// Nothing is returned if grafana is too old for playlists by uid.
+func (client *Client) GetAllPlaylistIds(ctx context.Context) ([]string, error) {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !capabilities.PlaylistUIDRoutes {
+		return nil, nil
+	}
+
+	playlists, err := client.getGrafanaObjects(ctx, "/api/playlists", prometheus.TypePlaylist)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+
+	for _, playlist := range playlists {
+		id, err := getField(playlist, "uid")
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
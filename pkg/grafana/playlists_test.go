@@ -0,0 +1,111 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// newPlaylistServer serves one playlist, "existing", and records every change
+func newPlaylistServer(version string, changes *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		request := r.Method + " " + r.URL.Path
+
+		switch request {
+		case "GET /api/health":
+			w.Write([]byte(`{"version": "` + version + `"}`))
+			return
+		case "GET /api/playlists":
+			w.Write([]byte(`[{"id": 1, "uid": "existing", "name": "noc", "interval": "5m"}]`))
+			return
+		}
+
+		*changes = append(*changes, request)
+
+		switch request {
+		case "PUT /api/playlists/existing":
+			w.Write([]byte(`{"uid": "existing"}`))
+		case "PUT /api/playlists/deleted":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "playlist not found"}`))
+		default:
+			body, _ := ioutil.ReadAll(r.Body)
+
+			var playlist map[string]interface{}
+			json.Unmarshal(body, &playlist)
+
+			if _, ok := playlist["uid"]; !ok {
+				playlist["uid"] = "new"
+			}
+
+			response, _ := json.Marshal(playlist)
+			w.Write(response)
+		}
+	}))
+}
+
+func TestPostPlaylist(t *testing.T) {
+	tests := []struct {
+		uid             string
+		expectedUid     string
+		expectedChanges []string
+	}{
+		{NO_ID, "new", []string{"POST /api/playlists"}},
+		{"existing", "existing", []string{"PUT /api/playlists/existing"}},
+		{"deleted", "deleted", []string{"PUT /api/playlists/deleted", "POST /api/playlists"}},
+	}
+
+	for _, test := range tests {
+		var changes []string
+
+		server := newPlaylistServer("9.1.0", &changes)
+
+		client, err := NewClient(server.URL, ClientOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		uid, err := client.PostPlaylist(context.Background(), `{"name": "noc", "interval": "5m", "items": []}`, test.uid)
+		server.Close()
+
+		if err != nil {
+			t.Errorf("%q: %v", test.uid, err)
+			continue
+		}
+
+		if uid != test.expectedUid {
+			t.Errorf("%q: expected uid %s, got %s", test.uid, test.expectedUid, uid)
+		}
+
+		if !reflect.DeepEqual(changes, test.expectedChanges) {
+			t.Errorf("%q: expected %v, got %v", test.uid, test.expectedChanges, changes)
+		}
+	}
+}
+
+func TestPlaylistsRequireGrafana91(t *testing.T) {
+	var changes []string
+
+	server := newPlaylistServer("9.0.0", &changes)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.PostPlaylist(context.Background(), `{"name": "noc"}`, NO_ID)
+	if _, ok := err.(*UnsupportedError); !ok {
+		t.Errorf("expected an unsupported error, got %v", err)
+	}
+
+	if ids, err := client.GetAllPlaylistIds(context.Background()); err != nil || ids != nil {
+		t.Errorf("expected no playlists, got %v %v", ids, err)
+	}
+}
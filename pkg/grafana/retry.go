@@ -0,0 +1,103 @@
+package grafana
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/imroc/req"
+)
+
+// RetryOptions configures how failed requests are retried inside the client.  Only requests
+// that are safe to repeat are retried and only if they failed in a way that may be transient.
+type RetryOptions struct {
+	// MaxRetries is the number of retries after the first attempt.  0 disables retries.
+	MaxRetries int
+	// InitialBackoff is the upper bound of the first backoff.  Each retry doubles it.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the backoff.  A Retry-After longer than this is not waited for and the
+	// error is returned instead.
+	MaxBackoff time.Duration
+}
+
+const (
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+)
+
+// backoff returns how long to wait before retrying a request and whether it should be retried
+// at all.  attempt is zero based.
+func (o *RetryOptions) backoff(attempt int, idempotent bool, resp *req.Resp, err error) (time.Duration, bool) {
+	if o == nil || attempt >= o.MaxRetries {
+		return 0, false
+	}
+
+	var statusCode int
+	if err == nil {
+		statusCode = resp.Response().StatusCode
+
+		if responseIsSuccess(resp) || !isRetryableStatus(statusCode) {
+			return 0, false
+		}
+	}
+
+	// a 429 means grafana (or a proxy) refused the request so even a post can be safely resent.
+	// anything else may have been partially processed
+	if !idempotent && statusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	initialBackoff, maxBackoff := o.InitialBackoff, o.MaxBackoff
+	if initialBackoff <= 0 {
+		initialBackoff = defaultInitialBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	if err == nil {
+		if retryAfter, ok := parseRetryAfter(resp.Response().Header.Get("Retry-After")); ok {
+			if retryAfter > maxBackoff {
+				return 0, false
+			}
+
+			return retryAfter, true
+		}
+	}
+
+	// full jitter: a random wait between 0 and the exponential backoff.  this spreads out
+	// requests from every worker that failed at the same time
+	backoff := initialBackoff << uint(attempt)
+	if backoff > maxBackoff || backoff <= 0 {
+		backoff = maxBackoff
+	}
+
+	return time.Duration(rand.Int63n(int64(backoff) + 1)), true
+}
+
+// parseRetryAfter parses a Retry-After header in either delay-seconds or http-date form
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+
+		return wait, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,141 @@
+package grafana
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFlakyServer fails the first failures requests with status and then succeeds
+func newFlakyServer(failures int32, status int, retryAfter string) (*httptest.Server, *int32) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if atomic.AddInt32(&calls, 1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`[]`))
+		} else {
+			w.Write([]byte(`{"id": 1, "uid": "abc"}`))
+		}
+	}))
+
+	return server, &calls
+}
+
+func newRetryTestClient(t *testing.T, address string) *Client {
+	client, err := NewClient(address, ClientOptions{
+		Retry: &RetryOptions{
+			MaxRetries:     3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     10 * time.Millisecond,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+func TestRetriesIdempotentRequests(t *testing.T) {
+	server, calls := newFlakyServer(2, http.StatusServiceUnavailable, "")
+	defer server.Close()
+
+	client := newRetryTestClient(t, server.URL)
+
+	if _, err := client.GetAllFolderIds(context.Background()); err != nil {
+		t.Errorf("expected get to succeed after retries: %v", err)
+	}
+
+	if *calls != 3 {
+		t.Errorf("expected 3 calls but got %d", *calls)
+	}
+}
+
+func TestGivesUpAfterMaxRetries(t *testing.T) {
+	server, calls := newFlakyServer(10, http.StatusBadGateway, "")
+	defer server.Close()
+
+	client := newRetryTestClient(t, server.URL)
+
+	_, err := client.GetAllFolderIds(context.Background())
+	if apiError, ok := err.(*APIError); !ok || apiError.StatusCode != http.StatusBadGateway {
+		t.Errorf("expected the final 502 to be returned but got %v", err)
+	}
+
+	if *calls != 4 {
+		t.Errorf("expected 4 calls but got %d", *calls)
+	}
+}
+
+func TestDoesNotRetryPosts(t *testing.T) {
+	server, calls := newFlakyServer(1, http.StatusServiceUnavailable, "")
+	defer server.Close()
+
+	client := newRetryTestClient(t, server.URL)
+
+	if _, _, err := client.PostFolder(context.Background(), `{"title": "test"}`, NO_ID); err == nil {
+		t.Error("expected post to fail without retrying")
+	}
+
+	if *calls != 1 {
+		t.Errorf("expected 1 call but got %d", *calls)
+	}
+}
+
+func TestRetriesRateLimitedPosts(t *testing.T) {
+	server, calls := newFlakyServer(1, http.StatusTooManyRequests, "0")
+	defer server.Close()
+
+	client := newRetryTestClient(t, server.URL)
+
+	if _, _, err := client.PostFolder(context.Background(), `{"title": "test"}`, NO_ID); err != nil {
+		t.Errorf("expected rate limited post to be retried: %v", err)
+	}
+
+	if *calls != 2 {
+		t.Errorf("expected 2 calls but got %d", *calls)
+	}
+}
+
+func TestDoesNotWaitForLongRetryAfter(t *testing.T) {
+	server, calls := newFlakyServer(1, http.StatusServiceUnavailable, "3600")
+	defer server.Close()
+
+	client := newRetryTestClient(t, server.URL)
+
+	if _, err := client.GetAllFolderIds(context.Background()); err == nil {
+		t.Error("expected request to fail instead of waiting an hour")
+	}
+
+	if *calls != 1 {
+		t.Errorf("expected 1 call but got %d", *calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if wait, ok := parseRetryAfter("5"); !ok || wait != 5*time.Second {
+		t.Errorf("expected 5s but got %v, %t", wait, ok)
+	}
+
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if wait, ok := parseRetryAfter(date); !ok || wait <= 0 || wait > time.Minute {
+		t.Errorf("expected about a minute but got %v, %t", wait, ok)
+	}
+
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Error("expected invalid Retry-After to be ignored")
+	}
+}
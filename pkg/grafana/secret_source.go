@@ -0,0 +1,129 @@
+package grafana
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SecretSource provides the current contents of a secret.  Implementations are expected
+// to pick up changes to the underlying secret so rotated values are used on the next call.
+type SecretSource interface {
+	Data() (map[string][]byte, error)
+}
+
+// DirectorySecretSource reads a secret mounted as a directory.  Every file in the directory
+// is a key.  Files are re-read on every call so updates made by the kubelet are picked up.
+type DirectorySecretSource struct {
+	path string
+}
+
+func NewDirectorySecretSource(path string) *DirectorySecretSource {
+	return &DirectorySecretSource{
+		path: path,
+	}
+}
+
+func (s *DirectorySecretSource) Data() (map[string][]byte, error) {
+	files, err := ioutil.ReadDir(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[string][]byte)
+
+	for _, file := range files {
+		// mounted secrets contain hidden ..data directories and symlinks to them
+		if strings.HasPrefix(file.Name(), "..") {
+			continue
+		}
+
+		filePath := filepath.Join(s.path, file.Name())
+
+		// stat the path to resolve the symlinks the kubelet uses for keys
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		if info.IsDir() {
+			continue
+		}
+
+		contents, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		data[file.Name()] = contents
+	}
+
+	return data, nil
+}
+
+// KubernetesSecretSource watches a single Kubernetes Secret and serves its contents from
+// an informer cache.
+type KubernetesSecretSource struct {
+	namespace string
+	name      string
+
+	secretsLister corelisters.SecretLister
+	secretsSynced cache.InformerSynced
+}
+
+// NewKubernetesSecretSource starts an informer restricted to the named secret.  The informer
+// runs until stopCh is closed.
+func NewKubernetesSecretSource(kubeclientset kubernetes.Interface, namespace string, name string, stopCh <-chan struct{}) *KubernetesSecretSource {
+
+	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeclientset, 0,
+		kubeinformers.WithNamespace(namespace),
+		kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}))
+
+	secretInformer := informerFactory.Core().V1().Secrets()
+
+	source := &KubernetesSecretSource{
+		namespace:     namespace,
+		name:          name,
+		secretsLister: secretInformer.Lister(),
+		secretsSynced: secretInformer.Informer().HasSynced,
+	}
+
+	informerFactory.Start(stopCh)
+
+	return source
+}
+
+func (s *KubernetesSecretSource) Data() (map[string][]byte, error) {
+	if !s.secretsSynced() {
+		return nil, fmt.Errorf("secret %s/%s has not been synced yet", s.namespace, s.name)
+	}
+
+	secret, err := s.secretsLister.Secrets(s.namespace).Get(s.name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("secret %s/%s not found", s.namespace, s.name)
+		}
+
+		return nil, err
+	}
+
+	data := make(map[string][]byte, len(secret.Data))
+
+	for key, value := range secret.Data {
+		data[key] = value
+	}
+
+	return data, nil
+}
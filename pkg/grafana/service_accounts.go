@@ -0,0 +1,215 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/prometheus"
+)
+
+// ServiceAccountTokenPrefix is added to the name of the tokens CreateServiceAccountToken creates.
+// Service accounts have nowhere else to be marked, so only service accounts holding such a token
+// are listed by GetAllServiceAccountIds.  Service accounts made by hand, like the one the
+// controller logs in with, are never deleted.
+const ServiceAccountTokenPrefix = annotationManagedBy + "-"
+
+// PostServiceAccount creates or updates a service account and returns its id.
+// serviceAccountJson has the name, role and isDisabled of the service account.  A service account
+// that was deleted in grafana is created again with a new id.
+func (client *Client) PostServiceAccount(ctx context.Context, serviceAccountJson string, id string) (string, error) {
+	if err := client.requireServiceAccounts(ctx); err != nil {
+		return "", err
+	}
+
+	serviceAccountJson, err := sanitizeObject(serviceAccountJson, false)
+	if err != nil {
+		return "", err
+	}
+
+	if id == NO_ID {
+		return client.postServiceAccount(ctx, serviceAccountJson)
+	}
+
+	_, err = client.patchGrafanaObject(ctx, serviceAccountJson, "/api/serviceaccounts/"+url.PathEscape(id), prometheus.TypeServiceAccount)
+
+	if IsNotFound(err) {
+		runtime.HandleError(err)
+		prometheus.GrafanaWastedPutTotal.WithLabelValues(prometheus.TypeServiceAccount).Inc()
+
+		return client.postServiceAccount(ctx, serviceAccountJson)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (client *Client) postServiceAccount(ctx context.Context, serviceAccountJson string) (string, error) {
+	response, err := client.postGrafanaObject(ctx, serviceAccountJson, "/api/serviceaccounts", prometheus.TypeServiceAccount)
+	if err != nil {
+		return "", err
+	}
+
+	return getField(response, "id")
+}
+
+// DeleteServiceAccount deletes a service account and its tokens
+func (client *Client) DeleteServiceAccount(ctx context.Context, id string) error {
+	return client.deleteGrafanaObject(ctx, "/api/serviceaccounts/"+url.PathEscape(id), prometheus.TypeServiceAccount)
+}
+
+// GetServiceAccount returns the service account with the given id
+func (client *Client) GetServiceAccount(ctx context.Context, id string) (*Object, error) {
+	body, err := client.getGrafanaObject(ctx, "/api/serviceaccounts/"+url.PathEscape(id), prometheus.TypeServiceAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	// service accounts have no versions or timestamps
+	return newObject(body, []byte("{}"))
+}
+
+// GetAllServiceAccountIds returns the id of every service account holding a token created by
+// CreateServiceAccountToken.  External service accounts belong to plugins and are left out.
+// Nothing is returned if grafana is too old for service accounts.
+func (client *Client) GetAllServiceAccountIds(ctx context.Context) ([]string, error) {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !capabilities.ServiceAccounts {
+		return nil, nil
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("/api/serviceaccounts/search?perpage=%d&page=%d", pageSize, page)
+
+		serviceAccounts, err := client.getGrafanaObjectsIn(ctx, path, "serviceAccounts", prometheus.TypeServiceAccount)
+		if err != nil {
+			return nil, err
+		}
+
+		newIds := 0
+
+		for _, serviceAccount := range serviceAccounts {
+			id, err := getField(serviceAccount, "id")
+			if err != nil {
+				return nil, err
+			}
+
+			// service accounts created or deleted while paging can shift results between pages
+			if seen[id] {
+				continue
+			}
+
+			seen[id] = true
+			newIds++
+
+			if external, _ := serviceAccount["isExternal"].(bool); external {
+				continue
+			}
+
+			// the token count saves listing the tokens of service accounts without any
+			if tokens, ok := serviceAccount["tokens"].(float64); ok && tokens == 0 {
+				continue
+			}
+
+			managed, err := client.hasManagedToken(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+
+			if managed {
+				ids = append(ids, id)
+			}
+		}
+
+		if len(serviceAccounts) < pageSize || newIds == 0 {
+			return ids, nil
+		}
+	}
+}
+
+// hasManagedToken returns true if the service account with the given id holds a token created by
+// CreateServiceAccountToken
+func (client *Client) hasManagedToken(ctx context.Context, id string) (bool, error) {
+	tokens, err := client.getGrafanaObjects(ctx, "/api/serviceaccounts/"+url.PathEscape(id)+"/tokens", prometheus.TypeServiceAccount)
+	if err != nil {
+		return false, err
+	}
+
+	for _, token := range tokens {
+		if name, _ := token["name"].(string); strings.HasPrefix(name, ServiceAccountTokenPrefix) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// CreateServiceAccountToken adds a token to a service account and returns the token's id and key.
+// The key is only ever returned here.  A token with secondsToLive 0 never expires.  The token's
+// name is prefixed with ServiceAccountTokenPrefix.
+func (client *Client) CreateServiceAccountToken(ctx context.Context, id string, name string, secondsToLive int64) (string, string, error) {
+	tokenJson, err := json.Marshal(map[string]interface{}{
+		"name":          ServiceAccountTokenPrefix + name,
+		"secondsToLive": secondsToLive,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	response, err := client.postGrafanaObject(ctx, string(tokenJson), "/api/serviceaccounts/"+url.PathEscape(id)+"/tokens", prometheus.TypeServiceAccount)
+	if err != nil {
+		return "", "", err
+	}
+
+	tokenId, err := getField(response, "id")
+	if err != nil {
+		return "", "", err
+	}
+
+	key, ok := response["key"].(string)
+	if !ok {
+		return "", "", fmt.Errorf("service account token %s has no key", tokenId)
+	}
+
+	return tokenId, key, nil
+}
+
+// DeleteServiceAccountToken revokes a token of a service account
+func (client *Client) DeleteServiceAccountToken(ctx context.Context, id string, tokenId string) error {
+	return client.deleteGrafanaObject(ctx, "/api/serviceaccounts/"+url.PathEscape(id)+"/tokens/"+url.PathEscape(tokenId), prometheus.TypeServiceAccount)
+}
+
+// GetServiceAccountTokenIds returns the id of every token of a service account
+func (client *Client) GetServiceAccountTokenIds(ctx context.Context, id string) ([]string, error) {
+	tokens, err := client.getGrafanaObjects(ctx, "/api/serviceaccounts/"+url.PathEscape(id)+"/tokens", prometheus.TypeServiceAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+
+	for _, token := range tokens {
+		tokenId, err := getField(token, "id")
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, tokenId)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,193 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// newServiceAccountServer serves service account 1 and an external service account 3 of a
+// plugin, and records every change
+func newServiceAccountServer(version string, changes *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		request := r.Method + " " + r.URL.Path
+
+		switch request {
+		case "GET /api/health":
+			w.Write([]byte(`{"version": "` + version + `"}`))
+			return
+		case "GET /api/serviceaccounts/search":
+			w.Write([]byte(`{"totalCount": 4, "serviceAccounts": [
+				{"id": 1, "name": "ci", "role": "Viewer", "tokens": 2},
+				{"id": 3, "name": "extsvc-plugin", "role": "None", "isExternal": true, "tokens": 1},
+				{"id": 5, "name": "controller", "role": "Admin", "tokens": 1},
+				{"id": 6, "name": "unused", "role": "Viewer", "tokens": 0}
+			]}`))
+			return
+		case "GET /api/serviceaccounts/1/tokens":
+			w.Write([]byte(`[{"id": 7, "name": "kubernetes-grafana-controller-ci-1"}, {"id": 8, "name": "kubernetes-grafana-controller-ci-2"}]`))
+			return
+		case "GET /api/serviceaccounts/5/tokens":
+			w.Write([]byte(`[{"id": 10, "name": "created by hand"}]`))
+			return
+		}
+
+		*changes = append(*changes, request)
+
+		body, _ := ioutil.ReadAll(r.Body)
+
+		switch request {
+		case "PATCH /api/serviceaccounts/1":
+			w.Write([]byte(`{"message": "Service account updated"}`))
+		case "PATCH /api/serviceaccounts/2":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "service account not found"}`))
+		case "POST /api/serviceaccounts":
+			w.Write([]byte(`{"id": 4, "name": "ci"}`))
+		case "POST /api/serviceaccounts/1/tokens":
+			var token map[string]interface{}
+			json.Unmarshal(body, &token)
+
+			if token["secondsToLive"] != float64(3600) || token["name"] != "kubernetes-grafana-controller-ci-3" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			w.Write([]byte(`{"id": 9, "name": "kubernetes-grafana-controller-ci-3", "key": "glsa_secret"}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+}
+
+func TestPostServiceAccount(t *testing.T) {
+	tests := []struct {
+		id              string
+		expectedId      string
+		expectedChanges []string
+	}{
+		{NO_ID, "4", []string{"POST /api/serviceaccounts"}},
+		{"1", "1", []string{"PATCH /api/serviceaccounts/1"}},
+		// a service account deleted in grafana is created again
+		{"2", "4", []string{"PATCH /api/serviceaccounts/2", "POST /api/serviceaccounts"}},
+	}
+
+	for _, test := range tests {
+		var changes []string
+
+		server := newServiceAccountServer("9.1.0", &changes)
+
+		client, err := NewClient(server.URL, ClientOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		id, err := client.PostServiceAccount(context.Background(), `{"name": "ci", "role": "Viewer"}`, test.id)
+		server.Close()
+
+		if err != nil {
+			t.Errorf("%q: %v", test.id, err)
+			continue
+		}
+
+		if id != test.expectedId {
+			t.Errorf("%q: expected id %s, got %s", test.id, test.expectedId, id)
+		}
+
+		if !reflect.DeepEqual(changes, test.expectedChanges) {
+			t.Errorf("%q: expected changes %v, got %v", test.id, test.expectedChanges, changes)
+		}
+	}
+}
+
+func TestPostServiceAccountUnsupported(t *testing.T) {
+	var changes []string
+
+	server := newServiceAccountServer("9.0.0", &changes)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.PostServiceAccount(context.Background(), `{"name": "ci"}`, NO_ID)
+	if _, ok := err.(*UnsupportedError); !ok {
+		t.Errorf("expected an UnsupportedError, got %v", err)
+	}
+
+	if ids, err := client.GetAllServiceAccountIds(context.Background()); err != nil || ids != nil {
+		t.Errorf("expected no ids, got %v and %v", ids, err)
+	}
+}
+
+func TestGetAllServiceAccountIdsOnlyReturnsManaged(t *testing.T) {
+	var changes []string
+
+	server := newServiceAccountServer("10.3.0", &changes)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := client.GetAllServiceAccountIds(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// external service accounts and service accounts without a token the controller created are left out
+	if !reflect.DeepEqual(ids, []string{"1"}) {
+		t.Errorf("expected ids [1], got %v", ids)
+	}
+
+	if len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+}
+
+func TestServiceAccountTokens(t *testing.T) {
+	var changes []string
+
+	server := newServiceAccountServer("9.1.0", &changes)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenId, key, err := client.CreateServiceAccountToken(context.Background(), "1", "ci-3", 3600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tokenId != "9" || key != "glsa_secret" {
+		t.Errorf("expected token 9 with key glsa_secret, got %s and %s", tokenId, key)
+	}
+
+	ids, err := client.GetServiceAccountTokenIds(context.Background(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(ids, []string{"7", "8"}) {
+		t.Errorf("expected token ids [7 8], got %v", ids)
+	}
+
+	if err := client.DeleteServiceAccountToken(context.Background(), "1", "7"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"POST /api/serviceaccounts/1/tokens", "DELETE /api/serviceaccounts/1/tokens/7"}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected changes %v, got %v", expected, changes)
+	}
+}
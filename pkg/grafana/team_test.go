@@ -0,0 +1,66 @@
+package grafana
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestSetTeamMembers(t *testing.T) {
+	var lock sync.Mutex
+	var changes []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/org/users":
+			w.Write([]byte(`[
+				{"userId": 1, "login": "admin", "email": "admin@localhost"},
+				{"userId": 2, "login": "alice", "email": "alice@example.com"},
+				{"userId": 3, "login": "bob", "email": "bob@example.com"}
+			]`))
+		case "GET /api/teams/4/members":
+			w.Write([]byte(`[{"userId": 1, "login": "admin"}, {"userId": 2, "login": "alice"}]`))
+		default:
+			body, _ := ioutil.ReadAll(r.Body)
+
+			lock.Lock()
+			changes = append(changes, r.Method+" "+r.URL.Path+" "+string(body))
+			lock.Unlock()
+
+			w.Write([]byte(`{"message": "ok"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.SetTeamMembers(context.Background(), "4", []string{"Alice", "bob@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	sort.Strings(changes)
+	expected := []string{
+		"DELETE /api/teams/4/members/1 ",
+		`POST /api/teams/4/members {"userId": 3}`,
+	}
+
+	if len(changes) != len(expected) || changes[0] != expected[0] || changes[1] != expected[1] {
+		t.Errorf("expected %q, got %q", expected, changes)
+	}
+
+	if err := client.SetTeamMembers(context.Background(), "4", []string{"carol"}); err == nil {
+		t.Error("expected an error for an unknown user")
+	}
+}
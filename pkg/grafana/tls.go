@@ -0,0 +1,137 @@
+package grafana
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+)
+
+// Keys read from a TLS secret.  These match the keys used by kubernetes.io/tls Secrets and
+// cert-manager so existing Secrets can be used as is.
+const (
+	TLSCAKey   = "ca.crt"
+	TLSCertKey = "tls.crt"
+	TLSKeyKey  = "tls.key"
+)
+
+// TLSOptions configures how the client validates Grafana and authenticates itself over TLS.
+type TLSOptions struct {
+	// Source provides the CA bundle and the optional client certificate and key.  It is
+	// consulted on every handshake so rotated certificates are picked up.  May be nil.
+	Source SecretSource
+
+	// ServerName overrides the name sent using SNI and used to validate the server certificate.
+	// Defaults to the host in the grafana address.
+	ServerName string
+
+	// InsecureSkipVerify disables validation of the server certificate.
+	InsecureSkipVerify bool
+}
+
+// tlsConfig builds a tls.Config that reads certificates from the options' source on demand.
+// host is the host of the grafana address and is used for validation when no ServerName
+// override is provided.
+func (o *TLSOptions) tlsConfig(host string) *tls.Config {
+	config := &tls.Config{
+		ServerName:         o.ServerName,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+
+	if o.Source == nil {
+		return config
+	}
+
+	serverName := o.ServerName
+	if serverName == "" {
+		serverName = host
+	}
+
+	config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return o.clientCertificate()
+	}
+
+	// Go only supports a static RootCAs pool.  To pick up a rotated CA bundle the standard
+	// verification is disabled and the chain is verified against the current bundle instead.
+	if !o.InsecureSkipVerify {
+		config.InsecureSkipVerify = true
+		config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return o.verifyPeerCertificate(rawCerts, serverName)
+		}
+	}
+
+	return config
+}
+
+func (o *TLSOptions) clientCertificate() (*tls.Certificate, error) {
+	data, err := o.Source.Data()
+	if err != nil {
+		return nil, err
+	}
+
+	certPEM, keyPEM := data[TLSCertKey], data[TLSKeyKey]
+
+	// no client certificate configured.  an empty certificate tells the server we have none
+	if len(certPEM) == 0 && len(keyPEM) == 0 {
+		return &tls.Certificate{}, nil
+	}
+
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("error loading grafana client certificate: %v", err)
+	}
+
+	return &certificate, nil
+}
+
+func (o *TLSOptions) verifyPeerCertificate(rawCerts [][]byte, serverName string) error {
+	if len(rawCerts) == 0 {
+		return errors.New("grafana presented no certificates")
+	}
+
+	roots, err := o.rootCAs()
+	if err != nil {
+		return err
+	}
+
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		certs[i], err = x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		DNSName:       serverName,
+	})
+
+	return err
+}
+
+// rootCAs returns the CA bundle from the source or the system pool if the source has none.
+func (o *TLSOptions) rootCAs() (*x509.CertPool, error) {
+	data, err := o.Source.Data()
+	if err != nil {
+		return nil, err
+	}
+
+	caPEM := data[TLSCAKey]
+	if len(caPEM) == 0 {
+		return x509.SystemCertPool()
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("no certificates found in grafana CA bundle")
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,89 @@
+package grafana
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTLSTestServer() *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[]"))
+	}))
+}
+
+func TestTLSCustomCA(t *testing.T) {
+	server := newTLSTestServer()
+	defer server.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	client, err := NewClient(server.URL, ClientOptions{
+		TLS: &TLSOptions{
+			Source: staticSecretSource{TLSCAKey: caPEM},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.GetAllFolderIds(context.Background()); err != nil {
+		t.Errorf("expected request trusting the custom CA to succeed: %v", err)
+	}
+}
+
+func TestTLSUnknownCA(t *testing.T) {
+	server := newTLSTestServer()
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.GetAllFolderIds(context.Background()); err == nil {
+		t.Error("expected request to a server signed by an unknown CA to fail")
+	}
+}
+
+func TestTLSServerNameMismatch(t *testing.T) {
+	server := newTLSTestServer()
+	defer server.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	client, err := NewClient(server.URL, ClientOptions{
+		TLS: &TLSOptions{
+			Source:     staticSecretSource{TLSCAKey: caPEM},
+			ServerName: "grafana.invalid",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.GetAllFolderIds(context.Background()); err == nil {
+		t.Error("expected request with a mismatched server name to fail")
+	}
+}
+
+func TestTLSInsecureSkipVerify(t *testing.T) {
+	server := newTLSTestServer()
+	defer server.Close()
+
+	client, err := NewClient(server.URL, ClientOptions{
+		TLS: &TLSOptions{
+			InsecureSkipVerify: true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.GetAllFolderIds(context.Background()); err != nil {
+		t.Errorf("expected insecure request to succeed: %v", err)
+	}
+}
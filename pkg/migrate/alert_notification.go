@@ -0,0 +1,237 @@
+// Package migrate converts objects written for legacy grafana alerting to their unified alerting
+// replacements.
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+)
+
+// notifier describes how the settings of a legacy notifier type map to a contact point
+type notifier struct {
+	// contactPointType is the type of the contact point
+	contactPointType string
+	// settings maps legacy setting names to contact point setting names
+	settings map[string]string
+}
+
+// same maps each setting to a contact point setting with the same name
+func same(names ...string) map[string]string {
+	settings := make(map[string]string, len(names))
+
+	for _, name := range names {
+		settings[name] = name
+	}
+
+	return settings
+}
+
+// notifiers are the legacy notifier types that have a contact point type.  Settings that are not
+// listed, like uploadImage and autoResolve, have no equivalent and are reported.
+var notifiers = map[string]notifier{
+	"dingding":                {"dingding", same("url", "msgType")},
+	"discord":                 {"discord", same("url", "content", "avatar_url", "use_discord_username")},
+	"email":                   {"email", same("addresses", "singleEmail")},
+	"googlechat":              {"googlechat", same("url")},
+	"kafka":                   {"kafka", same("kafkaRestProxy", "kafkaTopic")},
+	"LINE":                    {"line", same("token")},
+	"opsgenie":                {"opsgenie", same("apiKey", "apiUrl", "autoClose", "overridePriority", "sendTagsAs")},
+	"pagerduty":               {"pagerduty", same("integrationKey", "severity", "class", "component", "group")},
+	"prometheus-alertmanager": {"prometheus-alertmanager", same("url", "basicAuthUser", "basicAuthPassword")},
+	"pushover":                {"pushover", same("apiToken", "userKey", "device", "priority", "okPriority", "retry", "expire", "sound", "okSound")},
+	"sensugo":                 {"sensugo", same("url", "apikey", "entity", "check", "namespace", "handler", "message")},
+	"slack":                   {"slack", same("url", "recipient", "username", "icon_emoji", "icon_url", "mentionUsers", "mentionGroups", "mentionChannel", "token")},
+	"teams":                   {"teams", same("url")},
+	"telegram":                {"telegram", same("bottoken", "chatid")},
+	"threema":                 {"threema", same("gateway_id", "recipient_id", "api_secret")},
+	"victorops":               {"victorops", same("url")},
+	"webhook":                 {"webhook", same("url", "httpMethod", "username", "password", "maxAlerts")},
+}
+
+// legacyNotification is the json of an AlertNotification
+type legacyNotification struct {
+	Name                  string                 `json:"name"`
+	Type                  string                 `json:"type"`
+	IsDefault             bool                   `json:"isDefault"`
+	SendReminder          bool                   `json:"sendReminder"`
+	Frequency             string                 `json:"frequency"`
+	DisableResolveMessage bool                   `json:"disableResolveMessage"`
+	Settings              map[string]interface{} `json:"settings"`
+	SecureSettings        map[string]interface{} `json:"secureSettings"`
+}
+
+// ContactPoint converts an AlertNotification to a ContactPoint in the same namespace and
+// organization.  Values from secureSettings are moved to a Secret the contact point references.
+// The Secret is nil if there are none.  It returns a message for every setting that could not be
+// mapped.  Settings that are dropped do not make the conversion fail.  Notifier types without a
+// contact point type do.
+func ContactPoint(alertNotification *v1alpha1.AlertNotification) (*v1alpha1.ContactPoint, *corev1.Secret, []string, error) {
+	var legacy legacyNotification
+
+	if err := json.Unmarshal([]byte(alertNotification.Spec.JSON), &legacy); err != nil {
+		return nil, nil, nil, err
+	}
+
+	mapping, ok := notifiers[legacy.Type]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("notifier type %q has no contact point type", legacy.Type)
+	}
+
+	var unmapped []string
+
+	if legacy.IsDefault {
+		unmapped = append(unmapped, "isDefault: make the contact point the receiver of the notification policy instead")
+	}
+
+	if legacy.SendReminder {
+		unmapped = append(unmapped, fmt.Sprintf("sendReminder: set repeatInterval %s on the notification policy routes instead", legacy.Frequency))
+	}
+
+	settings := make(map[string]interface{})
+
+	for _, name := range sortedKeys(legacy.Settings) {
+		contactPointName, ok := mapping.settings[name]
+		if !ok {
+			unmapped = append(unmapped, fmt.Sprintf("settings.%s: %s contact points have no such setting", name, mapping.contactPointType))
+			continue
+		}
+
+		settings[contactPointName] = legacy.Settings[name]
+	}
+
+	secretName := alertNotification.Name + "-secure-settings"
+	secretData := make(map[string]string)
+
+	var secureSettings []v1alpha1.SecureSetting
+
+	for _, name := range sortedKeys(legacy.SecureSettings) {
+		contactPointName, ok := mapping.settings[name]
+		if !ok {
+			unmapped = append(unmapped, fmt.Sprintf("secureSettings.%s: %s contact points have no such setting", name, mapping.contactPointType))
+			continue
+		}
+
+		value, err := secretValue(legacy.SecureSettings[name])
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		secretData[contactPointName] = value
+		secureSettings = append(secureSettings, v1alpha1.SecureSetting{
+			Name: contactPointName,
+			SecretKeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  contactPointName,
+			},
+		})
+	}
+
+	name := legacy.Name
+	if name == "" {
+		name = alertNotification.Name
+	}
+
+	contactPointJson, err := json.Marshal(map[string]interface{}{
+		"name":                  name,
+		"type":                  mapping.contactPointType,
+		"settings":              settings,
+		"disableResolveMessage": legacy.DisableResolveMessage,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	contactPoint := &v1alpha1.ContactPoint{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "ContactPoint",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      alertNotification.Name,
+			Namespace: alertNotification.Namespace,
+			Labels:    alertNotification.Labels,
+		},
+		Spec: v1alpha1.ContactPointSpec{
+			JSON:             string(contactPointJson),
+			OrganizationName: alertNotification.Spec.OrganizationName,
+			SecureSettings:   secureSettings,
+		},
+	}
+
+	if len(secretData) == 0 {
+		return contactPoint, nil, unmapped, nil
+	}
+
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: alertNotification.Namespace,
+			Labels:    alertNotification.Labels,
+		},
+		Type:       corev1.SecretTypeOpaque,
+		StringData: secretData,
+	}
+
+	return contactPoint, secret, unmapped, nil
+}
+
+// secretValue returns a secure setting as the string the contact point controller merges into the
+// settings.  Values that are not strings are stored as json.
+func secretValue(value interface{}) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	valueJson, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(valueJson), nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Manifest returns the yaml of a ContactPoint or Secret without its status, ready to be applied
+func Manifest(object runtime.Object) ([]byte, error) {
+	objectJson, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest map[string]interface{}
+
+	if err := json.Unmarshal(objectJson, &manifest); err != nil {
+		return nil, err
+	}
+
+	delete(manifest, "status")
+
+	if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+
+	return yaml.Marshal(manifest)
+}
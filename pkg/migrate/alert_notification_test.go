@@ -0,0 +1,157 @@
+package migrate
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/joe-elliott/kubernetes-grafana-controller/pkg/apis/grafana/v1alpha1"
+)
+
+func newAlertNotification(name string, notificationJson string) *v1alpha1.AlertNotification {
+	return &v1alpha1.AlertNotification{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "monitoring",
+		},
+		Spec: v1alpha1.AlertNotificationSpec{
+			JSON:             notificationJson,
+			OrganizationName: "ops",
+		},
+	}
+}
+
+func TestContactPointMapsSettings(t *testing.T) {
+	alertNotification := newAlertNotification("slack", `{
+		"name": "Slack alerts",
+		"type": "slack",
+		"isDefault": true,
+		"disableResolveMessage": true,
+		"settings": {"recipient": "#alerts", "uploadImage": true},
+		"secureSettings": {"url": "https://hooks.slack.com/secret"}
+	}`)
+
+	contactPoint, secret, unmapped, err := ContactPoint(alertNotification)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if contactPoint.Name != "slack" || contactPoint.Namespace != "monitoring" || contactPoint.Spec.OrganizationName != "ops" {
+		t.Errorf("expected the contact point to replace the alert notification, got %+v", contactPoint.ObjectMeta)
+	}
+
+	var posted map[string]interface{}
+	if err := json.Unmarshal([]byte(contactPoint.Spec.JSON), &posted); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"name":                  "Slack alerts",
+		"type":                  "slack",
+		"disableResolveMessage": true,
+		"settings": map[string]interface{}{
+			"recipient": "#alerts",
+		},
+	}
+
+	if !reflect.DeepEqual(posted, expected) {
+		t.Errorf("expected %v, got %v", expected, posted)
+	}
+
+	if secret == nil || secret.Name != "slack-secure-settings" || secret.Namespace != "monitoring" ||
+		secret.StringData["url"] != "https://hooks.slack.com/secret" {
+		t.Fatalf("expected the secure settings to be moved to a secret, got %+v", secret)
+	}
+
+	expectedSecureSettings := []v1alpha1.SecureSetting{{
+		Name: "url",
+		SecretKeyRef: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "slack-secure-settings"},
+			Key:                  "url",
+		},
+	}}
+
+	if !reflect.DeepEqual(contactPoint.Spec.SecureSettings, expectedSecureSettings) {
+		t.Errorf("expected the contact point to reference the secret, got %+v", contactPoint.Spec.SecureSettings)
+	}
+
+	reported := strings.Join(unmapped, "\n")
+	for _, setting := range []string{"isDefault", "settings.uploadImage"} {
+		if !strings.Contains(reported, setting+":") {
+			t.Errorf("expected %s to be reported, got %q", setting, unmapped)
+		}
+	}
+
+	if len(unmapped) != 2 {
+		t.Errorf("expected two reported settings, got %q", unmapped)
+	}
+}
+
+func TestContactPointRenamesLineType(t *testing.T) {
+	contactPoint, secret, unmapped, err := ContactPoint(newAlertNotification("line", `{"type": "LINE", "settings": {"token": "abc"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if secret != nil {
+		t.Errorf("expected no secret without secure settings, got %+v", secret)
+	}
+
+	if len(unmapped) != 0 {
+		t.Errorf("expected every setting to be mapped, got %q", unmapped)
+	}
+
+	if !strings.Contains(contactPoint.Spec.JSON, `"type":"line"`) || !strings.Contains(contactPoint.Spec.JSON, `"name":"line"`) {
+		t.Errorf("expected the type to be renamed and the name to default to the object's name, got %s", contactPoint.Spec.JSON)
+	}
+}
+
+func TestContactPointRejectsUnknownType(t *testing.T) {
+	if _, _, _, err := ContactPoint(newAlertNotification("hipchat", `{"type": "hipchat"}`)); err == nil {
+		t.Error("expected an error for a notifier type without a contact point type")
+	}
+}
+
+func TestManifestOmitsStatus(t *testing.T) {
+	contactPoint, _, _, err := ContactPoint(newAlertNotification("email", `{"type": "email", "settings": {"addresses": "ops@example.com"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := Manifest(contactPoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, field := range []string{"status:", "creationTimestamp:"} {
+		if strings.Contains(string(manifest), field) {
+			t.Errorf("expected %s to be omitted, got\n%s", field, manifest)
+		}
+	}
+
+	if !strings.Contains(string(manifest), "kind: ContactPoint") {
+		t.Errorf("expected the manifest to have a kind, got\n%s", manifest)
+	}
+}
+
+func TestManifestOfSecret(t *testing.T) {
+	_, secret, _, err := ContactPoint(newAlertNotification("pagerduty", `{"type": "pagerduty", "secureSettings": {"integrationKey": "abc"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := Manifest(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, field := range []string{"kind: Secret", "integrationKey: abc"} {
+		if !strings.Contains(string(manifest), field) {
+			t.Errorf("expected %s in the manifest, got\n%s", field, manifest)
+		}
+	}
+}
@@ -5,10 +5,26 @@ import "github.com/prometheus/client_golang/prometheus"
 const (
 	namespace = "grafana_controller"
 
-	TypeAlertNotification = "alert-notification"
-	TypeDashboard         = "dashboard"
-	TypeDataSource        = "datasource"
-	TypeFolder            = "folder"
+	TypeAlertNotification  = "alert-notification"
+	TypeAlertRuleGroup     = "alert-rule-group"
+	TypeAnnotation         = "annotation"
+	TypeContactPoint       = "contact-point"
+	TypeDaemonSetRollout   = "daemonset-rollout"
+	TypeDashboard          = "dashboard"
+	TypeDeploymentRollout  = "deployment-rollout"
+	TypeDataSource         = "datasource"
+	TypeEventAnnotation    = "event-annotation"
+	TypeFolder             = "folder"
+	TypeHealth             = "health"
+	TypeLibraryPanel       = "library-panel"
+	TypeMuteTiming         = "mute-timing"
+	TypeNotificationPolicy = "notification-policy"
+	TypeOrganization       = "organization"
+	TypePlaylist           = "playlist"
+	TypeServiceAccount     = "service-account"
+	TypeStatefulSetRollout = "statefulset-rollout"
+	TypeTeam               = "team"
+	TypeUser               = "user"
 )
 
 var (
@@ -73,6 +89,16 @@ var (
 		[]string{"type"},
 	)
 
+	GrafanaPatchLatencyMilliseconds = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  namespace,
+			Name:       "grafana_patch_latency_ms",
+			Help:       "Kubernetes Grafana Controllers Grafana Update Latency (milliseconds)",
+			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+		},
+		[]string{"type"},
+	)
+
 	GrafanaGetLatencyMilliseconds = prometheus.NewSummaryVec(
 		prometheus.SummaryOpts{
 			Namespace:  namespace,
@@ -93,6 +119,32 @@ var (
 		[]string{"type"},
 	)
 
+	GrafanaRetryTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grafana_retry_total",
+			Help:      "Kubernetes Grafana Controllers Grafana Request Retry Total",
+		},
+		[]string{"type"},
+	)
+
+	GrafanaRateLimitWaitMilliseconds = prometheus.NewSummary(
+		prometheus.SummaryOpts{
+			Namespace:  namespace,
+			Name:       "grafana_rate_limit_wait_ms",
+			Help:       "Kubernetes Grafana Controllers Grafana Client Rate Limit Wait (milliseconds)",
+			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+		},
+	)
+
+	GrafanaQueuedRequests = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "grafana_queued_requests",
+			Help:      "Kubernetes Grafana Controllers Grafana Requests Waiting On The Client Rate Limit",
+		},
+	)
+
 	GrafanaWastedPutTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: namespace,
@@ -111,7 +163,11 @@ func init() {
 
 	prometheus.MustRegister(GrafanaPostLatencyMilliseconds)
 	prometheus.MustRegister(GrafanaPutLatencyMilliseconds)
+	prometheus.MustRegister(GrafanaPatchLatencyMilliseconds)
 	prometheus.MustRegister(GrafanaDeleteLatencyMilliseconds)
 	prometheus.MustRegister(GrafanaGetLatencyMilliseconds)
 	prometheus.MustRegister(GrafanaWastedPutTotal)
+	prometheus.MustRegister(GrafanaRetryTotal)
+	prometheus.MustRegister(GrafanaRateLimitWaitMilliseconds)
+	prometheus.MustRegister(GrafanaQueuedRequests)
 }